@@ -1,29 +1,224 @@
 package main
 
 import (
-	"os"
+	"context"
+	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"banking-service/internal/account"
+	"banking-service/internal/accountnumber"
 	"banking-service/internal/api"
+	"banking-service/internal/approval"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/config"
+	"banking-service/internal/fee"
+	"banking-service/internal/fx"
+	"banking-service/internal/hold"
+	"banking-service/internal/interest"
+	"banking-service/internal/ledger"
+	"banking-service/internal/lifecycle"
+	"banking-service/internal/limit"
+	"banking-service/internal/overdraft"
+	"banking-service/internal/payment"
+	"banking-service/internal/scheduler"
+	"banking-service/internal/standingorder"
 	"banking-service/internal/store"
+	"banking-service/internal/transaction"
 )
 
 func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Invalid configuration: " + err.Error())
 	}
 	
-	logger.Info("Starting banking service on port " + port)
+	level, err := logrus.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		logger.Fatal("Invalid LOG_LEVEL: " + cfg.LogLevel)
+	}
+	logger.SetLevel(level)
+	
+	logger.Info("Starting banking service on port " + cfg.Port)
+	
+	repo, err := newRepository(cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialise store: " + err.Error())
+	}
+	
+	rates, err := newRateProvider(cfg)
+	if err != nil {
+		logger.Fatal("Failed to load FX rates: " + err.Error())
+	}
+	
+	fees, err := newFeePolicy(cfg)
+	if err != nil {
+		logger.Fatal("Failed to load fee schedule: " + err.Error())
+	}
+	
+	limits, err := newLimits(cfg)
+	if err != nil {
+		logger.Fatal("Failed to load transaction limits: " + err.Error())
+	}
+	
+	numbers, err := accountnumber.NewService(cfg.AccountBranchCode, cfg.IBANCountryCode, cfg.IBANBankCode)
+	if err != nil {
+		logger.Fatal("Invalid account number configuration: " + err.Error())
+	}
+	
+	payees := beneficiary.NewService(cfg.BeneficiaryCoolingOff, cfg.BeneficiaryCoolingOffLimit, cfg.BeneficiaryRequired)
+	processor := payment.NewProcessor(account.NewServiceWithFees(fees), transaction.NewService(), ledger.NewService(), limits, payees)
+	overdrafts, err := overdraft.NewAccruer(repo, account.NewService(), transaction.NewService(), processor, cfg.OverdraftInterestRate, cfg.OverdraftDailyFee, logger)
+	if err != nil {
+		logger.Fatal("Invalid overdraft configuration: " + err.Error())
+	}
+	
+	rateTable, err := newInterestTable(cfg)
+	if err != nil {
+		logger.Fatal("Failed to load interest rates: " + err.Error())
+	}
+	interestAccruer := interest.NewAccruer(repo, account.NewService(), transaction.NewService(), processor, rateTable)
+	
+	jobs := scheduler.New(scheduler.SystemClock{}, logger)
+	jobs.Every("purge-idempotency-keys", cfg.IdempotencyPurgeInterval, func(ctx context.Context, now time.Time) error {
+		purged, err := repo.PurgeExpiredIdempotencyKeys(ctx, now)
+		if purged > 0 {
+			logger.WithField("purged", purged).Info("Purged expired idempotency keys")
+		}
+		return err
+	})
+	
+	dormancy := lifecycle.NewDormancySweeper(repo, account.NewService(), cfg.DormancyPeriod)
+	jobs.Every("dormancy-sweep", cfg.DormancySweepInterval, func(ctx context.Context, now time.Time) error {
+		swept, err := dormancy.Run(ctx, now)
+		if swept > 0 {
+			logger.WithField("accounts", swept).Info("Marked dormant accounts")
+		}
+		return err
+	})
+	
+	jobs.Every("purge-fx-quotes", cfg.FXQuotePurgeInterval, func(ctx context.Context, now time.Time) error {
+		purged, err := repo.PurgeExpiredQuotes(ctx, now)
+		if purged > 0 {
+			logger.WithField("purged", purged).Info("Purged expired FX quotes")
+		}
+		return err
+	})
 	
-	store := store.NewStore()
-	server := api.NewServer(port, logger, store)
+	holdExpirer := lifecycle.NewHoldExpirer(repo, account.NewService(), hold.NewService(cfg.HoldTTL), logger)
+	jobs.Every("expire-holds", cfg.HoldExpiryInterval, func(ctx context.Context, now time.Time) error {
+		expired, err := holdExpirer.Run(ctx, now)
+		if expired > 0 {
+			logger.WithField("holds", expired).Info("Expired stale holds")
+		}
+		return err
+	})
+	
+	jobs.Every("accrue-overdraft-charges", cfg.OverdraftAccrualInterval, func(ctx context.Context, now time.Time) error {
+		charged, err := overdrafts.Run(ctx, now)
+		if charged > 0 {
+			logger.WithField("accounts", charged).Info("Charged overdrawn accounts")
+		}
+		return err
+	})
+	
+	jobs.Every("accrue-interest", cfg.InterestAccrualInterval, func(ctx context.Context, now time.Time) error {
+		accrued, err := interestAccruer.Run(ctx, now)
+		if accrued > 0 {
+			logger.WithField("accounts", accrued).Info("Accrued interest")
+		}
+		return err
+	})
+	
+	standingOrders := lifecycle.NewStandingOrderRunner(repo, transaction.NewService(), standingorder.NewService(cfg.StandingOrderMaxRetries, cfg.StandingOrderRetryDelay), processor)
+	jobs.Every("run-standing-orders", cfg.StandingOrderInterval, func(ctx context.Context, now time.Time) error {
+		paid, err := standingOrders.Run(ctx, now)
+		if paid > 0 {
+			logger.WithField("payments", paid).Info("Paid standing orders")
+		}
+		return err
+	})
+	
+	approvalExpirer := lifecycle.NewApprovalExpirer(repo, approval.NewService(cfg.ApprovalTTL))
+	jobs.Every("expire-approvals", cfg.ApprovalExpiryInterval, func(ctx context.Context, now time.Time) error {
+		expired, err := approvalExpirer.Run(ctx, now)
+		if expired > 0 {
+			logger.WithField("approvals", expired).Info("Expired pending approvals")
+		}
+		return err
+	})
+	
+	transfers := payment.NewDispatcher(repo, processor, logger, payment.DispatcherOptions{
+		Workers:      cfg.TransferWorkers,
+		QueueSize:    cfg.TransferQueueSize,
+		MaxAttempts:  cfg.TransferMaxAttempts,
+		RetryBackoff: cfg.TransferRetryBackoff,
+	})
+	jobs.Every("requeue-pending-transfers", cfg.TransferRecoveryInterval, func(ctx context.Context, now time.Time) error {
+		requeued, err := transfers.Recover(ctx)
+		if requeued > 0 {
+			logger.WithField("transfers", requeued).Debug("Requeued pending transfers")
+		}
+		return err
+	})
+	
+	jobs.Start(context.Background())
+	transfers.Start(context.Background())
+	
+	server := api.NewServer(cfg, logger, repo, rates, fees, limits, numbers, payees, transfers)
 	
 	if err := server.Start(); err != nil {
 		logger.Fatal("Server failed to start: " + err.Error())
 	}
-} 
\ No newline at end of file
+}
+
+func newRepository(cfg *config.Config) (store.Repository, error) {
+	switch cfg.StoreBackend {
+	case config.StoreBackendMemory:
+		return store.NewMemoryStore(), nil
+	case config.StoreBackendFile:
+		return store.OpenFileStore(cfg.StoreDir, store.FileStoreOptions{
+			SnapshotInterval:  cfg.SnapshotInterval,
+			SnapshotThreshold: cfg.SnapshotThreshold,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORE_BACKEND %q", cfg.StoreBackend)
+	}
+}
+
+func newRateProvider(cfg *config.Config) (fx.RateProvider, error) {
+	if cfg.FXRatesFile != "" {
+		return fx.LoadFileProvider(cfg.FXRatesFile)
+	}
+	
+	rates, err := fx.ParseRates(cfg.FXRates)
+	if err != nil {
+		return nil, err
+	}
+	return fx.NewStaticProvider(rates)
+}
+
+func newInterestTable(cfg *config.Config) (interest.Table, error) {
+	if cfg.InterestRatesFile == "" {
+		return nil, nil
+	}
+	return interest.LoadTable(cfg.InterestRatesFile)
+}
+
+func newFeePolicy(cfg *config.Config) (account.FeePolicy, error) {
+	if cfg.FeeScheduleFile == "" {
+		return nil, nil
+	}
+	return fee.LoadSchedule(cfg.FeeScheduleFile)
+}
+
+func newLimits(cfg *config.Config) ([]limit.Limit, error) {
+	if cfg.LimitsFile == "" {
+		return nil, nil
+	}
+	return limit.LoadLimits(cfg.LimitsFile)
+}
@@ -1,24 +1,140 @@
 package account
 
 import (
+	"strconv"
 	"time"
 
+	"banking-service/internal/limit"
 	"banking-service/pkg/errors"
+	"banking-service/pkg/money"
 
 	"github.com/google/uuid"
 )
 
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusFrozen  Status = "frozen"
+	StatusDormant Status = "dormant"
+	StatusClosed  Status = "closed"
+)
+
+// Type is the account product, which decides how the account earns
+// interest.
+type Type string
+
+const (
+	TypeSavings      Type = "savings"
+	TypeCurrent      Type = "current"
+	TypeFixedDeposit Type = "fixed_deposit"
+)
+
+func ValidType(t Type) bool {
+	switch t {
+	case TypeSavings, TypeCurrent, TypeFixedDeposit:
+		return true
+	}
+	return false
+}
+
+// transitions lists the statuses each status may move to. Accounts stored
+// before statuses existed have an empty status and are treated as active.
+var transitions = map[Status][]Status{
+	StatusActive:  {StatusFrozen, StatusDormant, StatusClosed},
+	StatusFrozen:  {StatusActive},
+	StatusDormant: {StatusActive, StatusFrozen, StatusClosed},
+	StatusClosed:  {},
+}
+
 type Account struct {
-	ID           string    `json:"id"`
-	CustomerName string    `json:"owner_name"`
-	Balance      int64     `json:"balance"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             string    `json:"id"`
+	Number         string    `json:"account_number,omitempty"`
+	IBAN           string    `json:"iban,omitempty"`
+	CustomerName   string    `json:"owner_name"`
+	CustomerID     string    `json:"customer_id,omitempty"`
+	Balance        int64     `json:"balance"`
+	HeldAmount     int64     `json:"held_amount"`
+	Type           Type      `json:"type"`
+	OverdraftLimit int64     `json:"overdraft_limit"`
+	Currency       string    `json:"currency"`
+	Status         Status    `json:"status"`
+	Version        int64     `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+
+	// OverdraftChargedAt is when overdraft charges were last posted.
+	OverdraftChargedAt time.Time `json:"overdraft_charged_at,omitempty"`
+
+	// AccruedInterest is interest earned but not yet paid, as a decimal
+	// number of minor units, accrued for each day up to and including
+	// InterestAccruedThrough.
+	AccruedInterest        string    `json:"accrued_interest,omitempty"`
+	InterestAccruedThrough time.Time `json:"interest_accrued_through,omitempty"`
+
+	// Limits are the account's own transaction limits, which take the place
+	// of the configured defaults for the same type and period.
+	Limits []limit.Limit `json:"limits,omitempty"`
+
+	// Holders are the customers on the account, starting with the primary
+	// holder, and Mandate is how many of them must approve a payment.
+	Holders []Holder `json:"holders,omitempty"`
+	Mandate Mandate  `json:"mandate,omitempty"`
+
+	// ParentID is set on pots, named accounts that ring-fence money from
+	// their parent account.
+	ParentID string `json:"parent_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// CurrentCurrency returns the account currency, defaulting accounts created
+// before currencies existed to money.DefaultCurrency.
+func (a *Account) CurrentCurrency() string {
+	if a.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return a.Currency
+}
+
+// BalanceMoney returns the balance in the account's currency.
+func (a *Account) BalanceMoney() money.Money {
+	return money.Money{Amount: a.Balance, Currency: a.CurrentCurrency()}
+}
+
+// AvailableBalance is what the account can spend: its balance plus any
+// overdraft, less what active holds reserve.
+func (a *Account) AvailableBalance() int64 {
+	return a.Balance + a.OverdraftLimit - a.HeldAmount
+}
+
+// CurrentType returns the account type, treating accounts created before
+// types existed as current accounts.
+func (a *Account) CurrentType() Type {
+	if a.Type == "" {
+		return TypeCurrent
+	}
+	return a.Type
 }
 
+func (a *Account) CurrentStatus() Status {
+	if a.Status == "" {
+		return StatusActive
+	}
+	return a.Status
+}
+
+// CreateAccountRequest opens an account. With a CustomerID the account
+// belongs to that customer and CustomerName defaults to the customer's name.
+// Branch is the branch code the account number is allocated in.
 type CreateAccountRequest struct {
 	CustomerName   string `json:"customer_name"`
+	CustomerID     string `json:"customer_id,omitempty"`
 	InitialBalance int64  `json:"initial_balance"`
+	Currency       string `json:"currency,omitempty"`
+	Type           Type   `json:"type,omitempty"`
+	OverdraftLimit int64  `json:"overdraft_limit,omitempty"`
+	Branch         string `json:"branch,omitempty"`
 }
 
 type CreateAccountResponse struct {
@@ -29,12 +145,42 @@ type CreateAccountResponse struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
-type Service struct{}
+// Operation is a kind of money movement the bank may charge a fee for.
+type Operation string
+
+const (
+	OperationWithdrawal Operation = "withdrawal"
+	OperationTransfer   Operation = "transfer"
+)
+
+// FeePolicy prices the fee for moving amount out of an account. The fee is
+// in the account's currency.
+type FeePolicy interface {
+	Fee(account *Account, op Operation, amount int64) int64
+}
+
+type Service struct {
+	fees FeePolicy
+}
 
 func NewService() *Service {
 	return &Service{}
 }
 
+// NewServiceWithFees returns a service that charges fees on withdrawals and
+// transfers according to fees.
+func NewServiceWithFees(fees FeePolicy) *Service {
+	return &Service{fees: fees}
+}
+
+// Fee returns what Withdraw or Transfer would charge on top of amount.
+func (s *Service) Fee(account *Account, op Operation, amount int64) int64 {
+	if s.fees == nil || amount <= 0 {
+		return 0
+	}
+	return s.fees.Fee(account, op, amount)
+}
+
 func (s *Service) CreateAccount(req CreateAccountRequest) (*Account, error) {
 	if req.CustomerName == "" {
 		return nil, &errors.ErrInvalidCustomerName{Name: req.CustomerName}
@@ -44,15 +190,45 @@ func (s *Service) CreateAccount(req CreateAccountRequest) (*Account, error) {
 		return nil, &errors.ErrInvalidInitialBalance{Balance: req.InitialBalance}
 	}
 
+	if req.OverdraftLimit < 0 {
+		return nil, &errors.ErrInvalidParameter{Name: "overdraft_limit", Value: strconv.FormatInt(req.OverdraftLimit, 10)}
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	if err := money.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	accountType := req.Type
+	if accountType == "" {
+		accountType = TypeCurrent
+	}
+	if !ValidType(accountType) {
+		return nil, &errors.ErrInvalidParameter{Name: "type", Value: string(accountType)}
+	}
+
 	accountID := uuid.New().String()
 	now := time.Now()
 	
 	account := &Account{
 		ID:           accountID,
 		CustomerName: req.CustomerName,
+		CustomerID:   req.CustomerID,
 		Balance:      req.InitialBalance,
+		Currency:     currency,
+		Type:         accountType,
+		Status:       StatusActive,
 		CreatedAt:    now,
 		UpdatedAt:    now,
+
+		LastActivityAt: now,
+		OverdraftLimit: req.OverdraftLimit,
+	}
+	if req.CustomerID != "" {
+		account.Holders = []Holder{{CustomerID: req.CustomerID, Role: RolePrimary, AddedAt: now}}
 	}
 
 	return account, nil
@@ -65,20 +241,143 @@ func (s *Service) ValidateAccount(account *Account) error {
 	return nil
 }
 
+// ValidateCurrency checks that an amount given in currency can be applied to
+// the account. An empty currency means the account's own.
+func (s *Service) ValidateCurrency(account *Account, currency string) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+
+	if currency != "" && currency != account.CurrentCurrency() {
+		return &errors.ErrCurrencyMismatch{
+			AccountID: account.ID,
+			Expected:  account.CurrentCurrency(),
+			Actual:    currency,
+		}
+	}
+	return nil
+}
+
+// Transition moves the account to the given status if the lifecycle allows
+// it.
+func (s *Service) Transition(account *Account, to Status) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+
+	from := account.CurrentStatus()
+	allowed := false
+	for _, candidate := range transitions[from] {
+		if candidate == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return &errors.ErrInvalidStatusTransition{
+			AccountID: account.ID,
+			From:      string(from),
+			To:        string(to),
+		}
+	}
+
+	account.Status = to
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *Service) Freeze(account *Account) error {
+	return s.Transition(account, StatusFrozen)
+}
+
+func (s *Service) Unfreeze(account *Account) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() != StatusFrozen {
+		return &errors.ErrInvalidStatusTransition{
+			AccountID: account.ID,
+			From:      string(account.CurrentStatus()),
+			To:        string(StatusActive),
+		}
+	}
+	return s.Transition(account, StatusActive)
+}
+
+// Close closes the account. Only accounts with a zero balance can be closed.
+func (s *Service) Close(account *Account) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.Balance != 0 {
+		return &errors.ErrAccountBalanceNotZero{AccountID: account.ID, Balance: account.Balance}
+	}
+	if account.HeldAmount != 0 {
+		return &errors.ErrAccountHasHolds{AccountID: account.ID, Held: account.HeldAmount}
+	}
+	return s.Transition(account, StatusClosed)
+}
+
+// IsDormant reports whether an active account has gone without any
+// transaction for at least the given period. Pots are left untouched by
+// design and never go dormant.
+func (s *Service) IsDormant(account *Account, now time.Time, after time.Duration) bool {
+	if account.CurrentStatus() != StatusActive || account.IsPot() {
+		return false
+	}
+
+	last := account.LastActivityAt
+	if last.IsZero() {
+		last = account.CreatedAt
+	}
+	return !now.Before(last.Add(after))
+}
+
+// canDebit rejects debits from accounts that are not active.
+func (s *Service) canDebit(account *Account) error {
+	switch account.CurrentStatus() {
+	case StatusFrozen:
+		return &errors.ErrAccountFrozen{AccountID: account.ID}
+	case StatusDormant:
+		return &errors.ErrAccountDormant{AccountID: account.ID}
+	case StatusClosed:
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	return nil
+}
+
+// canCredit rejects credits to frozen or closed accounts. Dormant accounts
+// can still receive money.
+func (s *Service) canCredit(account *Account) error {
+	switch account.CurrentStatus() {
+	case StatusFrozen:
+		return &errors.ErrAccountFrozen{AccountID: account.ID}
+	case StatusClosed:
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	return nil
+}
+
 func (s *Service) CanWithdraw(account *Account, amount int64) error {
 	if err := s.ValidateAccount(account); err != nil {
 		return err
 	}
 
+	if err := s.canDebit(account); err != nil {
+		return err
+	}
+
 	if amount <= 0 {
 		return &errors.ErrInvalidAmount{Amount: amount}
 	}
 
-	if account.Balance < amount {
+	if account.AvailableBalance() < amount {
 		return &errors.ErrInsufficientFunds{
 			AccountID: account.ID,
 			Balance:   account.Balance,
+			Available: account.AvailableBalance(),
 			Amount:    amount,
+			Currency:  account.CurrentCurrency(),
 		}
 	}
 
@@ -89,27 +388,277 @@ func (s *Service) Deposit(account *Account, amount int64) error {
 	if err := s.ValidateAccount(account); err != nil {
 		return err
 	}
+	if err := notPot(account); err != nil {
+		return err
+	}
+
+	if err := s.canCredit(account); err != nil {
+		return err
+	}
 
 	if amount <= 0 {
 		return &errors.ErrInvalidAmount{Amount: amount}
 	}
 
+	now := time.Now()
 	account.Balance += amount
-	account.UpdatedAt = time.Now()
+	account.UpdatedAt = now
+	account.LastActivityAt = now
+
+	// A customer paying in is enough to bring a dormant account back.
+	if account.CurrentStatus() == StatusDormant {
+		account.Status = StatusActive
+	}
 	return nil
 }
 
-func (s *Service) Withdraw(account *Account, amount int64) error {
+// Withdraw takes amount and any withdrawal fee out of the account, and
+// returns the fee.
+func (s *Service) Withdraw(account *Account, amount int64) (int64, error) {
+	if err := notPot(account); err != nil {
+		return 0, err
+	}
+
+	fee := s.Fee(account, OperationWithdrawal, amount)
+	if err := s.debit(account, amount+fee); err != nil {
+		return 0, err
+	}
+	return fee, nil
+}
+
+// ReturnDeposit takes back (part of) an earlier deposit. No fee is charged.
+func (s *Service) ReturnDeposit(account *Account, amount int64) error {
+	return s.debit(account, amount)
+}
+
+func (s *Service) debit(account *Account, amount int64) error {
 	if err := s.CanWithdraw(account, amount); err != nil {
 		return err
 	}
 
+	now := time.Now()
+	account.Balance -= amount
+	account.UpdatedAt = now
+	account.LastActivityAt = now
+	return nil
+}
+
+// SetOverdraftLimit changes how far the account may go below zero. The new
+// limit must still cover the current balance.
+func (s *Service) SetOverdraftLimit(account *Account, limit int64) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() == StatusClosed {
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	if limit < 0 {
+		return &errors.ErrInvalidParameter{Name: "overdraft_limit", Value: strconv.FormatInt(limit, 10)}
+	}
+	if account.Balance+limit < 0 {
+		return &errors.ErrOverdraftLimitTooLow{AccountID: account.ID, Limit: limit, Balance: account.Balance}
+	}
+
+	account.OverdraftLimit = limit
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// LinkCustomer makes the customer with customerID the account's primary
+// holder, in place of any previous one, and takes the customer's name as the
+// owner name.
+func (s *Service) LinkCustomer(account *Account, customerID, customerName string) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() == StatusClosed {
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+
+	now := time.Now()
+	holders := []Holder{{CustomerID: customerID, Role: RolePrimary, AddedAt: now}}
+	for _, holder := range account.CurrentHolders() {
+		switch {
+		case holder.CustomerID == customerID:
+			holders[0].AddedAt = holder.AddedAt
+		case holder.Role != RolePrimary:
+			holders = append(holders, holder)
+		}
+	}
+	if err := checkMandate(account, account.CurrentMandate(), holders); err != nil {
+		return err
+	}
+
+	account.CustomerID = customerID
+	account.CustomerName = customerName
+	account.Holders = holders
+	account.UpdatedAt = now
+	return nil
+}
+
+// Charge takes a bank charge such as overdraft interest from the account.
+// Charges are not customer activity, so they may go beyond the overdraft
+// limit and do not affect dormancy.
+func (s *Service) Charge(account *Account, amount int64) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() == StatusClosed {
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	if amount <= 0 {
+		return &errors.ErrInvalidAmount{Amount: amount}
+	}
+
 	account.Balance -= amount
 	account.UpdatedAt = time.Now()
 	return nil
 }
 
-func (s *Service) Transfer(fromAccount, toAccount *Account, amount int64) error {
+// SetLimits replaces the account's own transaction limits.
+func (s *Service) SetLimits(account *Account, limits []limit.Limit) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() == StatusClosed {
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	if err := limit.Validate(limits); err != nil {
+		return err
+	}
+
+	account.Limits = limits
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// PayInterest credits interest the account has earned. Like Charge it is not
+// customer activity, and it is paid into frozen accounts too.
+func (s *Service) PayInterest(account *Account, amount int64) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() == StatusClosed {
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	if amount <= 0 {
+		return &errors.ErrInvalidAmount{Amount: amount}
+	}
+
+	account.Balance += amount
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// Reserve sets amount of the available balance aside for a hold.
+func (s *Service) Reserve(account *Account, amount int64) error {
+	if err := notPot(account); err != nil {
+		return err
+	}
+	if err := s.CanWithdraw(account, amount); err != nil {
+		return err
+	}
+
+	account.HeldAmount += amount
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// ReleaseReserved returns a held amount to the available balance.
+func (s *Service) ReleaseReserved(account *Account, amount int64) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if amount <= 0 || amount > account.HeldAmount {
+		return &errors.ErrInvalidAmount{Amount: amount}
+	}
+
+	account.HeldAmount -= amount
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// CaptureReserved settles captured of a hold of held as a withdrawal and
+// releases the remainder.
+func (s *Service) CaptureReserved(account *Account, held, captured int64) error {
+	if err := s.ReleaseReserved(account, held); err != nil {
+		return err
+	}
+	if err := s.debit(account, captured); err != nil {
+		account.HeldAmount += held
+		return err
+	}
+	return nil
+}
+
+// Transfer moves amount between the accounts and takes any transfer fee from
+// fromAccount, returning the fee.
+func (s *Service) Transfer(fromAccount, toAccount *Account, amount int64) (int64, error) {
+	if err := s.validateTransfer(fromAccount, toAccount); err != nil {
+		return 0, err
+	}
+	if err := notPot(fromAccount, toAccount); err != nil {
+		return 0, err
+	}
+
+	if fromAccount.CurrentCurrency() != toAccount.CurrentCurrency() {
+		return 0, &errors.ErrCurrencyMismatch{
+			AccountID: toAccount.ID,
+			Expected:  fromAccount.CurrentCurrency(),
+			Actual:    toAccount.CurrentCurrency(),
+		}
+	}
+
+	fee := s.Fee(fromAccount, OperationTransfer, amount)
+	if err := s.move(fromAccount, toAccount, amount+fee, amount); err != nil {
+		return 0, err
+	}
+	return fee, nil
+}
+
+// TransferConverted moves conv.Source out of fromAccount and conv.Target into
+// toAccount, for transfers between accounts in different currencies. The fee
+// is charged on the source amount.
+func (s *Service) TransferConverted(fromAccount, toAccount *Account, conv money.Conversion) (int64, error) {
+	if err := s.validateTransfer(fromAccount, toAccount); err != nil {
+		return 0, err
+	}
+	if err := notPot(fromAccount, toAccount); err != nil {
+		return 0, err
+	}
+
+	if err := s.ValidateCurrency(fromAccount, conv.Source.Currency); err != nil {
+		return 0, err
+	}
+	if err := s.ValidateCurrency(toAccount, conv.Target.Currency); err != nil {
+		return 0, err
+	}
+	if conv.Target.Amount <= 0 {
+		return 0, &errors.ErrInvalidAmount{Amount: conv.Target.Amount}
+	}
+	if err := conv.Validate(); err != nil {
+		return 0, err
+	}
+
+	fee := s.Fee(fromAccount, OperationTransfer, conv.Source.Amount)
+	if err := s.move(fromAccount, toAccount, conv.Source.Amount+fee, conv.Target.Amount); err != nil {
+		return 0, err
+	}
+	return fee, nil
+}
+
+// ReturnTransfer moves money back from toAccount to fromAccount to undo (part
+// of) an earlier transfer between them: debit leaves toAccount and credit
+// arrives in fromAccount, in their own currencies. The amounts come from the
+// original transfer, so no conversion is re-validated.
+func (s *Service) ReturnTransfer(fromAccount, toAccount *Account, debit, credit int64) error {
+	if err := s.validateTransfer(toAccount, fromAccount); err != nil {
+		return err
+	}
+	return s.move(toAccount, fromAccount, debit, credit)
+}
+
+func (s *Service) validateTransfer(fromAccount, toAccount *Account) error {
 	if err := s.ValidateAccount(fromAccount); err != nil {
 		return err
 	}
@@ -123,21 +672,30 @@ func (s *Service) Transfer(fromAccount, toAccount *Account, amount int64) error
 			ToAccountID:   toAccount.ID,
 		}
 	}
+	return nil
+}
 
-	if amount <= 0 {
-		return &errors.ErrInvalidAmount{Amount: amount}
+func (s *Service) move(fromAccount, toAccount *Account, debit, credit int64) error {
+	if debit <= 0 {
+		return &errors.ErrInvalidAmount{Amount: debit}
+	}
+
+	if err := s.CanWithdraw(fromAccount, debit); err != nil {
+		return err
 	}
 
-	if err := s.CanWithdraw(fromAccount, amount); err != nil {
+	if err := s.canCredit(toAccount); err != nil {
 		return err
 	}
 
-	fromAccount.Balance -= amount
-	toAccount.Balance += amount
+	fromAccount.Balance -= debit
+	toAccount.Balance += credit
 	
 	now := time.Now()
 	fromAccount.UpdatedAt = now
 	toAccount.UpdatedAt = now
+	fromAccount.LastActivityAt = now
+	toAccount.LastActivityAt = now
 
 	return nil
-} 
\ No newline at end of file
+}
\ No newline at end of file
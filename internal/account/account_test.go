@@ -2,8 +2,10 @@ package account
 
 import (
 	"testing"
+	"time"
 
 	"banking-service/pkg/errors"
+	"banking-service/pkg/money"
 )
 
 func TestNewService(t *testing.T) {
@@ -401,7 +403,7 @@ func TestWithdraw(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			originalBalance := tt.account.Balance
-			err := service.Withdraw(tt.account, tt.amount)
+			_, err := service.Withdraw(tt.account, tt.amount)
 
 			if tt.wantErr {
 				if err == nil {
@@ -518,7 +520,7 @@ func TestTransfer(t *testing.T) {
 			fromOriginalBalance := tt.fromAccount.Balance
 			toOriginalBalance := tt.toAccount.Balance
 
-			err := service.Transfer(tt.fromAccount, tt.toAccount, tt.amount)
+			_, err := service.Transfer(tt.fromAccount, tt.toAccount, tt.amount)
 
 			if tt.wantErr {
 				if err == nil {
@@ -550,4 +552,263 @@ func TestTransfer(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+
+func TestTransition(t *testing.T) {
+	service := NewService()
+
+	tests := []struct {
+		name    string
+		from    Status
+		to      Status
+		wantErr bool
+	}{
+		{name: "active_to_frozen", from: StatusActive, to: StatusFrozen},
+		{name: "frozen_to_active", from: StatusFrozen, to: StatusActive},
+		{name: "active_to_dormant", from: StatusActive, to: StatusDormant},
+		{name: "dormant_to_closed", from: StatusDormant, to: StatusClosed},
+		{name: "legacy_empty_to_frozen", from: "", to: StatusFrozen},
+		{name: "frozen_to_closed", from: StatusFrozen, to: StatusClosed, wantErr: true},
+		{name: "closed_to_active", from: StatusClosed, to: StatusActive, wantErr: true},
+		{name: "active_to_active", from: StatusActive, to: StatusActive, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &Account{ID: "test-id", CustomerName: "Kavya", Status: tt.from}
+			err := service.Transition(account, tt.to)
+
+			if tt.wantErr {
+				if _, ok := err.(*errors.ErrInvalidStatusTransition); !ok {
+					t.Errorf("Transition() error = %v, want *errors.ErrInvalidStatusTransition", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Transition() unexpected error = %v", err)
+				return
+			}
+			if account.Status != tt.to {
+				t.Errorf("Transition() status = %v, want %v", account.Status, tt.to)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	service := NewService()
+
+	account := &Account{ID: "test-id", CustomerName: "Harish", Balance: 100, Status: StatusActive}
+	if _, ok := service.Close(account).(*errors.ErrAccountBalanceNotZero); !ok {
+		t.Error("Close() expected *errors.ErrAccountBalanceNotZero for funded account")
+	}
+
+	account.Balance = 0
+	if err := service.Close(account); err != nil {
+		t.Errorf("Close() unexpected error = %v", err)
+	}
+	if account.Status != StatusClosed {
+		t.Errorf("Close() status = %v, want %v", account.Status, StatusClosed)
+	}
+}
+
+func TestStatusRestrictions(t *testing.T) {
+	service := NewService()
+
+	frozen := &Account{ID: "frozen-id", CustomerName: "Rekha", Balance: 1000, Status: StatusFrozen}
+	if _, ok := service.Deposit(frozen, 100).(*errors.ErrAccountFrozen); !ok {
+		t.Error("Deposit() expected *errors.ErrAccountFrozen")
+	}
+	if _, ok := errorOf(service.Withdraw(frozen, 100)).(*errors.ErrAccountFrozen); !ok {
+		t.Error("Withdraw() expected *errors.ErrAccountFrozen")
+	}
+
+	closed := &Account{ID: "closed-id", CustomerName: "Rekha", Status: StatusClosed}
+	active := &Account{ID: "active-id", CustomerName: "Rekha", Balance: 1000, Status: StatusActive}
+	if _, ok := errorOf(service.Transfer(active, closed, 100)).(*errors.ErrAccountClosed); !ok {
+		t.Error("Transfer() expected *errors.ErrAccountClosed for closed destination")
+	}
+	if active.Balance != 1000 {
+		t.Errorf("Transfer() balance = %v, want 1000", active.Balance)
+	}
+
+	dormant := &Account{ID: "dormant-id", CustomerName: "Rekha", Balance: 1000, Status: StatusDormant}
+	if _, ok := errorOf(service.Withdraw(dormant, 100)).(*errors.ErrAccountDormant); !ok {
+		t.Error("Withdraw() expected *errors.ErrAccountDormant")
+	}
+	if err := service.Deposit(dormant, 100); err != nil {
+		t.Errorf("Deposit() unexpected error = %v", err)
+	}
+	if dormant.Status != StatusActive {
+		t.Errorf("Deposit() status = %v, want %v", dormant.Status, StatusActive)
+	}
+}
+
+func TestIsDormant(t *testing.T) {
+	service := NewService()
+	now := time.Now()
+
+	account := &Account{ID: "test-id", Status: StatusActive, LastActivityAt: now.Add(-40 * 24 * time.Hour)}
+	if !service.IsDormant(account, now, 30*24*time.Hour) {
+		t.Error("IsDormant() = false, want true for account idle longer than the period")
+	}
+	if service.IsDormant(account, now, 60*24*time.Hour) {
+		t.Error("IsDormant() = true, want false for account idle shorter than the period")
+	}
+
+	account.Status = StatusFrozen
+	if service.IsDormant(account, now, 30*24*time.Hour) {
+		t.Error("IsDormant() = true, want false for frozen account")
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+	service := NewService()
+
+	if _, err := service.CreateAccount(CreateAccountRequest{CustomerName: "Ravi Kumar", Currency: "XYZ"}); err == nil {
+		t.Error("CreateAccount() expected error for unsupported currency")
+	}
+
+	inr := &Account{ID: "inr-id", CustomerName: "Ravi Kumar", Balance: 100000}
+	usd := &Account{ID: "usd-id", CustomerName: "Ravi Kumar", Balance: 0, Currency: "USD"}
+
+	if _, ok := service.ValidateCurrency(inr, "USD").(*errors.ErrCurrencyMismatch); !ok {
+		t.Error("ValidateCurrency() expected *errors.ErrCurrencyMismatch")
+	}
+	if _, ok := errorOf(service.Transfer(inr, usd, 8300)).(*errors.ErrCurrencyMismatch); !ok {
+		t.Error("Transfer() expected *errors.ErrCurrencyMismatch without a conversion")
+	}
+
+	conv := money.Conversion{
+		Source: money.Money{Amount: 8300, Currency: "INR"},
+		Target: money.Money{Amount: 100, Currency: "USD"},
+		Rate:   "0.012048",
+	}
+	if _, err := service.TransferConverted(inr, usd, conv); err != nil {
+		t.Fatalf("TransferConverted() unexpected error = %v", err)
+	}
+	if inr.Balance != 91700 || usd.Balance != 100 {
+		t.Errorf("TransferConverted() balances = %v/%v, want 91700/100", inr.Balance, usd.Balance)
+	}
+}
+
+func TestHolds(t *testing.T) {
+	service := NewService()
+
+	account := &Account{ID: "test-id", CustomerName: "Meera", Balance: 1000, Status: StatusActive}
+	if err := service.Reserve(account, 700); err != nil {
+		t.Fatalf("Reserve() unexpected error = %v", err)
+	}
+	if account.AvailableBalance() != 300 || account.Balance != 1000 {
+		t.Errorf("Reserve() available/balance = %v/%v, want 300/1000", account.AvailableBalance(), account.Balance)
+	}
+
+	if _, ok := errorOf(service.Withdraw(account, 500)).(*errors.ErrInsufficientFunds); !ok {
+		t.Error("Withdraw() expected *errors.ErrInsufficientFunds beyond available balance")
+	}
+	if _, ok := service.Close(&Account{ID: "held-id", HeldAmount: 10}).(*errors.ErrAccountHasHolds); !ok {
+		t.Error("Close() expected *errors.ErrAccountHasHolds")
+	}
+
+	if err := service.CaptureReserved(account, 700, 400); err != nil {
+		t.Fatalf("CaptureReserved() unexpected error = %v", err)
+	}
+	if account.Balance != 600 || account.HeldAmount != 0 {
+		t.Errorf("CaptureReserved() balance/held = %v/%v, want 600/0", account.Balance, account.HeldAmount)
+	}
+}
+
+func TestOverdraft(t *testing.T) {
+	service := NewService()
+
+	account := &Account{ID: "test-id", CustomerName: "Meera", Balance: 1000, Status: StatusActive}
+	if err := service.SetOverdraftLimit(account, -1); err == nil {
+		t.Error("SetOverdraftLimit() expected error for negative limit")
+	}
+	if err := service.SetOverdraftLimit(account, 500); err != nil {
+		t.Fatalf("SetOverdraftLimit() unexpected error = %v", err)
+	}
+
+	if _, err := service.Withdraw(account, 1400); err != nil {
+		t.Fatalf("Withdraw() into overdraft unexpected error = %v", err)
+	}
+	if account.Balance != -400 || account.AvailableBalance() != 100 {
+		t.Errorf("Withdraw() balance/available = %v/%v, want -400/100", account.Balance, account.AvailableBalance())
+	}
+
+	_, err := service.Withdraw(account, 200)
+	insufficient, ok := err.(*errors.ErrInsufficientFunds)
+	if !ok {
+		t.Fatalf("Withdraw() beyond limit error = %v, want *errors.ErrInsufficientFunds", err)
+	}
+	if insufficient.Available != 100 {
+		t.Errorf("ErrInsufficientFunds.Available = %v, want 100", insufficient.Available)
+	}
+
+	if _, ok := service.SetOverdraftLimit(account, 300).(*errors.ErrOverdraftLimitTooLow); !ok {
+		t.Error("SetOverdraftLimit() expected *errors.ErrOverdraftLimitTooLow below the overdrawn balance")
+	}
+}
+
+func TestAccountTypes(t *testing.T) {
+	service := NewService()
+
+	account, err := service.CreateAccount(CreateAccountRequest{CustomerName: "Meera"})
+	if err != nil {
+		t.Fatalf("CreateAccount() unexpected error = %v", err)
+	}
+	if account.Type != TypeCurrent {
+		t.Errorf("CreateAccount() type = %v, want %v", account.Type, TypeCurrent)
+	}
+
+	account, err = service.CreateAccount(CreateAccountRequest{CustomerName: "Meera", Type: TypeFixedDeposit})
+	if err != nil || account.Type != TypeFixedDeposit {
+		t.Errorf("CreateAccount() = %v, %v, want a fixed deposit", account, err)
+	}
+
+	if _, err := service.CreateAccount(CreateAccountRequest{CustomerName: "Meera", Type: "checking"}); err == nil {
+		t.Error("CreateAccount() expected error for unknown type")
+	}
+
+	if got := (&Account{}).CurrentType(); got != TypeCurrent {
+		t.Errorf("CurrentType() of untyped account = %v, want %v", got, TypeCurrent)
+	}
+}
+
+type fixedFee int64
+
+func (f fixedFee) Fee(account *Account, op Operation, amount int64) int64 {
+	if op == OperationWithdrawal {
+		return int64(f)
+	}
+	return 0
+}
+
+func TestWithdrawFee(t *testing.T) {
+	service := NewServiceWithFees(fixedFee(50))
+
+	account := &Account{ID: "test-id", CustomerName: "Meera", Balance: 1000, Status: StatusActive}
+	fee, err := service.Withdraw(account, 500)
+	if err != nil || fee != 50 {
+		t.Fatalf("Withdraw() = %v, %v, want fee 50", fee, err)
+	}
+	if account.Balance != 450 {
+		t.Errorf("Withdraw() balance = %v, want 450", account.Balance)
+	}
+
+	if _, err := service.Withdraw(account, 450); err == nil {
+		t.Error("Withdraw() expected error when the fee is not covered")
+	}
+	if _, err := service.Withdraw(account, 0); err == nil {
+		t.Error("Withdraw() expected error for zero amount")
+	}
+	if err := service.ReturnDeposit(account, 450); err != nil || account.Balance != 0 {
+		t.Errorf("ReturnDeposit() = %v with balance %v, want no fee", err, account.Balance)
+	}
+}
+
+// errorOf drops the fee returned alongside an error.
+func errorOf(_ int64, err error) error {
+	return err
+}
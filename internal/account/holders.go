@@ -0,0 +1,269 @@
+package account
+
+import (
+	"time"
+
+	"banking-service/pkg/errors"
+)
+
+// Role is what a holder may do with an account.
+type Role string
+
+const (
+	RolePrimary   Role = "primary"
+	RoleJoint     Role = "joint"
+	RoleSignatory Role = "authorized_signatory"
+	RoleViewer    Role = "view_only"
+)
+
+func ValidRole(r Role) bool {
+	switch r {
+	case RolePrimary, RoleJoint, RoleSignatory, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// CanSign reports whether holders with the role can make and approve
+// withdrawals and transfers.
+func (r Role) CanSign() bool {
+	return r == RolePrimary || r == RoleJoint || r == RoleSignatory
+}
+
+// Holder is a customer with a role on an account. The primary holder is the
+// account's CustomerID.
+type Holder struct {
+	CustomerID string    `json:"customer_id"`
+	Role       Role      `json:"role"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// Mandate is how many of the signing holders must agree to a withdrawal or
+// transfer.
+type Mandate string
+
+const (
+	MandateAnyOne Mandate = "any_one"
+	MandateAll    Mandate = "all"
+	MandateTwoOfN Mandate = "two_of_n"
+)
+
+func ValidMandate(m Mandate) bool {
+	switch m {
+	case MandateAnyOne, MandateAll, MandateTwoOfN:
+		return true
+	}
+	return false
+}
+
+// CurrentHolders returns the account's holders. Accounts linked to a customer
+// before holders existed have that customer as their only, primary holder.
+func (a *Account) CurrentHolders() []Holder {
+	if len(a.Holders) == 0 && a.CustomerID != "" {
+		return []Holder{{CustomerID: a.CustomerID, Role: RolePrimary, AddedAt: a.CreatedAt}}
+	}
+	return a.Holders
+}
+
+// CurrentMandate returns the account's mandate, defaulting to any one holder.
+func (a *Account) CurrentMandate() Mandate {
+	if a.Mandate == "" {
+		return MandateAnyOne
+	}
+	return a.Mandate
+}
+
+// Holder returns the customer's holding on the account.
+func (a *Account) Holder(customerID string) (Holder, bool) {
+	for _, holder := range a.CurrentHolders() {
+		if holder.CustomerID == customerID {
+			return holder, true
+		}
+	}
+	return Holder{}, false
+}
+
+// CanSign reports whether the customer is a holder who can make and approve
+// payments from the account.
+func (a *Account) CanSign(customerID string) bool {
+	holder, ok := a.Holder(customerID)
+	return ok && holder.Role.CanSign()
+}
+
+// RequiredApprovals is how many signing holders the mandate needs.
+func (a *Account) RequiredApprovals() int {
+	switch a.CurrentMandate() {
+	case MandateAll:
+		return signers(a.CurrentHolders())
+	case MandateTwoOfN:
+		return 2
+	}
+	return 1
+}
+
+// MandateSatisfied reports whether the approvals of customerIDs are enough
+// for a payment from the account. Only current signing holders count.
+func (a *Account) MandateSatisfied(customerIDs ...string) bool {
+	approved := make(map[string]bool)
+	for _, id := range customerIDs {
+		if a.CanSign(id) {
+			approved[id] = true
+		}
+	}
+	return len(approved) > 0 && len(approved) >= a.RequiredApprovals()
+}
+
+// NeedsApproval reports whether a payment the customer asks for must wait for
+// other holders to approve it.
+func (a *Account) NeedsApproval(customerID string) bool {
+	return len(a.CurrentHolders()) > 0 && !a.MandateSatisfied(customerID)
+}
+
+func signers(holders []Holder) int {
+	n := 0
+	for _, holder := range holders {
+		if holder.Role.CanSign() {
+			n++
+		}
+	}
+	return n
+}
+
+// Authorize checks that the caller may make a payment of the given kind from
+// the account. Accounts without holders are not restricted.
+func (s *Service) Authorize(account *Account, customerID, action string) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if len(account.CurrentHolders()) == 0 || account.CanSign(customerID) {
+		return nil
+	}
+	return &errors.ErrCallerNotAuthorized{AccountID: account.ID, CustomerID: customerID, Action: action}
+}
+
+// AuthorizeAlone is Authorize for payments that cannot wait for other
+// holders to approve them, such as batches, standing orders and holds: the
+// mandate must be satisfied by the caller's signature alone.
+func (s *Service) AuthorizeAlone(account *Account, customerID, action string) error {
+	if err := s.Authorize(account, customerID, action); err != nil {
+		return err
+	}
+	if account.NeedsApproval(customerID) {
+		return &errors.ErrApprovalRequired{AccountID: account.ID, CustomerID: customerID, Action: action}
+	}
+	return nil
+}
+
+// AuthorizeManagement checks that the caller is the primary holder, who alone
+// may change the account's holders and mandate.
+func (s *Service) AuthorizeManagement(account *Account, customerID string) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if holder, ok := account.Holder(customerID); ok && holder.Role == RolePrimary {
+		return nil
+	}
+	return &errors.ErrCallerNotAuthorized{AccountID: account.ID, CustomerID: customerID, Action: "manage holders"}
+}
+
+// SetHolder adds the customer to the account with the role, or changes the
+// role of an existing holder. The primary holder is changed with
+// LinkCustomer instead.
+func (s *Service) SetHolder(account *Account, customerID string, role Role) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() == StatusClosed {
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	if !ValidRole(role) {
+		return &errors.ErrInvalidParameter{Name: "role", Value: string(role)}
+	}
+	if role == RolePrimary {
+		return &errors.ErrInvalidHolderChange{AccountID: account.ID, CustomerID: customerID, Reason: "the primary holder is set by linking the account to a customer"}
+	}
+	if account.CustomerID == "" {
+		return &errors.ErrInvalidHolderChange{AccountID: account.ID, CustomerID: customerID, Reason: "the account has no primary holder"}
+	}
+	if customerID == account.CustomerID {
+		return &errors.ErrInvalidHolderChange{AccountID: account.ID, CustomerID: customerID, Reason: "the primary holder's role cannot be changed"}
+	}
+
+	now := time.Now()
+	holders := make([]Holder, 0, len(account.CurrentHolders())+1)
+	added := false
+	for _, holder := range account.CurrentHolders() {
+		if holder.CustomerID == customerID {
+			holder.Role = role
+			added = true
+		}
+		holders = append(holders, holder)
+	}
+	if !added {
+		holders = append(holders, Holder{CustomerID: customerID, Role: role, AddedAt: now})
+	}
+	if err := checkMandate(account, account.CurrentMandate(), holders); err != nil {
+		return err
+	}
+
+	account.Holders = holders
+	account.UpdatedAt = now
+	return nil
+}
+
+// RemoveHolder takes the customer off the account. The primary holder cannot
+// be removed.
+func (s *Service) RemoveHolder(account *Account, customerID string) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	holder, ok := account.Holder(customerID)
+	if !ok {
+		return &errors.ErrHolderNotFound{AccountID: account.ID, CustomerID: customerID}
+	}
+	if holder.Role == RolePrimary {
+		return &errors.ErrInvalidHolderChange{AccountID: account.ID, CustomerID: customerID, Reason: "the primary holder cannot be removed"}
+	}
+
+	holders := make([]Holder, 0, len(account.CurrentHolders()))
+	for _, holder := range account.CurrentHolders() {
+		if holder.CustomerID != customerID {
+			holders = append(holders, holder)
+		}
+	}
+	if err := checkMandate(account, account.CurrentMandate(), holders); err != nil {
+		return err
+	}
+
+	account.Holders = holders
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetMandate changes how many signing holders must approve payments.
+func (s *Service) SetMandate(account *Account, mandate Mandate) error {
+	if err := s.ValidateAccount(account); err != nil {
+		return err
+	}
+	if account.CurrentStatus() == StatusClosed {
+		return &errors.ErrAccountClosed{AccountID: account.ID}
+	}
+	if !ValidMandate(mandate) {
+		return &errors.ErrInvalidParameter{Name: "mandate", Value: string(mandate)}
+	}
+	if err := checkMandate(account, mandate, account.CurrentHolders()); err != nil {
+		return err
+	}
+
+	account.Mandate = mandate
+	account.UpdatedAt = time.Now()
+	return nil
+}
+
+// checkMandate rejects holder lists that could never satisfy the mandate.
+func checkMandate(account *Account, mandate Mandate, holders []Holder) error {
+	if mandate == MandateTwoOfN && signers(holders) < 2 {
+		return &errors.ErrInvalidMandate{AccountID: account.ID, Mandate: string(mandate), Reason: "it needs at least two signing holders"}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package account
+
+import (
+	"testing"
+
+	"banking-service/pkg/errors"
+)
+
+func TestHolders(t *testing.T) {
+	service := NewService()
+
+	account, err := service.CreateAccount(CreateAccountRequest{CustomerName: "Meera", CustomerID: "meera"})
+	if err != nil {
+		t.Fatalf("CreateAccount() unexpected error = %v", err)
+	}
+	if holder, ok := account.Holder("meera"); !ok || holder.Role != RolePrimary {
+		t.Fatalf("CreateAccount() holders = %+v, want meera as primary", account.Holders)
+	}
+
+	if err := service.SetHolder(account, "arjun", RoleJoint); err != nil {
+		t.Fatalf("SetHolder() unexpected error = %v", err)
+	}
+	if err := service.SetHolder(account, "kavya", RoleViewer); err != nil {
+		t.Fatalf("SetHolder() unexpected error = %v", err)
+	}
+	if account.NeedsApproval("arjun") || !account.CanSign("arjun") {
+		t.Error("joint holder should sign alone under any_one")
+	}
+	if _, ok := service.Authorize(account, "kavya", "withdraw").(*errors.ErrCallerNotAuthorized); !ok {
+		t.Error("Authorize() expected *errors.ErrCallerNotAuthorized for a view-only holder")
+	}
+	if _, ok := service.Authorize(account, "", "withdraw").(*errors.ErrCallerNotAuthorized); !ok {
+		t.Error("Authorize() expected *errors.ErrCallerNotAuthorized without a caller")
+	}
+
+	if _, ok := service.SetHolder(account, "meera", RoleViewer).(*errors.ErrInvalidHolderChange); !ok {
+		t.Error("SetHolder() expected *errors.ErrInvalidHolderChange for the primary holder")
+	}
+	if _, ok := service.RemoveHolder(account, "meera").(*errors.ErrInvalidHolderChange); !ok {
+		t.Error("RemoveHolder() expected *errors.ErrInvalidHolderChange for the primary holder")
+	}
+	if _, ok := service.RemoveHolder(account, "nobody").(*errors.ErrHolderNotFound); !ok {
+		t.Error("RemoveHolder() expected *errors.ErrHolderNotFound")
+	}
+	if _, ok := service.AuthorizeManagement(account, "arjun").(*errors.ErrCallerNotAuthorized); !ok {
+		t.Error("AuthorizeManagement() expected *errors.ErrCallerNotAuthorized for a joint holder")
+	}
+
+	// Linking to a new customer replaces the primary holder only.
+	if err := service.LinkCustomer(account, "arjun", "Arjun"); err != nil {
+		t.Fatalf("LinkCustomer() unexpected error = %v", err)
+	}
+	if _, ok := account.Holder("meera"); ok {
+		t.Error("LinkCustomer() kept the previous primary holder")
+	}
+	if holder, _ := account.Holder("arjun"); holder.Role != RolePrimary || len(account.Holders) != 2 {
+		t.Errorf("LinkCustomer() holders = %+v, want arjun primary and kavya", account.Holders)
+	}
+}
+
+func TestMandates(t *testing.T) {
+	service := NewService()
+
+	account := &Account{ID: "joint", CustomerID: "a", Status: StatusActive}
+	if _, ok := service.SetMandate(account, MandateTwoOfN).(*errors.ErrInvalidMandate); !ok {
+		t.Error("SetMandate() expected *errors.ErrInvalidMandate with one signer")
+	}
+	if _, ok := service.SetMandate(account, "majority").(*errors.ErrInvalidParameter); !ok {
+		t.Error("SetMandate() expected *errors.ErrInvalidParameter for an unknown mandate")
+	}
+
+	for _, id := range []string{"b", "c"} {
+		if err := service.SetHolder(account, id, RoleJoint); err != nil {
+			t.Fatalf("SetHolder() unexpected error = %v", err)
+		}
+	}
+	if err := service.SetHolder(account, "d", RoleViewer); err != nil {
+		t.Fatalf("SetHolder() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		mandate   Mandate
+		approvers []string
+		want      bool
+	}{
+		{MandateAnyOne, []string{"c"}, true},
+		{MandateAnyOne, []string{"d"}, false},
+		{MandateTwoOfN, []string{"a"}, false},
+		{MandateTwoOfN, []string{"a", "a"}, false},
+		{MandateTwoOfN, []string{"a", "d"}, false},
+		{MandateTwoOfN, []string{"b", "c"}, true},
+		{MandateAll, []string{"a", "b"}, false},
+		{MandateAll, []string{"a", "b", "c"}, true},
+	}
+	for _, tt := range tests {
+		if err := service.SetMandate(account, tt.mandate); err != nil {
+			t.Fatalf("SetMandate(%s) unexpected error = %v", tt.mandate, err)
+		}
+		if got := account.MandateSatisfied(tt.approvers...); got != tt.want {
+			t.Errorf("%s with %v: MandateSatisfied() = %v, want %v", tt.mandate, tt.approvers, got, tt.want)
+		}
+	}
+
+	// Two of N must keep two signers.
+	if err := service.SetMandate(account, MandateTwoOfN); err != nil {
+		t.Fatalf("SetMandate() unexpected error = %v", err)
+	}
+	if err := service.RemoveHolder(account, "b"); err != nil {
+		t.Fatalf("RemoveHolder() unexpected error = %v", err)
+	}
+	if _, ok := service.SetHolder(account, "c", RoleViewer).(*errors.ErrInvalidMandate); !ok {
+		t.Error("SetHolder() expected *errors.ErrInvalidMandate when leaving one signer")
+	}
+
+	// Payments that cannot wait for approval need the caller to satisfy the
+	// mandate alone.
+	if _, ok := service.AuthorizeAlone(account, "a", "make batch payments").(*errors.ErrApprovalRequired); !ok {
+		t.Error("AuthorizeAlone() expected *errors.ErrApprovalRequired under two of N")
+	}
+	if _, ok := service.AuthorizeAlone(account, "d", "make batch payments").(*errors.ErrCallerNotAuthorized); !ok {
+		t.Error("AuthorizeAlone() expected *errors.ErrCallerNotAuthorized for a viewer")
+	}
+	if err := service.SetMandate(account, MandateAnyOne); err != nil {
+		t.Fatalf("SetMandate() unexpected error = %v", err)
+	}
+	if err := service.AuthorizeAlone(account, "a", "make batch payments"); err != nil {
+		t.Errorf("AuthorizeAlone() under any one unexpected error = %v", err)
+	}
+}
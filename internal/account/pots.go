@@ -0,0 +1,95 @@
+package account
+
+import (
+	"strings"
+	"time"
+
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// IsPot reports whether the account is a pot under a parent account.
+func (a *Account) IsPot() bool {
+	return a.ParentID != ""
+}
+
+// CreatePot opens a pot named name under parent, alongside its existing
+// pots. The pot is a savings account in the parent's currency, held by the
+// parent's holders.
+func (s *Service) CreatePot(parent *Account, name string, pots []*Account) (*Account, error) {
+	if err := s.ValidateAccount(parent); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &errors.ErrInvalidParameter{Name: "name", Value: name}
+	}
+	if parent.IsPot() {
+		return nil, &errors.ErrInvalidPot{AccountID: parent.ID, Reason: "pots cannot have pots of their own"}
+	}
+	if parent.CurrentStatus() == StatusClosed {
+		return nil, &errors.ErrAccountClosed{AccountID: parent.ID}
+	}
+	for _, pot := range pots {
+		if pot.CurrentStatus() != StatusClosed && strings.EqualFold(pot.Name, name) {
+			return nil, &errors.ErrInvalidPot{AccountID: parent.ID, Reason: "it already has a pot named " + pot.Name}
+		}
+	}
+
+	now := time.Now()
+	return &Account{
+		ID:           uuid.New().String(),
+		CustomerName: parent.CustomerName,
+		CustomerID:   parent.CustomerID,
+		Currency:     parent.CurrentCurrency(),
+		Type:         TypeSavings,
+		Status:       StatusActive,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+
+		LastActivityAt: now,
+		Holders:        append([]Holder(nil), parent.Holders...),
+		Mandate:        parent.Mandate,
+		ParentID:       parent.ID,
+		Name:           name,
+	}, nil
+}
+
+// Move shifts amount between an account and one of its pots, in either
+// direction. Moves are free.
+func (s *Service) Move(fromAccount, toAccount *Account, amount int64) error {
+	if err := s.validateTransfer(fromAccount, toAccount); err != nil {
+		return err
+	}
+	if fromAccount.ParentID != toAccount.ID && toAccount.ParentID != fromAccount.ID {
+		return &errors.ErrInvalidPotMove{FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID}
+	}
+	return s.move(fromAccount, toAccount, amount, amount)
+}
+
+// SweepPot moves whatever is in the pot back to its parent and closes the
+// pot, returning the amount moved.
+func (s *Service) SweepPot(pot, parent *Account) (int64, error) {
+	swept := pot.Balance
+	if swept > 0 {
+		if err := s.Move(pot, parent, swept); err != nil {
+			return 0, err
+		}
+	}
+	if err := s.Close(pot); err != nil {
+		return 0, err
+	}
+	return swept, nil
+}
+
+// notPot rejects payments into or out of pots, whose money only moves to
+// and from their parent.
+func notPot(accounts ...*Account) error {
+	for _, acc := range accounts {
+		if acc.IsPot() {
+			return &errors.ErrPotRestricted{AccountID: acc.ID, ParentID: acc.ParentID}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"banking-service/pkg/errors"
+)
+
+func TestCreatePot(t *testing.T) {
+	service := NewService()
+
+	parent, err := service.CreateAccount(CreateAccountRequest{CustomerName: "Meera", CustomerID: "meera", Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("CreateAccount() unexpected error = %v", err)
+	}
+
+	pot, err := service.CreatePot(parent, " Holiday ", nil)
+	if err != nil {
+		t.Fatalf("CreatePot() unexpected error = %v", err)
+	}
+	if !pot.IsPot() || pot.ParentID != parent.ID || pot.Name != "Holiday" || pot.CurrentCurrency() != "EUR" {
+		t.Errorf("CreatePot() = %+v, want a EUR pot named Holiday under the parent", pot)
+	}
+	if holder, ok := pot.Holder("meera"); !ok || holder.Role != RolePrimary {
+		t.Errorf("CreatePot() holders = %+v, want the parent's holders", pot.Holders)
+	}
+
+	if _, err := service.CreatePot(parent, "holiday", []*Account{pot}); err == nil {
+		t.Error("CreatePot() expected an error for a duplicate name")
+	} else if _, ok := err.(*errors.ErrInvalidPot); !ok {
+		t.Errorf("CreatePot() error = %v, want *errors.ErrInvalidPot", err)
+	}
+	if _, err := service.CreatePot(pot, "Nested", nil); err == nil {
+		t.Error("CreatePot() expected an error under a pot")
+	} else if _, ok := err.(*errors.ErrInvalidPot); !ok {
+		t.Errorf("CreatePot() error = %v, want *errors.ErrInvalidPot", err)
+	}
+	if _, err := service.CreatePot(parent, " ", nil); err == nil {
+		t.Error("CreatePot() expected an error for a blank name")
+	}
+
+	pot.Status = StatusClosed
+	if _, err := service.CreatePot(parent, "Holiday", []*Account{pot}); err != nil {
+		t.Errorf("CreatePot() reusing a closed pot's name unexpected error = %v", err)
+	}
+}
+
+func TestPotMoves(t *testing.T) {
+	service := NewService()
+
+	parent := &Account{ID: "parent-id", CustomerName: "Meera", Balance: 1000, Status: StatusActive}
+	pot := &Account{ID: "pot-id", CustomerName: "Meera", Status: StatusActive, Type: TypeSavings, ParentID: parent.ID}
+	other := &Account{ID: "other-id", CustomerName: "Arjun", Balance: 1000, Status: StatusActive}
+
+	if err := service.Move(parent, pot, 600); err != nil {
+		t.Fatalf("Move() unexpected error = %v", err)
+	}
+	if err := service.Move(pot, parent, 100); err != nil {
+		t.Fatalf("Move() unexpected error = %v", err)
+	}
+	if parent.Balance != 500 || pot.Balance != 500 {
+		t.Errorf("Move() balances = %d and %d, want 500 and 500 with no fee", parent.Balance, pot.Balance)
+	}
+	if _, ok := service.Move(other, pot, 100).(*errors.ErrInvalidPotMove); !ok {
+		t.Error("Move() expected *errors.ErrInvalidPotMove from another account")
+	}
+	if _, ok := service.Move(pot, parent, 600).(*errors.ErrInsufficientFunds); !ok {
+		t.Error("Move() expected *errors.ErrInsufficientFunds beyond the pot balance")
+	}
+
+	// Pots only move money to and from their parent.
+	if _, ok := service.Deposit(pot, 100).(*errors.ErrPotRestricted); !ok {
+		t.Error("Deposit() expected *errors.ErrPotRestricted")
+	}
+	if _, ok := errorOf(service.Withdraw(pot, 100)).(*errors.ErrPotRestricted); !ok {
+		t.Error("Withdraw() expected *errors.ErrPotRestricted")
+	}
+	if _, ok := errorOf(service.Transfer(other, pot, 100)).(*errors.ErrPotRestricted); !ok {
+		t.Error("Transfer() expected *errors.ErrPotRestricted")
+	}
+
+	pot.LastActivityAt = time.Now().Add(-400 * 24 * time.Hour)
+	if service.IsDormant(pot, time.Now(), 30*24*time.Hour) {
+		t.Error("IsDormant() = true, want false for a pot")
+	}
+
+	swept, err := service.SweepPot(pot, parent)
+	if err != nil {
+		t.Fatalf("SweepPot() unexpected error = %v", err)
+	}
+	if swept != 500 || parent.Balance != 1000 || pot.Balance != 0 || pot.Status != StatusClosed {
+		t.Errorf("SweepPot() = %d, balances %d and %d, status %v, want 500 swept into the parent and the pot closed", swept, parent.Balance, pot.Balance, pot.Status)
+	}
+}
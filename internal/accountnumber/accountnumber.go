@@ -0,0 +1,184 @@
+package accountnumber
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/errors"
+)
+
+// An account number is a 4-digit branch code, a 2-digit product code, a
+// 7-digit sequence allocated per branch and product, and a Luhn check digit,
+// e.g. 0001 20 0000042 7.
+const (
+	Length      = 14
+	maxSequence = 9999999
+)
+
+// maxIBANLength is the longest IBAN ISO 13616 allows.
+const maxIBANLength = 34
+
+var productCodes = map[account.Type]string{
+	account.TypeSavings:      "10",
+	account.TypeCurrent:      "20",
+	account.TypeFixedDeposit: "30",
+}
+
+// Service allocates account numbers and, when a country and bank code are
+// configured, IBANs built from them.
+type Service struct {
+	branch      string
+	countryCode string
+	bankCode    string
+}
+
+// NewService returns a service numbering accounts in defaultBranch unless
+// another branch is asked for. countryCode and bankCode must both be set to
+// generate IBANs, or both be empty.
+func NewService(defaultBranch, countryCode, bankCode string) (*Service, error) {
+	if !validBranch(defaultBranch) {
+		return nil, fmt.Errorf("branch code %q must be 4 digits", defaultBranch)
+	}
+	if (countryCode == "") != (bankCode == "") {
+		return nil, fmt.Errorf("IBANs need both a country code and a bank code")
+	}
+	if countryCode != "" {
+		if len(countryCode) != 2 || !isUpper(countryCode) {
+			return nil, fmt.Errorf("IBAN country code %q must be 2 letters", countryCode)
+		}
+		if !isAlphanumeric(bankCode) || 4+len(bankCode)+Length > maxIBANLength {
+			return nil, fmt.Errorf("IBAN bank code %q must be up to %d letters or digits", bankCode, maxIBANLength-4-Length)
+		}
+	}
+	return &Service{branch: defaultBranch, countryCode: countryCode, bankCode: bankCode}, nil
+}
+
+// Prefix returns the branch and product digits that numbers for an account
+// of type t in branch start with. An empty branch means the default one.
+func (s *Service) Prefix(branch string, t account.Type) (string, error) {
+	if branch == "" {
+		branch = s.branch
+	}
+	if !validBranch(branch) {
+		return "", &errors.ErrInvalidParameter{Name: "branch", Value: branch}
+	}
+	product, ok := productCodes[t]
+	if !ok {
+		return "", &errors.ErrInvalidParameter{Name: "type", Value: string(t)}
+	}
+	return branch + product, nil
+}
+
+// Assign gives the account the number made of prefix and seq, and its IBAN
+// if IBANs are configured.
+func (s *Service) Assign(acc *account.Account, prefix string, seq int64) error {
+	if seq <= 0 || seq > maxSequence {
+		return fmt.Errorf("no account numbers left for %s", prefix)
+	}
+
+	body := fmt.Sprintf("%s%07d", prefix, seq)
+	acc.Number = body + strconv.Itoa(luhnDigit(body))
+	if s.countryCode != "" {
+		acc.IBAN = IBAN(s.countryCode, s.bankCode+acc.Number)
+	}
+	return nil
+}
+
+// IBAN builds the IBAN for a domestic account number (BBAN) in the country.
+func IBAN(countryCode, bban string) string {
+	check := 98 - mod97(bban+countryCode+"00")
+	return fmt.Sprintf("%s%02d%s", countryCode, check, bban)
+}
+
+// Normalize turns a printed account number or IBAN, which may be grouped
+// with spaces, into its stored form.
+func Normalize(ref string) string {
+	return strings.ToUpper(strings.ReplaceAll(ref, " ", ""))
+}
+
+// Check validates ref if it is written as an account number or IBAN, and
+// returns *errors.ErrInvalidAccountNumber if its check digits are wrong.
+// Other references, such as account IDs, are left to the caller.
+func Check(ref string) error {
+	normalized := Normalize(ref)
+	switch {
+	case isDigits(normalized):
+		if len(normalized) != Length || luhnDigit(normalized[:Length-1]) != int(normalized[Length-1]-'0') {
+			return &errors.ErrInvalidAccountNumber{Number: ref}
+		}
+	case looksLikeIBAN(normalized):
+		if len(normalized) > maxIBANLength || mod97(normalized[4:]+normalized[:4]) != 1 {
+			return &errors.ErrInvalidAccountNumber{Number: ref}
+		}
+	}
+	return nil
+}
+
+// luhnDigit returns the Luhn check digit for digits.
+func luhnDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+// mod97 returns s modulo 97, reading letters as two-digit numbers from
+// A=10 to Z=35 as ISO 13616 does.
+func mod97(s string) int {
+	rem := 0
+	for _, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			rem = (rem*100 + int(c-'A'+10)) % 97
+			continue
+		}
+		rem = (rem*10 + int(c-'0')) % 97
+	}
+	return rem
+}
+
+func looksLikeIBAN(s string) bool {
+	return len(s) > 4 && isUpper(s[:2]) && isDigits(s[2:4]) && isAlphanumeric(s)
+}
+
+func validBranch(branch string) bool {
+	return len(branch) == 4 && isDigits(branch)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isUpper(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,95 @@
+package accountnumber
+
+import (
+	"testing"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/errors"
+)
+
+func TestAssign(t *testing.T) {
+	s, err := NewService("0001", "GB", "NWBK")
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	prefix, err := s.Prefix("", account.TypeCurrent)
+	if err != nil || prefix != "000120" {
+		t.Fatalf("Prefix() = %q, %v, want 000120", prefix, err)
+	}
+	if _, err := s.Prefix("12", account.TypeCurrent); err == nil {
+		t.Error("Prefix() expected an error for a short branch code")
+	}
+
+	acc := &account.Account{}
+	if err := s.Assign(acc, prefix, 42); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if acc.Number != "00012000000427" {
+		t.Errorf("Number = %s, want 00012000000427", acc.Number)
+	}
+	if len(acc.IBAN) != 22 || acc.IBAN[:2] != "GB" || acc.IBAN[4:8] != "NWBK" {
+		t.Errorf("IBAN = %s, want a GB IBAN for NWBK", acc.IBAN)
+	}
+	if err := Check(acc.Number); err != nil {
+		t.Errorf("Check(%s) error = %v", acc.Number, err)
+	}
+	if err := Check(acc.IBAN); err != nil {
+		t.Errorf("Check(%s) error = %v", acc.IBAN, err)
+	}
+
+	if err := s.Assign(acc, prefix, maxSequence+1); err == nil {
+		t.Error("Assign() expected an error past the last sequence")
+	}
+
+	plain, _ := NewService("0001", "", "")
+	unnumbered := &account.Account{}
+	plain.Assign(unnumbered, prefix, 1)
+	if unnumbered.IBAN != "" {
+		t.Errorf("IBAN = %s without an IBAN configuration", unnumbered.IBAN)
+	}
+}
+
+func TestNewServiceErrors(t *testing.T) {
+	tests := []struct {
+		name                  string
+		branch, country, bank string
+	}{
+		{"bad branch", "1", "", ""},
+		{"country without bank", "0001", "GB", ""},
+		{"lower case country", "0001", "gb", "NWBK"},
+		{"long bank code", "0001", "GB", "ABCDEFGHIJKLMNOPQ"},
+	}
+	for _, tt := range tests {
+		if _, err := NewService(tt.branch, tt.country, tt.bank); err == nil {
+			t.Errorf("%s: NewService() expected an error", tt.name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		ref   string
+		valid bool
+	}{
+		{"GB82WEST12345698765432", true},
+		{"GB82 WEST 1234 5698 7654 32", true},
+		{"gb82 west 1234 5698 7654 32", true},
+		{"GB82WEST12345698765423", false},
+		{"00012000000427", true},
+		{"0001 2000 0004 27", true},
+		{"00012000000424", false},
+		{"00021000000427", false},
+		{"0001200000042", false},
+		{"8c2a6f0e-4b7d-4f7e-9d1b-2f1e3c4d5a6b", true},
+	}
+	for _, tt := range tests {
+		err := Check(tt.ref)
+		if tt.valid && err != nil {
+			t.Errorf("Check(%q) error = %v", tt.ref, err)
+		}
+		if _, ok := err.(*errors.ErrInvalidAccountNumber); !tt.valid && !ok {
+			t.Errorf("Check(%q) = %v, want *errors.ErrInvalidAccountNumber", tt.ref, err)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/approval"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// CallerHeader identifies the customer making a request. Payments from
+// accounts with holders are authorized against it.
+const CallerHeader = "X-Customer-ID"
+
+func caller(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(CallerHeader))
+}
+
+type ApprovalList struct {
+	Approvals []*approval.Approval `json:"approvals"`
+}
+
+// authorizePayment checks that the caller may pay amount from acc, and
+// reports whether the payment must wait for other holders to approve it.
+func (h *Handler) authorizePayment(acc *account.Account, customerID, action string, amount int64) (bool, error) {
+	if err := h.accountService.Authorize(acc, customerID, action); err != nil {
+		return false, err
+	}
+	if !acc.NeedsApproval(customerID) {
+		return false, nil
+	}
+	if amount <= 0 {
+		return false, &errors.ErrInvalidAmount{Amount: amount}
+	}
+	return true, nil
+}
+
+// AccountApprovals handles GET /accounts/{id}/approvals, optionally
+// filtered by ?status=.
+func (h *Handler) AccountApprovals(w http.ResponseWriter, r *http.Request, accountID string) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, err := h.store.GetAccount(r.Context(), accountID); err != nil {
+		h.writeApprovalError(w, err, "Failed to list approvals")
+		return
+	}
+
+	status := approval.Status(r.URL.Query().Get("status"))
+	approvals := make([]*approval.Approval, 0)
+	for _, a := range h.store.ListApprovals(r.Context(), accountID) {
+		if status == "" || a.Status == status {
+			approvals = append(approvals, a)
+		}
+	}
+	h.writeJSON(w, http.StatusOK, ApprovalList{Approvals: approvals})
+}
+
+// GetApproval handles GET /approvals/{id}.
+func (h *Handler) GetApproval(w http.ResponseWriter, r *http.Request, approvalID string) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	a, err := h.store.GetApproval(r.Context(), approvalID)
+	if err != nil {
+		h.writeApprovalError(w, err, "Failed to get approval")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, a)
+}
+
+// ApprovePayment handles POST /approvals/{id}/approve. The caller's approval
+// is added and, once the account's mandate is satisfied, the payment is made.
+// A payment declined at that point fails the approval with 422.
+func (h *Handler) ApprovePayment(w http.ResponseWriter, r *http.Request, approvalID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	customerID := caller(r)
+	now := time.Now()
+
+	var approved *approval.Approval
+	var tx *transaction.Transaction
+	var async, executing bool
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		executing = false
+		a, err := uow.GetApproval(approvalID)
+		if err != nil {
+			return err
+		}
+		acc, err := uow.GetAccount(a.AccountID)
+		if err != nil {
+			return err
+		}
+		ready, err := h.approvalService.Approve(a, acc, customerID, now)
+		if err != nil {
+			return err
+		}
+
+		approved = a
+		if ready {
+			executing = true
+			tx, async, err = h.executeApproval(uow, a)
+			if err != nil {
+				return err
+			}
+			h.approvalService.Executed(a, tx.ID, now)
+		}
+		return uow.SaveApproval(a)
+	})
+	if err != nil && executing && errors.Code(err) != "" {
+		h.failApproval(w, r, approved, customerID, err, now)
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("approval_id", approvalID).Error("Failed to approve payment")
+		h.writeApprovalError(w, err, "Failed to approve payment")
+		return
+	}
+
+	if approved.Status != approval.StatusExecuted {
+		h.logger.WithFields(logrus.Fields{
+			"approval_id": approved.ID,
+			"customer_id": customerID,
+		}).Info("Payment approved, waiting for more approvals")
+
+		h.writeJSON(w, http.StatusOK, approved)
+		return
+	}
+
+	if async {
+		if err := h.transfers.Submit(tx.ID); err != nil {
+			h.logger.WithError(err).WithField("transaction_id", tx.ID).Warn("Pending transfer not queued, it will be requeued by the recovery job")
+		}
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"approval_id":    approved.ID,
+		"account_id":     approved.AccountID,
+		"transaction_id": tx.ID,
+	}).Info("Approved payment made")
+
+	h.writeJSON(w, http.StatusOK, approved)
+}
+
+// executeApproval makes the payment an approval was waiting for.
+func (h *Handler) executeApproval(uow store.UnitOfWork, a *approval.Approval) (*transaction.Transaction, bool, error) {
+	if a.Kind == approval.KindWithdrawal {
+		tx, err := h.withdraw(uow, *a.Withdrawal)
+		return tx, false, err
+	}
+
+	async := h.asyncTransfer(a.Transfer.Amount)
+	tx, err := h.transfer(uow, *a.Transfer, async)
+	return tx, async, err
+}
+
+// failApproval records the declined payment and marks the approval failed,
+// keeping the approval that completed it.
+func (h *Handler) failApproval(w http.ResponseWriter, r *http.Request, a *approval.Approval, customerID string, cause error, now time.Time) {
+	h.logger.WithError(cause).WithField("approval_id", a.ID).Error("Approved payment declined")
+
+	var failedID string
+	if a.Kind == approval.KindWithdrawal {
+		req := a.Withdrawal
+		failedID = h.recordFailure(r.Context(), h.transactionService.CreateWithdrawalTransaction(req.AccountID, req.Amount, req.Currency), cause)
+	} else {
+		failedID = h.recordFailure(r.Context(), h.declinedTransfer(*a.Transfer), cause)
+	}
+
+	var failed *approval.Approval
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		current, err := uow.GetApproval(a.ID)
+		if err != nil {
+			return err
+		}
+		acc, err := uow.GetAccount(current.AccountID)
+		if err != nil {
+			return err
+		}
+		if _, err := h.approvalService.Approve(current, acc, customerID, now); err != nil {
+			return err
+		}
+		h.approvalService.Failed(current, cause, failedID, now)
+
+		failed = current
+		return uow.SaveApproval(current)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("approval_id", a.ID).Error("Failed to record declined approval")
+		h.writeApprovalError(w, err, "Failed to approve payment")
+		return
+	}
+
+	h.writeJSON(w, http.StatusUnprocessableEntity, failed)
+}
+
+// RejectPayment handles POST /approvals/{id}/reject.
+func (h *Handler) RejectPayment(w http.ResponseWriter, r *http.Request, approvalID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	customerID := caller(r)
+
+	var rejected *approval.Approval
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		a, err := uow.GetApproval(approvalID)
+		if err != nil {
+			return err
+		}
+		acc, err := uow.GetAccount(a.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := h.approvalService.Reject(a, acc, customerID, time.Now()); err != nil {
+			return err
+		}
+
+		rejected = a
+		return uow.SaveApproval(a)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("approval_id", approvalID).Error("Failed to reject payment")
+		h.writeApprovalError(w, err, "Failed to reject payment")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"approval_id": rejected.ID,
+		"customer_id": customerID,
+	}).Info("Payment rejected")
+
+	h.writeJSON(w, http.StatusOK, rejected)
+}
+
+func (h *Handler) writeApprovalError(w http.ResponseWriter, err error, message string) {
+	switch err.(type) {
+	case *errors.ErrApprovalNotFound:
+		h.writeError(w, http.StatusNotFound, "Approval not found")
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrCallerNotAuthorized:
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case *errors.ErrApprovalNotPending, *errors.ErrAlreadyApproved:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/batch"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// CreateBatch handles POST /transactions/batch. The legs come either as a
+// JSON batch.Request or, with Content-Type text/csv, as a CSV file with the
+// mode and a default from_account_id in the query string. The response is
+// the batch report: 201 if any leg went through, 422 otherwise.
+func (h *Handler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req, err := decodeBatchRequest(r)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to decode batch request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+
+	now := time.Now()
+	b, err := h.batchService.New(req, now)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.validateBatchAccounts(r, b)
+
+	if b.Mode == batch.ModeAllOrNothing {
+		h.runAllOrNothing(r, b, now)
+	} else {
+		h.runBestEffort(r, b, now)
+	}
+
+	h.batchService.Complete(b, time.Now())
+	err = h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		return uow.SaveBatch(b)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("batch_id", b.ID).Error("Failed to save batch report")
+		h.writeError(w, http.StatusInternalServerError, "Failed to save batch report")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"batch_id":  b.ID,
+		"mode":      b.Mode,
+		"status":    b.Status,
+		"succeeded": b.Succeeded,
+		"failed":    b.Failed,
+	}).Info("Batch processed")
+
+	statusCode := http.StatusCreated
+	if b.Succeeded == 0 {
+		statusCode = http.StatusUnprocessableEntity
+	}
+	h.writeJSON(w, statusCode, b)
+}
+
+func decodeBatchRequest(r *http.Request) (batch.Request, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		legs, err := batch.ParseCSV(r.Body)
+		if err != nil {
+			return batch.Request{}, err
+		}
+		query := r.URL.Query()
+		return batch.Request{
+			Mode:          batch.Mode(query.Get("mode")),
+			FromAccountID: query.Get("from_account_id"),
+			Legs:          legs,
+		}, nil
+	}
+
+	var req batch.Request
+	err := json.NewDecoder(r.Body).Decode(&req)
+	return req, err
+}
+
+// validateBatchAccounts replaces account numbers and IBANs in the pending legs
+// with account IDs, and marks the legs whose accounts do not exist or do not
+// match the leg's currency as invalid before anything is moved.
+func (h *Handler) validateBatchAccounts(r *http.Request, b *batch.Batch) {
+	accounts := make(map[string]*account.Account)
+	lookup := func(id string) (*account.Account, error) {
+		if acc, ok := accounts[id]; ok {
+			return acc, nil
+		}
+		acc, err := h.store.GetAccount(r.Context(), id)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = acc
+		return acc, nil
+	}
+
+	for i, leg := range b.Legs {
+		if leg.Status != batch.LegPending {
+			continue
+		}
+
+		fromAccount, err := lookup(leg.FromAccountID)
+		if err != nil {
+			h.batchService.Invalid(b, i, err)
+			continue
+		}
+		b.Legs[i].FromAccountID = fromAccount.ID
+		toAccount, err := lookup(leg.ToAccountID)
+		if err != nil {
+			h.batchService.Invalid(b, i, err)
+			continue
+		}
+		b.Legs[i].ToAccountID = toAccount.ID
+		if err := h.accountService.ValidateCurrency(fromAccount, leg.Currency); err != nil {
+			h.batchService.Invalid(b, i, err)
+			continue
+		}
+		if toAccount.CurrentCurrency() != fromAccount.CurrentCurrency() {
+			h.batchService.Invalid(b, i, &errors.ErrCurrencyMismatch{
+				AccountID: toAccount.ID,
+				Expected:  toAccount.CurrentCurrency(),
+				Actual:    fromAccount.CurrentCurrency(),
+			})
+		}
+	}
+}
+
+// runAllOrNothing makes every leg in one unit of work, so either all of them
+// are committed or, if any is invalid or declined, none.
+func (h *Handler) runAllOrNothing(r *http.Request, b *batch.Batch, now time.Time) {
+	if b.HasInvalid() {
+		h.batchService.Abort(b)
+		return
+	}
+
+	customerID := caller(r)
+	var staged *batch.Batch
+	failed := -1
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		staged, failed = b.Clone(), -1
+		for i, leg := range staged.Legs {
+			tx, err := h.transferLeg(uow, leg.Leg, customerID, now)
+			if err != nil {
+				failed = i
+				return err
+			}
+			tx.BatchID = b.ID
+			h.batchService.Succeeded(staged, i, tx.ID)
+		}
+		return nil
+	})
+	if err == nil {
+		*b = *staged
+		return
+	}
+
+	h.logger.WithError(err).WithField("batch_id", b.ID).Error("Batch declined")
+	if failed < 0 {
+		// Nothing was declined; the unit itself could not be committed.
+		for i := range b.Legs {
+			h.batchService.Failed(b, i, err, "")
+		}
+		return
+	}
+
+	*b = *staged
+	leg := b.Legs[failed]
+	h.batchService.Failed(b, failed, err, h.recordFailure(r.Context(), h.declinedBatchLeg(b, leg.Leg), err))
+	h.batchService.Abort(b)
+}
+
+// runBestEffort makes each valid leg in its own unit of work, so a declined
+// leg does not affect the others.
+func (h *Handler) runBestEffort(r *http.Request, b *batch.Batch, now time.Time) {
+	customerID := caller(r)
+	for i, leg := range b.Legs {
+		if leg.Status != batch.LegPending {
+			continue
+		}
+
+		var tx *transaction.Transaction
+		err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+			var err error
+			tx, err = h.transferLeg(uow, leg.Leg, customerID, now)
+			if err != nil {
+				return err
+			}
+			tx.BatchID = b.ID
+			return nil
+		})
+		if err != nil {
+			h.logger.WithError(err).WithFields(logrus.Fields{
+				"batch_id": b.ID,
+				"leg":      i,
+			}).Error("Batch leg declined")
+			h.batchService.Failed(b, i, err, h.recordFailure(r.Context(), h.declinedBatchLeg(b, leg.Leg), err))
+			continue
+		}
+		h.batchService.Succeeded(b, i, tx.ID)
+	}
+}
+
+// transferLeg makes one leg's transfer. A batch cannot wait for other
+// holders to approve a leg, so the caller must satisfy the paying account's
+// mandate alone.
+func (h *Handler) transferLeg(uow store.UnitOfWork, leg batch.Leg, customerID string, now time.Time) (*transaction.Transaction, error) {
+	from, err := uow.GetAccount(leg.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.accountService.AuthorizeAlone(from, customerID, "make batch payments"); err != nil {
+		return nil, err
+	}
+	return h.payments.Transfer(uow, leg.TransferRequest(), now)
+}
+
+func (h *Handler) declinedBatchLeg(b *batch.Batch, leg batch.Leg) *transaction.Transaction {
+	tx := h.declinedTransfer(leg.TransferRequest())
+	tx.BatchID = b.ID
+	return tx
+}
+
+// GetBatch handles GET /transactions/batch/{id}.
+func (h *Handler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	batchID := strings.TrimPrefix(r.URL.Path, "/transactions/batch/")
+	found, err := h.store.GetBatch(r.Context(), batchID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrBatchNotFound); ok {
+			h.writeError(w, http.StatusNotFound, "Batch not found")
+			return
+		}
+		h.logger.WithError(err).WithField("batch_id", batchID).Error("Failed to get batch")
+		h.writeError(w, http.StatusInternalServerError, "Failed to get batch")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, found)
+}
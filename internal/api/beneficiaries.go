@@ -0,0 +1,233 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/accountnumber"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/store"
+	"banking-service/pkg/errors"
+)
+
+type BeneficiaryList struct {
+	Beneficiaries []*beneficiary.Beneficiary `json:"beneficiaries"`
+}
+
+// AccountBeneficiaries handles GET and POST /accounts/{id}/beneficiaries,
+// which list the account's payees and add one.
+func (h *Handler) AccountBeneficiaries(w http.ResponseWriter, r *http.Request, accountID string) {
+	switch r.Method {
+	case "GET":
+		if _, err := h.store.GetAccount(r.Context(), accountID); err != nil {
+			h.writeBeneficiaryError(w, err, "Failed to list beneficiaries")
+			return
+		}
+
+		beneficiaries := h.store.ListBeneficiaries(r.Context(), accountID)
+		if beneficiaries == nil {
+			beneficiaries = []*beneficiary.Beneficiary{}
+		}
+		h.writeJSON(w, http.StatusOK, BeneficiaryList{Beneficiaries: beneficiaries})
+	case "POST":
+		h.idempotent(func(w http.ResponseWriter, r *http.Request) {
+			h.addBeneficiary(w, r, accountID)
+		})(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// AccountBeneficiary handles GET and DELETE /accounts/{id}/beneficiaries/{bid}
+// and POST /accounts/{id}/beneficiaries/{bid}/verify.
+func (h *Handler) AccountBeneficiary(w http.ResponseWriter, r *http.Request, accountID, beneficiaryID, action string) {
+	switch {
+	case action == "verify" && r.Method == "POST":
+		h.verifyBeneficiary(w, r, accountID, beneficiaryID)
+	case action != "" && action != "verify":
+		h.writeError(w, http.StatusNotFound, "Not found")
+	case action == "" && r.Method == "GET":
+		b, err := h.store.GetBeneficiary(r.Context(), beneficiaryID)
+		if err == nil && b.AccountID != accountID {
+			err = &errors.ErrBeneficiaryNotFound{BeneficiaryID: beneficiaryID}
+		}
+		if err != nil {
+			h.writeBeneficiaryError(w, err, "Failed to get beneficiary")
+			return
+		}
+		h.writeJSON(w, http.StatusOK, b)
+	case action == "" && r.Method == "DELETE":
+		h.removeBeneficiary(w, r, accountID, beneficiaryID)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) addBeneficiary(w http.ResponseWriter, r *http.Request, accountID string) {
+	var req beneficiary.AddBeneficiaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode beneficiary request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := accountnumber.Check(req.PayeeAccountID); err != nil {
+		h.writeBeneficiaryError(w, err, "Failed to add beneficiary")
+		return
+	}
+	payeeID := req.PayeeAccountID
+	if payee, err := h.store.GetAccount(r.Context(), payeeID); err == nil {
+		payeeID = payee.ID
+	}
+
+	customerID := caller(r)
+	var added *beneficiary.Beneficiary
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		acc, err := h.beneficiaryAccount(uow, accountID, customerID)
+		if err != nil {
+			return err
+		}
+		payee, err := uow.GetAccount(payeeID)
+		if err != nil {
+			return err
+		}
+		if existing := uow.FindBeneficiary(acc.ID, payee.ID); existing != nil {
+			return &errors.ErrBeneficiaryExists{AccountID: acc.ID, PayeeAccountID: payee.ID, BeneficiaryID: existing.ID}
+		}
+
+		added, err = h.beneficiaryService.Add(acc, payee, req, customerID, time.Now())
+		if err != nil {
+			return err
+		}
+		return uow.SaveBeneficiary(added)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id":       accountID,
+			"payee_account_id": req.PayeeAccountID,
+		}).Error("Failed to add beneficiary")
+		h.writeBeneficiaryError(w, err, "Failed to add beneficiary")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"beneficiary_id":    added.ID,
+		"account_id":        added.AccountID,
+		"payee_account_id":  added.PayeeAccountID,
+		"cooling_off_until": added.CoolingOffUntil,
+	}).Info("Beneficiary added")
+
+	h.writeJSON(w, http.StatusCreated, added)
+}
+
+func (h *Handler) verifyBeneficiary(w http.ResponseWriter, r *http.Request, accountID, beneficiaryID string) {
+	var req beneficiary.VerifyBeneficiaryRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.logger.WithError(err).Error("Failed to decode verify beneficiary request")
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	customerID := caller(r)
+	var verified *beneficiary.Beneficiary
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		b, err := h.beneficiaryFor(uow, accountID, beneficiaryID, customerID)
+		if err != nil {
+			return err
+		}
+		payee, err := uow.GetAccount(b.PayeeAccountID)
+		if err != nil {
+			return err
+		}
+
+		h.beneficiaryService.Verify(b, payee, req, time.Now())
+		verified = b
+		return uow.SaveBeneficiary(b)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("beneficiary_id", beneficiaryID).Error("Failed to verify beneficiary")
+		h.writeBeneficiaryError(w, err, "Failed to verify beneficiary")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"beneficiary_id": verified.ID,
+		"name_match":     verified.NameMatch,
+	}).Info("Beneficiary verified")
+
+	h.writeJSON(w, http.StatusOK, verified)
+}
+
+func (h *Handler) removeBeneficiary(w http.ResponseWriter, r *http.Request, accountID, beneficiaryID string) {
+	customerID := caller(r)
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		b, err := h.beneficiaryFor(uow, accountID, beneficiaryID, customerID)
+		if err != nil {
+			return err
+		}
+		return uow.DeleteBeneficiary(b.ID)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("beneficiary_id", beneficiaryID).Error("Failed to remove beneficiary")
+		h.writeBeneficiaryError(w, err, "Failed to remove beneficiary")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"beneficiary_id": beneficiaryID,
+		"account_id":     accountID,
+	}).Info("Beneficiary removed")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// beneficiaryAccount returns the account once the caller is confirmed as one
+// of its signing holders, who alone may change its payees.
+func (h *Handler) beneficiaryAccount(uow store.UnitOfWork, accountID, customerID string) (*account.Account, error) {
+	acc, err := uow.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.accountService.Authorize(acc, customerID, "manage beneficiaries"); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// beneficiaryFor returns the account's beneficiary for the caller to change.
+func (h *Handler) beneficiaryFor(uow store.UnitOfWork, accountID, beneficiaryID, customerID string) (*beneficiary.Beneficiary, error) {
+	b, err := uow.GetBeneficiary(beneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+	if b.AccountID != accountID {
+		return nil, &errors.ErrBeneficiaryNotFound{BeneficiaryID: beneficiaryID}
+	}
+	if _, err := h.beneficiaryAccount(uow, accountID, customerID); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (h *Handler) writeBeneficiaryError(w http.ResponseWriter, err error, message string) {
+	switch err.(type) {
+	case *errors.ErrBeneficiaryNotFound:
+		h.writeError(w, http.StatusNotFound, "Beneficiary not found")
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrCallerNotAuthorized:
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case *errors.ErrInvalidParameter, *errors.ErrInvalidAccountNumber:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrBeneficiaryExists, *errors.ErrAccountClosed:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/customer"
+	"banking-service/internal/store"
+	"banking-service/pkg/errors"
+	"banking-service/pkg/money"
+)
+
+type CustomerList struct {
+	Customers []*customer.Customer `json:"customers"`
+}
+
+// CustomerAccountsResponse lists a customer's accounts with their balances
+// totalled per currency and, in Total, across currencies at current rates.
+type CustomerAccountsResponse struct {
+	CustomerID string             `json:"customer_id"`
+	Accounts   []AccountResponse  `json:"accounts"`
+	Balances   []customer.Balance `json:"balances"`
+	Total      money.Money        `json:"total"`
+}
+
+type LinkCustomerRequest struct {
+	CustomerID string `json:"customer_id"`
+}
+
+// Customers handles POST /customers, which creates a customer, and
+// GET /customers, which lists them.
+func (h *Handler) Customers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		customers := h.store.ListCustomers(r.Context())
+		h.writeJSON(w, http.StatusOK, CustomerList{Customers: customers})
+	case "POST":
+		h.idempotent(h.CreateCustomer)(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req customer.CreateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode create customer request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	created, err := h.customerService.Create(req, time.Now())
+	if err != nil {
+		h.writeCustomerError(w, err, "Failed to create customer")
+		return
+	}
+
+	err = h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		return uow.SaveCustomer(created)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("customer_id", created.ID).Error("Failed to store customer")
+		h.writeCustomerError(w, err, "Failed to create customer")
+		return
+	}
+
+	h.logger.WithField("customer_id", created.ID).Info("Customer created")
+
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+// Customer handles GET, PATCH and DELETE /customers/{id}. A customer can only
+// be deleted once all of their accounts are closed.
+func (h *Handler) Customer(w http.ResponseWriter, r *http.Request, customerID string) {
+	switch r.Method {
+	case "GET":
+		found, err := h.store.GetCustomer(r.Context(), customerID)
+		if err != nil {
+			h.writeCustomerError(w, err, "Failed to get customer")
+			return
+		}
+		h.writeJSON(w, http.StatusOK, found)
+	case "PATCH":
+		h.updateCustomer(w, r, customerID)
+	case "DELETE":
+		h.deleteCustomer(w, r, customerID)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) updateCustomer(w http.ResponseWriter, r *http.Request, customerID string) {
+	var req customer.UpdateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode update customer request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var updated *customer.Customer
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		c, err := uow.GetCustomer(customerID)
+		if err != nil {
+			return err
+		}
+		if err := h.customerService.Update(c, req, time.Now()); err != nil {
+			return err
+		}
+
+		// Keep the owner name on the accounts the customer is the primary
+		// holder of in step.
+		if req.Name != nil {
+			for _, listed := range uow.ListCustomerAccounts(c.ID) {
+				if listed.CustomerID != c.ID || listed.CustomerName == c.Name || listed.CurrentStatus() == account.StatusClosed {
+					continue
+				}
+				acc, err := uow.GetAccount(listed.ID)
+				if err != nil {
+					return err
+				}
+				if err := h.accountService.LinkCustomer(acc, c.ID, c.Name); err != nil {
+					return err
+				}
+			}
+		}
+
+		updated = c
+		return uow.SaveCustomer(c)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("customer_id", customerID).Error("Failed to update customer")
+		h.writeCustomerError(w, err, "Failed to update customer")
+		return
+	}
+
+	h.logger.WithField("customer_id", updated.ID).Info("Customer updated")
+
+	h.writeJSON(w, http.StatusOK, updated)
+}
+
+func (h *Handler) deleteCustomer(w http.ResponseWriter, r *http.Request, customerID string) {
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		c, err := uow.GetCustomer(customerID)
+		if err != nil {
+			return err
+		}
+		if err := h.customerService.CanDelete(c, uow.ListCustomerAccounts(c.ID)); err != nil {
+			return err
+		}
+		return uow.DeleteCustomer(c.ID)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("customer_id", customerID).Error("Failed to delete customer")
+		h.writeCustomerError(w, err, "Failed to delete customer")
+		return
+	}
+
+	h.logger.WithField("customer_id", customerID).Info("Customer deleted")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CustomerAccounts handles GET /customers/{id}/accounts. The consolidated
+// total is in the currency query parameter, defaulting to the currency of the
+// customer's accounts if they share one.
+func (h *Handler) CustomerAccounts(w http.ResponseWriter, r *http.Request, customerID string) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, err := h.store.GetCustomer(r.Context(), customerID); err != nil {
+		h.writeCustomerError(w, err, "Failed to get customer accounts")
+		return
+	}
+	accounts := h.store.ListCustomerAccounts(r.Context(), customerID)
+
+	resp := CustomerAccountsResponse{
+		CustomerID: customerID,
+		Accounts:   make([]AccountResponse, 0, len(accounts)),
+		Balances:   h.customerService.Balances(accounts),
+	}
+	for _, acc := range accounts {
+		resp.Accounts = append(resp.Accounts, newAccountResponse(acc))
+	}
+
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		currency = money.DefaultCurrency
+		if len(resp.Balances) == 1 {
+			currency = resp.Balances[0].Currency
+		}
+	}
+	if err := money.ValidateCurrency(currency); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp.Total = money.Money{Currency: currency}
+	for _, balance := range resp.Balances {
+		converted, err := h.fxService.Convert(r.Context(), money.Money{Amount: balance.Balance, Currency: balance.Currency}, currency)
+		if err != nil {
+			h.logger.WithError(err).WithField("customer_id", customerID).Error("Failed to consolidate balances")
+			h.writeCustomerError(w, err, "Failed to consolidate balances")
+			return
+		}
+		resp.Total.Amount += converted.Amount
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// LinkAccountCustomer handles POST /accounts/{id}/customer, which moves an
+// existing account to a customer.
+func (h *Handler) LinkAccountCustomer(w http.ResponseWriter, r *http.Request, accountID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req LinkCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode link customer request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var linked *account.Account
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		c, err := uow.GetCustomer(req.CustomerID)
+		if err != nil {
+			return err
+		}
+		acc, err := uow.GetAccount(accountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.LinkCustomer(acc, c.ID, c.Name); err != nil {
+			return err
+		}
+
+		linked = acc
+		return nil
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id":  accountID,
+			"customer_id": req.CustomerID,
+		}).Error("Failed to link account to customer")
+		h.writeCustomerError(w, err, "Failed to link account to customer")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id":  linked.ID,
+		"customer_id": linked.CustomerID,
+	}).Info("Account linked to customer")
+
+	h.writeJSON(w, http.StatusOK, newAccountResponse(linked))
+}
+
+func (h *Handler) writeCustomerError(w http.ResponseWriter, err error, message string) {
+	switch err.(type) {
+	case *errors.ErrCustomerNotFound:
+		h.writeError(w, http.StatusNotFound, "Customer not found")
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrInvalidCustomerName, *errors.ErrInvalidParameter:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrRateUnavailable:
+		h.writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case *errors.ErrCustomerHasAccounts, *errors.ErrAccountClosed:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"banking-service/internal/account"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// QuoteTransaction handles POST /transactions/quote: it previews the fee for
+// a withdrawal or transfer without moving any money.
+func (h *Handler) QuoteTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req transaction.QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode quote request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	op, err := quoteOperation(req)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	acc, err := h.store.GetAccount(r.Context(), req.AccountID)
+	if err != nil {
+		h.logger.WithError(err).WithField("account_id", req.AccountID).Error("Failed to quote transaction")
+
+		if _, ok := err.(*errors.ErrAccountNotFound); ok {
+			h.writeError(w, http.StatusNotFound, "Account not found")
+		} else {
+			h.writeError(w, http.StatusInternalServerError, "Failed to quote transaction")
+		}
+		return
+	}
+
+	fee := h.accountService.Fee(acc, op, req.Amount)
+	h.writeJSON(w, http.StatusOK, transaction.QuoteResponse{
+		Type:      req.Type,
+		AccountID: acc.ID,
+		Amount:    req.Amount,
+		Fee:       fee,
+		Total:     req.Amount + fee,
+		Currency:  acc.CurrentCurrency(),
+	})
+}
+
+// quoteOperation validates req and returns the operation it prices.
+func quoteOperation(req transaction.QuoteRequest) (account.Operation, error) {
+	if req.Amount <= 0 {
+		return "", &errors.ErrInvalidAmount{Amount: req.Amount}
+	}
+
+	switch req.Type {
+	case transaction.TransactionTypeWithdrawal:
+		return account.OperationWithdrawal, nil
+	case transaction.TransactionTypeTransfer:
+		return account.OperationTransfer, nil
+	default:
+		return "", &errors.ErrInvalidParameter{Name: "type", Value: string(req.Type)}
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/fx"
+	"banking-service/pkg/errors"
+)
+
+// CreateQuote handles POST /fx/quotes.
+func (h *Handler) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req fx.QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode quote request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	quote, err := h.fxService.Quote(r.Context(), req, time.Now())
+	if err == nil {
+		err = h.store.SaveQuote(r.Context(), quote)
+	}
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"from_currency": req.FromCurrency,
+			"to_currency":   req.ToCurrency,
+			"amount":        req.Amount,
+		}).Error("Failed to create fx quote")
+
+		switch err.(type) {
+		case *errors.ErrInvalidAmount, *errors.ErrUnsupportedCurrency, *errors.ErrInvalidConversion:
+			h.writeError(w, http.StatusBadRequest, err.Error())
+		case *errors.ErrRateUnavailable:
+			h.writeError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			h.writeError(w, http.StatusInternalServerError, "Failed to create quote")
+		}
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"quote_id":   quote.ID,
+		"rate":       quote.Rate,
+		"expires_at": quote.ExpiresAt,
+	}).Info("FX quote created")
+
+	h.writeJSON(w, http.StatusCreated, quote)
+}
+
+// GetQuote handles GET /fx/quotes/{id}.
+func (h *Handler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/fx/quotes/")
+	if id == "" || strings.Contains(id, "/") {
+		h.writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	quote, err := h.store.GetQuote(r.Context(), id)
+	if err != nil {
+		if _, ok := err.(*errors.ErrQuoteNotFound); ok {
+			h.writeError(w, http.StatusNotFound, "Quote not found")
+		} else {
+			h.logger.WithError(err).WithField("quote_id", id).Error("Failed to get fx quote")
+			h.writeError(w, http.StatusInternalServerError, "Failed to get quote")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, quote)
+}
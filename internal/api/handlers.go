@@ -1,37 +1,109 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"banking-service/internal/account"
+	"banking-service/internal/accountnumber"
+	"banking-service/internal/approval"
+	"banking-service/internal/batch"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/config"
+	"banking-service/internal/customer"
+	"banking-service/internal/fx"
+	"banking-service/internal/hold"
+	"banking-service/internal/idempotency"
+	"banking-service/internal/ledger"
+	"banking-service/internal/limit"
+	"banking-service/internal/payment"
+	"banking-service/internal/standingorder"
 	"banking-service/internal/store"
 	"banking-service/internal/transaction"
 	"banking-service/pkg/errors"
 )
 
 type Handler struct {
-	store           *store.Store
+	store           store.Repository
 	accountService  *account.Service
 	transactionService *transaction.Service
+	ledgerService   *ledger.Service
+	fxService       *fx.Service
+	holdService     *hold.Service
+	standingOrderService *standingorder.Service
+	batchService    *batch.Service
+	customerService *customer.Service
+	approvalService *approval.Service
+	beneficiaryService *beneficiary.Service
+	numbers         *accountnumber.Service
+	payments        *payment.Processor
+	transfers       *payment.Dispatcher
 	logger          *logrus.Logger
+	config          *config.Config
 }
 
-func NewHandler(store *store.Store, logger *logrus.Logger) *Handler {
+func NewHandler(store store.Repository, logger *logrus.Logger, cfg *config.Config, rates fx.RateProvider, fees account.FeePolicy, limits []limit.Limit, numbers *accountnumber.Service, payees *beneficiary.Service, transfers *payment.Dispatcher) *Handler {
+	accountService := account.NewServiceWithFees(fees)
+	transactionService := transaction.NewService()
+	ledgerService := ledger.NewService()
+	
 	return &Handler{
 		store:             store,
-		accountService:    account.NewService(),
-		transactionService: transaction.NewService(),
+		accountService:    accountService,
+		transactionService: transactionService,
+		ledgerService:     ledgerService,
+		fxService:         fx.NewService(rates, cfg.FXQuoteTTL),
+		holdService:       hold.NewService(cfg.HoldTTL),
+		standingOrderService: standingorder.NewService(cfg.StandingOrderMaxRetries, cfg.StandingOrderRetryDelay),
+		batchService:      batch.NewService(),
+		customerService:   customer.NewService(),
+		approvalService:   approval.NewService(cfg.ApprovalTTL),
+		beneficiaryService: payees,
+		numbers:           numbers,
+		payments:          payment.NewProcessor(accountService, transactionService, ledgerService, limits, payees),
+		transfers:         transfers,
 		logger:            logger,
+		config:            cfg,
 	}
 }
 
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	
+	// TransactionID identifies the failed transaction recorded for a
+	// declined money movement.
+	TransactionID string `json:"transaction_id,omitempty"`
+	
+	// Limit describes the limit a declined transaction would have exceeded.
+	Limit *LimitAllowance `json:"limit,omitempty"`
+}
+
+// LimitAllowance is what is left of a transaction limit in the current
+// period, in the limit's measure: an amount or a number of transactions.
+type LimitAllowance struct {
+	Type      string `json:"type"`
+	Period    string `json:"period"`
+	Measure   string `json:"measure"`
+	Max       int64  `json:"max"`
+	Remaining int64  `json:"remaining"`
+}
+
+// AccountResponse is an account as returned by the API, with the balance
+// left over after active holds and, on GET, its open pots added up.
+type AccountResponse struct {
+	*account.Account
+	AvailableBalance int64 `json:"available_balance"`
+	*PotSummary
+}
+
+func newAccountResponse(acc *account.Account) AccountResponse {
+	return AccountResponse{Account: acc, AvailableBalance: acc.AvailableBalance()}
 }
 
 func (h *Handler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
@@ -51,6 +123,76 @@ func (h *Handler) writeError(w http.ResponseWriter, statusCode int, message stri
 	})
 }
 
+// writeDeclined is writeError for money movements, pointing the client at the
+// failed transaction recorded for the request, if any.
+func (h *Handler) writeDeclined(w http.ResponseWriter, statusCode int, message, transactionID string) {
+	h.writeJSON(w, statusCode, ErrorResponse{
+		Error:         http.StatusText(statusCode),
+		Message:       message,
+		TransactionID: transactionID,
+	})
+}
+
+// writeLimitExceeded declines a money movement that would break a limit with
+// 422 and the remaining allowance.
+func (h *Handler) writeLimitExceeded(w http.ResponseWriter, err *errors.ErrLimitExceeded, transactionID string) {
+	h.writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{
+		Error:         http.StatusText(http.StatusUnprocessableEntity),
+		Message:       err.Error(),
+		TransactionID: transactionID,
+		Limit: &LimitAllowance{
+			Type:      err.TransactionType,
+			Period:    err.Period,
+			Measure:   err.Measure,
+			Max:       err.Max,
+			Remaining: err.Remaining,
+		},
+	})
+}
+
+// recordFailure stores tx as failed when err declined the request, so that
+// declined attempts show up in the account history. Nothing is recorded when
+// none of the accounts exist, since no history would show it. It returns the
+// failed transaction's ID, or "" if nothing was recorded.
+func (h *Handler) recordFailure(ctx context.Context, tx *transaction.Transaction, err error) string {
+	code := errors.Code(err)
+	if code == "" || !h.anyAccountExists(ctx, tx.AccountID, tx.FromAccountID, tx.ToAccountID) {
+		return ""
+	}
+	
+	h.transactionService.MarkFailed(tx, code, err.Error())
+	if err := h.store.StoreTransaction(ctx, tx); err != nil {
+		h.logger.WithError(err).WithField("transaction_id", tx.ID).Error("Failed to record failed transaction")
+		return ""
+	}
+	return tx.ID
+}
+
+func (h *Handler) anyAccountExists(ctx context.Context, ids ...string) bool {
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, err := h.store.GetAccount(ctx, id); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// apply runs fn as a single store unit of work. The store runs units of work
+// one at a time, so fn always sees the latest committed state. Under an
+// Idempotency-Key, the key is marked as committed along with the changes.
+func (h *Handler) apply(ctx context.Context, fn func(uow store.UnitOfWork) error) error {
+	key := idempotency.KeyFrom(ctx)
+	return h.store.Apply(ctx, func(uow store.UnitOfWork) error {
+		if err := fn(uow); err != nil || key == "" {
+			return err
+		}
+		return uow.CommitIdempotencyKey(key)
+	})
+}
+
 func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -64,12 +206,33 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	if req.CustomerID != "" {
+		owner, err := h.store.GetCustomer(r.Context(), req.CustomerID)
+		if err != nil {
+			h.logger.WithError(err).WithField("customer_id", req.CustomerID).Error("Failed to get account customer")
+			
+			if _, ok := err.(*errors.ErrCustomerNotFound); ok {
+				h.writeError(w, http.StatusNotFound, "Customer not found")
+			} else {
+				h.writeError(w, http.StatusInternalServerError, "Failed to create account")
+			}
+			return
+		}
+		if req.CustomerName == "" {
+			req.CustomerName = owner.Name
+		}
+	}
+	
 	acc, err := h.accountService.CreateAccount(req)
+	var prefix string
+	if err == nil {
+		prefix, err = h.numbers.Prefix(req.Branch, acc.CurrentType())
+	}
 	if err != nil {
 		h.logger.WithError(err).WithField("customer_name", req.CustomerName).Error("Failed to create account")
 		
 		switch err.(type) {
-		case *errors.ErrInvalidCustomerName, *errors.ErrInvalidInitialBalance:
+		case *errors.ErrInvalidCustomerName, *errors.ErrInvalidInitialBalance, *errors.ErrUnsupportedCurrency, *errors.ErrInvalidParameter:
 			h.writeError(w, http.StatusBadRequest, err.Error())
 		default:
 			h.writeError(w, http.StatusInternalServerError, "Failed to create account")
@@ -77,19 +240,44 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	if err := h.store.CreateAccount(acc); err != nil {
+	err = h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		// The customer may have been deleted since it was looked up.
+		if acc.CustomerID != "" {
+			if _, err := uow.GetCustomer(acc.CustomerID); err != nil {
+				return err
+			}
+		}
+		if err := h.numbers.Assign(acc, prefix, uow.NextAccountSequence(prefix)); err != nil {
+			return err
+		}
+		if err := uow.CreateAccount(acc); err != nil {
+			return err
+		}
+		if entry := h.ledgerService.OpeningEntry(acc); entry != nil {
+			return uow.PostEntry(entry)
+		}
+		return nil
+	})
+	if err != nil {
 		h.logger.WithError(err).WithField("account_id", acc.ID).Error("Failed to store account")
-		h.writeError(w, http.StatusInternalServerError, "Failed to create account")
+		
+		switch err.(type) {
+		case *errors.ErrCustomerNotFound:
+			h.writeError(w, http.StatusNotFound, "Customer not found")
+		default:
+			h.writeError(w, http.StatusInternalServerError, "Failed to create account")
+		}
 		return
 	}
 	
 	h.logger.WithFields(logrus.Fields{
 		"account_id": acc.ID,
+		"account_number": acc.Number,
 		"customer_name": acc.CustomerName,
 		"balance": acc.Balance,
 	}).Info("Account created successfully")
 	
-	h.writeJSON(w, http.StatusCreated, acc)
+	h.writeJSON(w, http.StatusCreated, newAccountResponse(acc))
 }
 
 func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
@@ -104,7 +292,7 @@ func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	acc, err := h.store.GetAccount(path)
+	acc, err := h.store.GetAccount(r.Context(), path)
 	if err != nil {
 		h.logger.WithError(err).WithField("account_id", path).Error("Failed to get account")
 		
@@ -117,7 +305,7 @@ func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	h.logger.WithField("account_id", path).Info("Account retrieved successfully")
-	h.writeJSON(w, http.StatusOK, acc)
+	h.writeJSON(w, http.StatusOK, h.accountResponse(r.Context(), acc))
 }
 
 func (h *Handler) Deposit(w http.ResponseWriter, r *http.Request) {
@@ -132,49 +320,57 @@ func (h *Handler) Deposit(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
-	
-	acc, err := h.store.GetAccount(req.AccountID)
-	if err != nil {
-		h.logger.WithError(err).WithField("account_id", req.AccountID).Error("Failed to get account for deposit")
+	req.AccountID = h.accountID(r.Context(), req.AccountID)
+	
+	var tx *transaction.Transaction
+	var newBalance int64
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount(req.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.ValidateCurrency(acc, req.Currency); err != nil {
+			return err
+		}
+		if err := h.accountService.Deposit(acc, req.Amount); err != nil {
+			return err
+		}
 		
-		if _, ok := err.(*errors.ErrAccountNotFound); ok {
-			h.writeError(w, http.StatusNotFound, "Account not found")
-		} else {
-			h.writeError(w, http.StatusInternalServerError, "Failed to process deposit")
+		tx = h.transactionService.CreateDepositTransaction(req.AccountID, req.Amount, acc.CurrentCurrency())
+		if err := h.payments.CheckLimits(uow, acc, tx); err != nil {
+			return err
 		}
-		return
-	}
-	
-	if err := h.accountService.Deposit(acc, req.Amount); err != nil {
+		newBalance = acc.Balance
+		return h.payments.Record(uow, tx)
+	})
+	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
 			"account_id": req.AccountID,
 			"amount": req.Amount,
 		}).Error("Failed to process deposit")
 		
-		if _, ok := err.(*errors.ErrInvalidAmount); ok {
-			h.writeError(w, http.StatusBadRequest, err.Error())
-		} else {
+		failedID := h.recordFailure(r.Context(), h.transactionService.CreateDepositTransaction(req.AccountID, req.Amount, req.Currency), err)
+		
+		switch e := err.(type) {
+		case *errors.ErrAccountNotFound:
+			h.writeDeclined(w, http.StatusNotFound, "Account not found", failedID)
+		case *errors.ErrInvalidAmount, *errors.ErrCurrencyMismatch:
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
+		case *errors.ErrAccountFrozen, *errors.ErrAccountDormant, *errors.ErrAccountClosed, *errors.ErrPotRestricted:
+			h.writeDeclined(w, http.StatusConflict, err.Error(), failedID)
+		case *errors.ErrLimitExceeded:
+			h.writeLimitExceeded(w, e, failedID)
+		default:
 			h.writeError(w, http.StatusInternalServerError, "Failed to process deposit")
 		}
 		return
 	}
 	
-	if err := h.store.UpdateAccount(acc); err != nil {
-		h.logger.WithError(err).WithField("account_id", acc.ID).Error("Failed to update account after deposit")
-		h.writeError(w, http.StatusInternalServerError, "Failed to process deposit")
-		return
-	}
-	
-	tx := h.transactionService.CreateDepositTransaction(req.AccountID, req.Amount)
-	if err := h.store.StoreTransaction(tx); err != nil {
-		h.logger.WithError(err).WithField("transaction_id", tx.ID).Error("Failed to store deposit transaction")
-	}
-	
 	h.logger.WithFields(logrus.Fields{
 		"account_id": req.AccountID,
 		"amount": req.Amount,
 		"transaction_id": tx.ID,
-		"new_balance": acc.Balance,
+		"new_balance": newBalance,
 	}).Info("Deposit processed successfully")
 	
 	h.writeJSON(w, http.StatusOK, transaction.TransactionResponse{
@@ -195,52 +391,81 @@ func (h *Handler) Withdraw(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
-	
-	acc, err := h.store.GetAccount(req.AccountID)
-	if err != nil {
-		h.logger.WithError(err).WithField("account_id", req.AccountID).Error("Failed to get account for withdrawal")
+	req.AccountID = h.accountID(r.Context(), req.AccountID)
+	
+	customerID := caller(r)
+	
+	var tx *transaction.Transaction
+	var pending *approval.Approval
+	var newBalance int64
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		pending = nil
+		acc, err := uow.GetAccount(req.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.ValidateCurrency(acc, req.Currency); err != nil {
+			return err
+		}
+		wait, err := h.authorizePayment(acc, customerID, "withdraw", req.Amount)
+		if err != nil {
+			return err
+		}
+		if wait {
+			pending = h.approvalService.Withdrawal(acc, req, customerID, time.Now())
+			return uow.SaveApproval(pending)
+		}
 		
-		if _, ok := err.(*errors.ErrAccountNotFound); ok {
-			h.writeError(w, http.StatusNotFound, "Account not found")
-		} else {
-			h.writeError(w, http.StatusInternalServerError, "Failed to process withdrawal")
+		tx, err = h.withdraw(uow, req)
+		if err != nil {
+			return err
 		}
-		return
-	}
-	
-	if err := h.accountService.Withdraw(acc, req.Amount); err != nil {
+		newBalance = acc.Balance
+		return nil
+	})
+	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
 			"account_id": req.AccountID,
 			"amount": req.Amount,
 		}).Error("Failed to process withdrawal")
 		
-		switch err.(type) {
-		case *errors.ErrInvalidAmount:
-			h.writeError(w, http.StatusBadRequest, err.Error())
+		failedID := h.recordFailure(r.Context(), h.transactionService.CreateWithdrawalTransaction(req.AccountID, req.Amount, req.Currency), err)
+		
+		switch e := err.(type) {
+		case *errors.ErrAccountNotFound:
+			h.writeDeclined(w, http.StatusNotFound, "Account not found", failedID)
+		case *errors.ErrInvalidAmount, *errors.ErrCurrencyMismatch:
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
 		case *errors.ErrInsufficientFunds:
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
+		case *errors.ErrCallerNotAuthorized:
+			h.writeDeclined(w, http.StatusForbidden, err.Error(), failedID)
+		case *errors.ErrAccountFrozen, *errors.ErrAccountDormant, *errors.ErrAccountClosed, *errors.ErrPotRestricted:
+			h.writeDeclined(w, http.StatusConflict, err.Error(), failedID)
+		case *errors.ErrLimitExceeded:
+			h.writeLimitExceeded(w, e, failedID)
 		default:
 			h.writeError(w, http.StatusInternalServerError, "Failed to process withdrawal")
 		}
 		return
 	}
 	
-	if err := h.store.UpdateAccount(acc); err != nil {
-		h.logger.WithError(err).WithField("account_id", acc.ID).Error("Failed to update account after withdrawal")
-		h.writeError(w, http.StatusInternalServerError, "Failed to process withdrawal")
+	if pending != nil {
+		h.logger.WithFields(logrus.Fields{
+			"account_id": req.AccountID,
+			"amount": req.Amount,
+			"approval_id": pending.ID,
+		}).Info("Withdrawal waiting for approval")
+		
+		h.writeJSON(w, http.StatusAccepted, pending)
 		return
 	}
 	
-	tx := h.transactionService.CreateWithdrawalTransaction(req.AccountID, req.Amount)
-	if err := h.store.StoreTransaction(tx); err != nil {
-		h.logger.WithError(err).WithField("transaction_id", tx.ID).Error("Failed to store withdrawal transaction")
-	}
-	
 	h.logger.WithFields(logrus.Fields{
 		"account_id": req.AccountID,
 		"amount": req.Amount,
 		"transaction_id": tx.ID,
-		"new_balance": acc.Balance,
+		"new_balance": newBalance,
 	}).Info("Withdrawal processed successfully")
 	
 	h.writeJSON(w, http.StatusOK, transaction.TransactionResponse{
@@ -249,6 +474,31 @@ func (h *Handler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// withdraw takes req.Amount and any fee out of the account and records the
+// withdrawal.
+func (h *Handler) withdraw(uow store.UnitOfWork, req transaction.WithdrawRequest) (*transaction.Transaction, error) {
+	acc, err := uow.GetAccount(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.accountService.ValidateCurrency(acc, req.Currency); err != nil {
+		return nil, err
+	}
+	fee, err := h.accountService.Withdraw(acc, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	
+	tx := h.transactionService.CreateWithdrawalTransaction(req.AccountID, req.Amount, acc.CurrentCurrency())
+	if err := h.payments.CheckLimits(uow, acc, tx); err != nil {
+		return nil, err
+	}
+	if err := h.payments.RecordFee(uow, tx, fee); err != nil {
+		return nil, err
+	}
+	return tx, h.payments.Record(uow, tx)
+}
+
 func (h *Handler) Transfer(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -262,78 +512,182 @@ func (h *Handler) Transfer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	fromAccount, err := h.store.GetAccount(req.FromAccountID)
-	if err != nil {
-		h.logger.WithError(err).WithField("from_account_id", req.FromAccountID).Error("Failed to get from account for transfer")
-		
-		if _, ok := err.(*errors.ErrAccountNotFound); ok {
-			h.writeError(w, http.StatusNotFound, "From account not found")
-		} else {
-			h.writeError(w, http.StatusInternalServerError, "Failed to process transfer")
-		}
-		return
+	async := h.asyncTransfer(req.Amount)
+	customerID := caller(r)
+	
+	var tx *transaction.Transaction
+	var pending *approval.Approval
+	err := h.resolveTransfer(r.Context(), &req)
+	if err == nil {
+		err = h.apply(r.Context(), func(uow store.UnitOfWork) error {
+			from, err := uow.GetAccount(req.FromAccountID)
+			if err != nil {
+				return err
+			}
+			wait, err := h.authorizePayment(from, customerID, "transfer", req.Amount)
+			if err != nil {
+				return err
+			}
+			if wait {
+				if req.FromAccountID == req.ToAccountID {
+					return &errors.ErrSameAccountTransfer{FromAccountID: req.FromAccountID, ToAccountID: req.ToAccountID}
+				}
+				pending = h.approvalService.Transfer(from, req, customerID, time.Now())
+				return uow.SaveApproval(pending)
+			}
+			
+			tx, err = h.transfer(uow, req, async)
+			return err
+		})
 	}
-	
-	toAccount, err := h.store.GetAccount(req.ToAccountID)
 	if err != nil {
-		h.logger.WithError(err).WithField("to_account_id", req.ToAccountID).Error("Failed to get to account for transfer")
-		
-		if _, ok := err.(*errors.ErrAccountNotFound); ok {
-			h.writeError(w, http.StatusNotFound, "To account not found")
-		} else {
-			h.writeError(w, http.StatusInternalServerError, "Failed to process transfer")
-		}
-		return
-	}
-	
-	if err := h.accountService.Transfer(fromAccount, toAccount, req.Amount); err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
 			"from_account_id": req.FromAccountID,
 			"to_account_id": req.ToAccountID,
 			"amount": req.Amount,
 		}).Error("Failed to process transfer")
 		
-		switch err.(type) {
-		case *errors.ErrInvalidAmount:
-			h.writeError(w, http.StatusBadRequest, err.Error())
+		failedID := h.recordFailure(r.Context(), h.declinedTransfer(req), err)
+		
+		switch e := err.(type) {
+		case *errors.ErrAccountNotFound:
+			if e.AccountID == req.FromAccountID {
+				h.writeDeclined(w, http.StatusNotFound, "From account not found", failedID)
+			} else {
+				h.writeDeclined(w, http.StatusNotFound, "To account not found", failedID)
+			}
+		case *errors.ErrInvalidAmount, *errors.ErrCurrencyMismatch:
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
 		case *errors.ErrInsufficientFunds:
-			h.writeError(w, http.StatusBadRequest, err.Error())
-		case *errors.ErrSameAccountTransfer:
-			h.writeError(w, http.StatusBadRequest, err.Error())
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
+		case *errors.ErrSameAccountTransfer, *errors.ErrInvalidAccountNumber:
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
+		case *errors.ErrInvalidConversion, *errors.ErrUnsupportedCurrency:
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
+		case *errors.ErrQuoteNotFound:
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
+		case *errors.ErrQuoteExpired, *errors.ErrQuoteAlreadyUsed:
+			h.writeDeclined(w, http.StatusConflict, err.Error(), failedID)
+		case *errors.ErrCallerNotAuthorized:
+			h.writeDeclined(w, http.StatusForbidden, err.Error(), failedID)
+		case *errors.ErrAccountFrozen, *errors.ErrAccountDormant, *errors.ErrAccountClosed, *errors.ErrPotRestricted:
+			h.writeDeclined(w, http.StatusConflict, err.Error(), failedID)
+		case *errors.ErrLimitExceeded:
+			h.writeLimitExceeded(w, e, failedID)
+		case *errors.ErrBeneficiaryRequired, *errors.ErrBeneficiaryNotVerified, *errors.ErrBeneficiaryCoolingOff:
+			h.writeDeclined(w, http.StatusUnprocessableEntity, err.Error(), failedID)
 		default:
 			h.writeError(w, http.StatusInternalServerError, "Failed to process transfer")
 		}
 		return
 	}
 	
-	if err := h.store.UpdateAccount(fromAccount); err != nil {
-		h.logger.WithError(err).WithField("account_id", fromAccount.ID).Error("Failed to update from account after transfer")
-		h.writeError(w, http.StatusInternalServerError, "Failed to process transfer")
+	if pending != nil {
+		h.logger.WithFields(logrus.Fields{
+			"from_account_id": req.FromAccountID,
+			"to_account_id": req.ToAccountID,
+			"amount": req.Amount,
+			"approval_id": pending.ID,
+		}).Info("Transfer waiting for approval")
+		
+		h.writeJSON(w, http.StatusAccepted, pending)
 		return
 	}
 	
-	if err := h.store.UpdateAccount(toAccount); err != nil {
-		h.logger.WithError(err).WithField("account_id", toAccount.ID).Error("Failed to update to account after transfer")
-		h.writeError(w, http.StatusInternalServerError, "Failed to process transfer")
+	if async {
+		if err := h.transfers.Submit(tx.ID); err != nil {
+			h.logger.WithError(err).WithField("transaction_id", tx.ID).Warn("Pending transfer not queued, it will be requeued by the recovery job")
+		}
+		
+		h.logger.WithFields(logrus.Fields{
+			"from_account_id": req.FromAccountID,
+			"to_account_id": req.ToAccountID,
+			"amount": req.Amount,
+			"transaction_id": tx.ID,
+		}).Info("Transfer accepted for processing")
+		
+		h.writeJSON(w, http.StatusAccepted, transaction.TransactionResponse{
+			TransactionID: tx.ID,
+			Status:        tx.Status,
+		})
 		return
 	}
 	
-	tx := h.transactionService.CreateTransferTransaction(req.FromAccountID, req.ToAccountID, req.Amount)
-	if err := h.store.StoreTransaction(tx); err != nil {
-		h.logger.WithError(err).WithField("transaction_id", tx.ID).Error("Failed to store transfer transaction")
-	}
-	
 	h.logger.WithFields(logrus.Fields{
 		"from_account_id": req.FromAccountID,
 		"to_account_id": req.ToAccountID,
 		"amount": req.Amount,
 		"transaction_id": tx.ID,
-		"from_balance": fromAccount.Balance,
-		"to_balance": toAccount.Balance,
 	}).Info("Transfer processed successfully")
 	
 	h.writeJSON(w, http.StatusOK, transaction.TransactionResponse{
 		TransactionID: tx.ID,
 		Status:        tx.Status,
 	})
-} 
\ No newline at end of file
+}
+
+// resolveTransfer checks account numbers and IBANs given for the transfer's
+// accounts and replaces them with the accounts' IDs. References that match no
+// account are left for the transfer to report as not found.
+func (h *Handler) resolveTransfer(ctx context.Context, req *transaction.TransferRequest) error {
+	for _, ref := range []*string{&req.FromAccountID, &req.ToAccountID} {
+		if err := accountnumber.Check(*ref); err != nil {
+			return err
+		}
+		*ref = h.accountID(ctx, *ref)
+	}
+	return nil
+}
+
+// accountID returns the ID of the account ref names, which may be its ID,
+// account number or IBAN. Units of work look accounts up by ID only, so
+// requests resolve references first. A ref that matches no account is
+// returned as is, for the request to report as not found.
+func (h *Handler) accountID(ctx context.Context, ref string) string {
+	if acc, err := h.store.GetAccount(ctx, ref); err == nil {
+		return acc.ID
+	}
+	return ref
+}
+
+// asyncTransfer reports whether a transfer of amount is large enough to be
+// accepted as pending and settled by the transfer workers.
+func (h *Handler) asyncTransfer(amount int64) bool {
+	return h.config.AsyncTransferThreshold > 0 && amount >= h.config.AsyncTransferThreshold
+}
+
+// transfer makes the transfer, or only accepts it when async.
+func (h *Handler) transfer(uow store.UnitOfWork, req transaction.TransferRequest, async bool) (*transaction.Transaction, error) {
+	if async {
+		return h.payments.Accept(uow, req, time.Now())
+	}
+	return h.payments.Transfer(uow, req, time.Now())
+}
+
+// declinedTransfer builds the transaction recorded for a rejected transfer
+// request.
+func (h *Handler) declinedTransfer(req transaction.TransferRequest) *transaction.Transaction {
+	tx := h.transactionService.CreateTransferTransaction(req.FromAccountID, req.ToAccountID, req.Amount, req.Currency)
+	tx.QuoteID = req.QuoteID
+	return tx
+}
+
+func (h *Handler) VerifyLedger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	accounts, entries := h.store.LedgerState(r.Context())
+	if err := h.ledgerService.Verify(accounts, entries); err != nil {
+		h.logger.WithError(err).Error("Ledger verification failed")
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"balanced": true,
+		"accounts": len(accounts),
+		"entries":  len(entries),
+	})
+}
\ No newline at end of file
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+func (h *Handler) GetTransaction(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if id == "" {
+		h.writeError(w, http.StatusBadRequest, "Transaction ID required")
+		return
+	}
+
+	tx, err := h.store.GetTransaction(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("transaction_id", id).Error("Failed to get transaction")
+
+		if _, ok := err.(*errors.ErrTransactionNotFound); ok {
+			h.writeError(w, http.StatusNotFound, "Transaction not found")
+		} else {
+			h.writeError(w, http.StatusInternalServerError, "Failed to get transaction")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tx)
+}
+
+func (h *Handler) ListAccountTransactions(w http.ResponseWriter, r *http.Request, accountID string) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := parseTransactionFilter(query)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := defaultPageLimit
+	if raw := query.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			h.writeError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxPageLimit))
+			return
+		}
+	}
+
+	if _, err := h.store.GetAccount(r.Context(), accountID); err != nil {
+		if _, ok := err.(*errors.ErrAccountNotFound); ok {
+			h.writeError(w, http.StatusNotFound, "Account not found")
+		} else {
+			h.logger.WithError(err).WithField("account_id", accountID).Error("Failed to get account for history")
+			h.writeError(w, http.StatusInternalServerError, "Failed to list transactions")
+		}
+		return
+	}
+
+	page, err := h.store.ListAccountTransactions(r.Context(), accountID, filter, query.Get("cursor"), limit)
+	if err != nil {
+		h.logger.WithError(err).WithField("account_id", accountID).Error("Failed to list transactions")
+
+		if _, ok := err.(*errors.ErrInvalidCursor); ok {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.writeError(w, http.StatusInternalServerError, "Failed to list transactions")
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, page)
+}
+
+// parseTransactionFilter reads the history filters from the query string:
+// type and status (comma-separated or repeated), min_amount, max_amount, and
+// from/to as RFC 3339 timestamps.
+func parseTransactionFilter(query url.Values) (transaction.Filter, error) {
+	var filter transaction.Filter
+
+	for _, t := range splitQueryList(query["type"]) {
+		filter.Types = append(filter.Types, transaction.TransactionType(t))
+	}
+	for _, status := range splitQueryList(query["status"]) {
+		filter.Statuses = append(filter.Statuses, transaction.TransactionStatus(status))
+	}
+
+	var err error
+	if filter.MinAmount, err = parseOptionalInt(query, "min_amount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = parseOptionalInt(query, "max_amount"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseOptionalTime(query, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseOptionalTime(query, "to"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func splitQueryList(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func parseOptionalInt(query url.Values, key string) (*int64, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, &errors.ErrInvalidParameter{Name: key, Value: raw}
+	}
+	return &n, nil
+}
+
+func parseOptionalTime(query url.Values, key string) (time.Time, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, &errors.ErrInvalidParameter{Name: key, Value: raw}
+	}
+	return t, nil
+}
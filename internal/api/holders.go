@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/store"
+	"banking-service/pkg/errors"
+)
+
+type HolderRequest struct {
+	CustomerID string       `json:"customer_id"`
+	Role       account.Role `json:"role"`
+}
+
+type MandateRequest struct {
+	Mandate account.Mandate `json:"mandate"`
+}
+
+type HoldersResponse struct {
+	AccountID         string           `json:"account_id"`
+	Holders           []account.Holder `json:"holders"`
+	Mandate           account.Mandate  `json:"mandate"`
+	RequiredApprovals int              `json:"required_approvals"`
+}
+
+func newHoldersResponse(acc *account.Account) HoldersResponse {
+	return HoldersResponse{
+		AccountID:         acc.ID,
+		Holders:           append([]account.Holder{}, acc.CurrentHolders()...),
+		Mandate:           acc.CurrentMandate(),
+		RequiredApprovals: acc.RequiredApprovals(),
+	}
+}
+
+// AccountHolders handles GET and POST /accounts/{id}/holders. POST adds a
+// holder or changes a holder's role, and DELETE /accounts/{id}/holders/{cid}
+// removes one. Changes must be made by the primary holder.
+func (h *Handler) AccountHolders(w http.ResponseWriter, r *http.Request, accountID, customerID string) {
+	switch {
+	case customerID != "" && r.Method == "DELETE":
+		h.removeHolder(w, r, accountID, customerID)
+	case customerID != "":
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	case r.Method == "GET":
+		acc, err := h.store.GetAccount(r.Context(), accountID)
+		if err != nil {
+			h.logger.WithError(err).WithField("account_id", accountID).Error("Failed to get account holders")
+			h.writeHolderError(w, err, "Failed to get account holders")
+			return
+		}
+		h.writeJSON(w, http.StatusOK, newHoldersResponse(acc))
+	case r.Method == "POST":
+		h.setHolder(w, r, accountID)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) setHolder(w http.ResponseWriter, r *http.Request, accountID string) {
+	var req HolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode holder request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var updated *account.Account
+	err := h.manageHolders(r, accountID, func(uow store.UnitOfWork, acc *account.Account) error {
+		if _, err := uow.GetCustomer(req.CustomerID); err != nil {
+			return err
+		}
+		if err := h.accountService.SetHolder(acc, req.CustomerID, req.Role); err != nil {
+			return err
+		}
+
+		updated = acc
+		return nil
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id":  accountID,
+			"customer_id": req.CustomerID,
+		}).Error("Failed to set account holder")
+		h.writeHolderError(w, err, "Failed to set account holder")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id":  accountID,
+		"customer_id": req.CustomerID,
+		"role":        req.Role,
+	}).Info("Account holder set")
+
+	h.writeJSON(w, http.StatusOK, newHoldersResponse(updated))
+}
+
+func (h *Handler) removeHolder(w http.ResponseWriter, r *http.Request, accountID, customerID string) {
+	var updated *account.Account
+	err := h.manageHolders(r, accountID, func(uow store.UnitOfWork, acc *account.Account) error {
+		if err := h.accountService.RemoveHolder(acc, customerID); err != nil {
+			return err
+		}
+
+		updated = acc
+		return nil
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id":  accountID,
+			"customer_id": customerID,
+		}).Error("Failed to remove account holder")
+		h.writeHolderError(w, err, "Failed to remove account holder")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id":  accountID,
+		"customer_id": customerID,
+	}).Info("Account holder removed")
+
+	h.writeJSON(w, http.StatusOK, newHoldersResponse(updated))
+}
+
+// SetAccountMandate handles POST /accounts/{id}/mandate.
+func (h *Handler) SetAccountMandate(w http.ResponseWriter, r *http.Request, accountID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req MandateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode mandate request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var updated *account.Account
+	err := h.manageHolders(r, accountID, func(uow store.UnitOfWork, acc *account.Account) error {
+		if err := h.accountService.SetMandate(acc, req.Mandate); err != nil {
+			return err
+		}
+
+		updated = acc
+		return nil
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("account_id", accountID).Error("Failed to set account mandate")
+		h.writeHolderError(w, err, "Failed to set account mandate")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id": accountID,
+		"mandate":    req.Mandate,
+	}).Info("Account mandate set")
+
+	h.writeJSON(w, http.StatusOK, newHoldersResponse(updated))
+}
+
+// manageHolders runs fn on the account once the caller is confirmed as its
+// primary holder.
+func (h *Handler) manageHolders(r *http.Request, accountID string, fn func(uow store.UnitOfWork, acc *account.Account) error) error {
+	customerID := caller(r)
+	return h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount(accountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.AuthorizeManagement(acc, customerID); err != nil {
+			return err
+		}
+		return fn(uow, acc)
+	})
+}
+
+func (h *Handler) writeHolderError(w http.ResponseWriter, err error, message string) {
+	switch err.(type) {
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrCustomerNotFound:
+		h.writeError(w, http.StatusNotFound, "Customer not found")
+	case *errors.ErrHolderNotFound:
+		h.writeError(w, http.StatusNotFound, err.Error())
+	case *errors.ErrCallerNotAuthorized:
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case *errors.ErrInvalidParameter:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrInvalidHolderChange, *errors.ErrInvalidMandate, *errors.ErrAccountClosed:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
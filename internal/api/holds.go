@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/hold"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// PlaceHold handles POST /holds.
+func (h *Handler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req hold.PlaceHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode hold request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.AccountID = h.accountID(r.Context(), req.AccountID)
+
+	customerID := caller(r)
+	var placed *hold.Hold
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount(req.AccountID)
+		if err != nil {
+			return err
+		}
+		// Capturing a hold needs no further approval, so placing it must
+		// satisfy the mandate.
+		if err := h.accountService.AuthorizeAlone(acc, customerID, "place holds"); err != nil {
+			return err
+		}
+		if err := h.accountService.ValidateCurrency(acc, req.Currency); err != nil {
+			return err
+		}
+
+		placed, err = h.holdService.Place(req, acc.CurrentCurrency(), time.Now())
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.Reserve(acc, placed.Amount); err != nil {
+			return err
+		}
+		return uow.SaveHold(placed)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id": req.AccountID,
+			"amount":     req.Amount,
+		}).Error("Failed to place hold")
+		h.writeHoldError(w, err, "Failed to place hold")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"hold_id":    placed.ID,
+		"account_id": placed.AccountID,
+		"amount":     placed.Amount,
+	}).Info("Hold placed")
+
+	h.writeJSON(w, http.StatusCreated, placed)
+}
+
+// GetHold handles GET /holds/{id}.
+func (h *Handler) GetHold(w http.ResponseWriter, r *http.Request, holdID string) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	found, err := h.store.GetHold(r.Context(), holdID)
+	if err != nil {
+		h.logger.WithError(err).WithField("hold_id", holdID).Error("Failed to get hold")
+		h.writeHoldError(w, err, "Failed to get hold")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, found)
+}
+
+// CaptureHold handles POST /holds/{id}/capture. The captured amount is
+// withdrawn, subject to the account's withdrawal limits, and the rest of the
+// hold released.
+func (h *Handler) CaptureHold(w http.ResponseWriter, r *http.Request, holdID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req hold.CaptureRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.logger.WithError(err).Error("Failed to decode capture request")
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	var captured *hold.Hold
+	var tx *transaction.Transaction
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		held, err := uow.GetHold(holdID)
+		if err != nil {
+			return err
+		}
+		acc, err := uow.GetAccount(held.AccountID)
+		if err != nil {
+			return err
+		}
+
+		amount, err := h.holdService.Capture(held, req.Amount, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.CaptureReserved(acc, held.Amount, amount); err != nil {
+			return err
+		}
+
+		tx = h.transactionService.CreateWithdrawalTransaction(acc.ID, amount, acc.CurrentCurrency())
+		tx.HoldID = held.ID
+		held.TransactionID = tx.ID
+		if err := h.payments.CheckLimits(uow, acc, tx); err != nil {
+			return err
+		}
+		if err := h.payments.Record(uow, tx); err != nil {
+			return err
+		}
+
+		captured = held
+		return uow.SaveHold(held)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"hold_id": holdID,
+			"amount":  req.Amount,
+		}).Error("Failed to capture hold")
+		h.writeHoldError(w, err, "Failed to capture hold")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"hold_id":        captured.ID,
+		"account_id":     captured.AccountID,
+		"amount":         captured.CapturedAmount,
+		"transaction_id": tx.ID,
+	}).Info("Hold captured")
+
+	h.writeJSON(w, http.StatusOK, captured)
+}
+
+// ReleaseHold handles POST /holds/{id}/release.
+func (h *Handler) ReleaseHold(w http.ResponseWriter, r *http.Request, holdID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var released *hold.Hold
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		held, err := uow.GetHold(holdID)
+		if err != nil {
+			return err
+		}
+		acc, err := uow.GetAccount(held.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := h.holdService.Release(held, time.Now()); err != nil {
+			return err
+		}
+		if err := h.accountService.ReleaseReserved(acc, held.Amount); err != nil {
+			return err
+		}
+
+		released = held
+		return uow.SaveHold(held)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("hold_id", holdID).Error("Failed to release hold")
+		h.writeHoldError(w, err, "Failed to release hold")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"hold_id":    released.ID,
+		"account_id": released.AccountID,
+		"amount":     released.Amount,
+	}).Info("Hold released")
+
+	h.writeJSON(w, http.StatusOK, released)
+}
+
+func (h *Handler) writeHoldError(w http.ResponseWriter, err error, message string) {
+	switch e := err.(type) {
+	case *errors.ErrHoldNotFound:
+		h.writeError(w, http.StatusNotFound, "Hold not found")
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrInvalidAmount, *errors.ErrCurrencyMismatch, *errors.ErrInvalidParameter:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrInsufficientFunds, *errors.ErrCaptureExceedsHold:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrCallerNotAuthorized, *errors.ErrApprovalRequired:
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case *errors.ErrHoldNotActive, *errors.ErrHoldExpired:
+		h.writeError(w, http.StatusConflict, err.Error())
+	case *errors.ErrLimitExceeded:
+		h.writeLimitExceeded(w, e, "")
+	case *errors.ErrAccountFrozen, *errors.ErrAccountDormant, *errors.ErrAccountClosed, *errors.ErrPotRestricted:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/idempotency"
+)
+
+const maxIdempotentBodyBytes = 1 << 20
+
+// responseRecorder captures what a handler writes so the response can be
+// stored against its Idempotency-Key.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// idempotent makes next safe to retry. The first POST carrying an
+// Idempotency-Key runs normally and its response is stored; repeats with the
+// same body replay that response, and repeats with a different body are
+// rejected. Server errors are not stored so that the client may retry them,
+// unless the request had already committed changes.
+func (h *Handler) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotency.HeaderKey)
+		if key == "" || r.Method != "POST" {
+			next(w, r)
+			return
+		}
+
+		// Read one byte past the limit so an oversized body is refused
+		// rather than cut short and handled as if it were complete.
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodyBytes+1))
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if len(body) > maxIdempotentBodyBytes {
+			h.writeError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		rec := &idempotency.Record{
+			Key:         key,
+			Fingerprint: idempotency.Fingerprint(r.Method, r.URL.Path, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(h.config.IdempotencyKeyTTL),
+		}
+
+		existing, err := h.store.ReserveIdempotencyKey(r.Context(), rec)
+		if err != nil {
+			h.logger.WithError(err).WithField("idempotency_key", key).Error("Failed to reserve idempotency key")
+			h.writeError(w, http.StatusInternalServerError, "Failed to process request")
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != rec.Fingerprint:
+				h.writeError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+			case existing.Committed && !existing.Completed():
+				h.writeError(w, http.StatusConflict, "A request with this Idempotency-Key was already processed but its response was not stored")
+			case !existing.Completed():
+				h.writeError(w, http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+			default:
+				h.logger.WithField("idempotency_key", key).Info("Replaying idempotent response")
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(existing.StatusCode)
+				w.Write(existing.Response)
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next(recorder, r.WithContext(idempotency.WithKey(r.Context(), key)))
+
+		if recorder.statusCode == 0 || recorder.statusCode >= 500 {
+			if err := h.store.ReleaseIdempotencyKey(r.Context(), key); err != nil {
+				h.logger.WithError(err).WithField("idempotency_key", key).Error("Failed to release idempotency key")
+			}
+			return
+		}
+
+		rec.StatusCode = recorder.statusCode
+		rec.Response = recorder.body.Bytes()
+		if err := h.store.CompleteIdempotencyKey(r.Context(), rec); err != nil {
+			h.logger.WithError(err).WithFields(logrus.Fields{
+				"idempotency_key": key,
+				"status_code":     rec.StatusCode,
+			}).Error("Failed to store idempotent response")
+		}
+	}
+}
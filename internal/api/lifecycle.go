@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/store"
+	"banking-service/pkg/errors"
+)
+
+// ChangeAccountStatus handles POST /accounts/{id}/freeze, /unfreeze and
+// /close. Closing an account first sweeps its pots, or a pot's balance, into
+// the parent, in the same unit of work as the close.
+func (h *Handler) ChangeAccountStatus(w http.ResponseWriter, r *http.Request, accountID, action string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var change func(acc *account.Account) error
+	switch action {
+	case "freeze":
+		change = h.accountService.Freeze
+	case "unfreeze":
+		change = h.accountService.Unfreeze
+	case "close":
+		// Left to the payment processor, which sweeps pots first.
+	default:
+		h.writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var updated *account.Account
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount(accountID)
+		if err != nil {
+			return err
+		}
+		if change == nil {
+			err = h.payments.Close(uow, acc)
+		} else {
+			err = change(acc)
+		}
+		if err != nil {
+			return err
+		}
+
+		updated = acc
+		return nil
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id": accountID,
+			"action":     action,
+		}).Error("Failed to change account status")
+
+		switch err.(type) {
+		case *errors.ErrAccountNotFound:
+			h.writeError(w, http.StatusNotFound, "Account not found")
+		case *errors.ErrInvalidStatusTransition, *errors.ErrAccountBalanceNotZero, *errors.ErrAccountHasHolds:
+			h.writeError(w, http.StatusConflict, err.Error())
+		case *errors.ErrAccountFrozen, *errors.ErrAccountDormant, *errors.ErrAccountClosed:
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			h.writeError(w, http.StatusInternalServerError, "Failed to change account status")
+		}
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id": accountID,
+		"status":     updated.Status,
+	}).Info("Account status changed")
+
+	h.writeJSON(w, http.StatusOK, newAccountResponse(updated))
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/limit"
+	"banking-service/internal/store"
+	"banking-service/pkg/errors"
+)
+
+type LimitsRequest struct {
+	Limits []limit.Limit `json:"limits"`
+}
+
+// LimitsResponse lists the account's own limits and every limit that
+// applies to it, including configured defaults.
+type LimitsResponse struct {
+	AccountID string        `json:"account_id"`
+	Limits    []limit.Limit `json:"limits"`
+	Effective []limit.Limit `json:"effective"`
+}
+
+func (h *Handler) newLimitsResponse(acc *account.Account) LimitsResponse {
+	return LimitsResponse{
+		AccountID: acc.ID,
+		Limits:    append([]limit.Limit{}, acc.Limits...),
+		Effective: append([]limit.Limit{}, h.payments.Limits(acc)...),
+	}
+}
+
+// AccountLimits handles GET and POST /accounts/{id}/limits. POST replaces
+// the account's own limits; an empty list falls back to the defaults.
+func (h *Handler) AccountLimits(w http.ResponseWriter, r *http.Request, accountID string) {
+	switch r.Method {
+	case "GET":
+		acc, err := h.store.GetAccount(r.Context(), accountID)
+		if err != nil {
+			h.logger.WithError(err).WithField("account_id", accountID).Error("Failed to get account limits")
+			h.writeLimitsError(w, err, "Failed to get account limits")
+			return
+		}
+		h.writeJSON(w, http.StatusOK, h.newLimitsResponse(acc))
+	case "POST":
+		h.setLimits(w, r, accountID)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) setLimits(w http.ResponseWriter, r *http.Request, accountID string) {
+	var req LimitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode limits request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var updated *account.Account
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount(accountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.SetLimits(acc, req.Limits); err != nil {
+			return err
+		}
+
+		updated = acc
+		return nil
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("account_id", accountID).Error("Failed to set account limits")
+		h.writeLimitsError(w, err, "Failed to set account limits")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id": accountID,
+		"limits":     len(updated.Limits),
+	}).Info("Account limits set")
+
+	h.writeJSON(w, http.StatusOK, h.newLimitsResponse(updated))
+}
+
+func (h *Handler) writeLimitsError(w http.ResponseWriter, err error, message string) {
+	switch err.(type) {
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrInvalidParameter:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrAccountClosed:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
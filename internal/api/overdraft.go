@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/store"
+	"banking-service/pkg/errors"
+)
+
+type OverdraftRequest struct {
+	Limit int64 `json:"limit"`
+}
+
+// SetOverdraftLimit handles POST /accounts/{id}/overdraft. A limit of zero
+// removes the facility.
+func (h *Handler) SetOverdraftLimit(w http.ResponseWriter, r *http.Request, accountID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req OverdraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode overdraft request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var updated *account.Account
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount(accountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.SetOverdraftLimit(acc, req.Limit); err != nil {
+			return err
+		}
+
+		updated = acc
+		return nil
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id": accountID,
+			"limit":      req.Limit,
+		}).Error("Failed to set overdraft limit")
+
+		switch err.(type) {
+		case *errors.ErrAccountNotFound:
+			h.writeError(w, http.StatusNotFound, "Account not found")
+		case *errors.ErrInvalidParameter, *errors.ErrOverdraftLimitTooLow:
+			h.writeError(w, http.StatusBadRequest, err.Error())
+		case *errors.ErrAccountClosed:
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			h.writeError(w, http.StatusInternalServerError, "Failed to set overdraft limit")
+		}
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id": accountID,
+		"limit":      updated.OverdraftLimit,
+	}).Info("Overdraft limit set")
+
+	h.writeJSON(w, http.StatusOK, newAccountResponse(updated))
+}
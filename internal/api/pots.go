@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// PotSummary adds up a parent account's open pots.
+type PotSummary struct {
+	Pots         int   `json:"pots"`
+	PotsBalance  int64 `json:"pots_balance"`
+	TotalBalance int64 `json:"total_balance"`
+}
+
+type PotList struct {
+	PotsBalance  int64             `json:"pots_balance"`
+	TotalBalance int64             `json:"total_balance"`
+	Pots         []AccountResponse `json:"pots"`
+}
+
+type CreatePotRequest struct {
+	Name string `json:"name"`
+}
+
+// AccountPots handles GET and POST /accounts/{id}/pots, which list the
+// account's pots and open one.
+func (h *Handler) AccountPots(w http.ResponseWriter, r *http.Request, accountID string) {
+	switch r.Method {
+	case "GET":
+		parent, err := h.store.GetAccount(r.Context(), accountID)
+		if err != nil {
+			h.writePotError(w, err, "Failed to list pots")
+			return
+		}
+
+		pots := h.store.ListPots(r.Context(), parent.ID)
+		summary := summarizePots(parent, pots)
+		resp := PotList{
+			PotsBalance:  summary.PotsBalance,
+			TotalBalance: summary.TotalBalance,
+			Pots:         make([]AccountResponse, 0, len(pots)),
+		}
+		for _, pot := range pots {
+			resp.Pots = append(resp.Pots, newAccountResponse(pot))
+		}
+		h.writeJSON(w, http.StatusOK, resp)
+	case "POST":
+		h.idempotent(func(w http.ResponseWriter, r *http.Request) {
+			h.createPot(w, r, accountID)
+		})(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) createPot(w http.ResponseWriter, r *http.Request, accountID string) {
+	var req CreatePotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode create pot request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	customerID := caller(r)
+	var pot *account.Account
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		parent, err := uow.GetAccount(accountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.Authorize(parent, customerID, "manage pots"); err != nil {
+			return err
+		}
+
+		pot, err = h.accountService.CreatePot(parent, req.Name, uow.ListPots(parent.ID))
+		if err != nil {
+			return err
+		}
+		return uow.CreateAccount(pot)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id": accountID,
+			"name":       req.Name,
+		}).Error("Failed to create pot")
+		h.writePotError(w, err, "Failed to create pot")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"account_id": pot.ID,
+		"parent_id":  pot.ParentID,
+		"name":       pot.Name,
+	}).Info("Pot created")
+
+	h.writeJSON(w, http.StatusCreated, newAccountResponse(pot))
+}
+
+// MovePotMoney handles POST /transactions/move, which moves money between an
+// account and one of its pots without a fee.
+func (h *Handler) MovePotMoney(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req transaction.MoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode move request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.FromAccountID = h.accountID(r.Context(), req.FromAccountID)
+	req.ToAccountID = h.accountID(r.Context(), req.ToAccountID)
+
+	customerID := caller(r)
+	var tx *transaction.Transaction
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		from, err := uow.GetAccount(req.FromAccountID)
+		if err != nil {
+			return err
+		}
+		// Pots share their parent's holders, so the caller is checked
+		// against whichever side is not the pot.
+		if from.IsPot() {
+			from, err = uow.GetAccount(from.ParentID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := h.accountService.Authorize(from, customerID, "move money"); err != nil {
+			return err
+		}
+
+		tx, err = h.payments.Move(uow, req)
+		return err
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"from_account_id": req.FromAccountID,
+			"to_account_id":   req.ToAccountID,
+			"amount":          req.Amount,
+		}).Error("Failed to move money")
+
+		failedID := h.recordFailure(r.Context(), h.transactionService.CreateMoveTransaction(req.FromAccountID, req.ToAccountID, req.Amount, ""), err)
+
+		switch e := err.(type) {
+		case *errors.ErrAccountNotFound:
+			if e.AccountID == req.FromAccountID {
+				h.writeDeclined(w, http.StatusNotFound, "From account not found", failedID)
+			} else {
+				h.writeDeclined(w, http.StatusNotFound, "To account not found", failedID)
+			}
+		case *errors.ErrInvalidAmount, *errors.ErrInsufficientFunds, *errors.ErrSameAccountTransfer, *errors.ErrInvalidPotMove:
+			h.writeDeclined(w, http.StatusBadRequest, err.Error(), failedID)
+		case *errors.ErrCallerNotAuthorized:
+			h.writeDeclined(w, http.StatusForbidden, err.Error(), failedID)
+		case *errors.ErrAccountFrozen, *errors.ErrAccountDormant, *errors.ErrAccountClosed:
+			h.writeDeclined(w, http.StatusConflict, err.Error(), failedID)
+		default:
+			h.writeError(w, http.StatusInternalServerError, "Failed to move money")
+		}
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"from_account_id": req.FromAccountID,
+		"to_account_id":   req.ToAccountID,
+		"amount":          req.Amount,
+		"transaction_id":  tx.ID,
+	}).Info("Money moved")
+
+	h.writeJSON(w, http.StatusOK, transaction.TransactionResponse{
+		TransactionID: tx.ID,
+		Status:        tx.Status,
+	})
+}
+
+// accountResponse is newAccountResponse with the account's open pots added
+// up, if it has any.
+func (h *Handler) accountResponse(ctx context.Context, acc *account.Account) AccountResponse {
+	resp := newAccountResponse(acc)
+	if acc.IsPot() {
+		return resp
+	}
+	if summary := summarizePots(acc, h.store.ListPots(ctx, acc.ID)); summary.Pots > 0 {
+		resp.PotSummary = &summary
+	}
+	return resp
+}
+
+func summarizePots(parent *account.Account, pots []*account.Account) PotSummary {
+	summary := PotSummary{TotalBalance: parent.Balance}
+	for _, pot := range pots {
+		if pot.CurrentStatus() == account.StatusClosed {
+			continue
+		}
+		summary.Pots++
+		summary.PotsBalance += pot.Balance
+		summary.TotalBalance += pot.Balance
+	}
+	return summary
+}
+
+func (h *Handler) writePotError(w http.ResponseWriter, err error, message string) {
+	switch err.(type) {
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrCallerNotAuthorized:
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case *errors.ErrInvalidParameter, *errors.ErrInvalidPot:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrAccountClosed:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// ReverseTransaction handles POST /transactions/{id}/reverse. Without an
+// amount the whole remaining amount is reversed; with one it is a partial
+// refund.
+func (h *Handler) ReverseTransaction(w http.ResponseWriter, r *http.Request, transactionID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req transaction.ReverseRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.logger.WithError(err).Error("Failed to decode reverse request")
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	var reversal *transaction.Transaction
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		var err error
+		reversal, err = h.payments.Reverse(uow, transactionID, req)
+		return err
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"transaction_id": transactionID,
+			"amount":         req.Amount,
+		}).Error("Failed to reverse transaction")
+
+		switch err.(type) {
+		case *errors.ErrTransactionNotFound:
+			h.writeError(w, http.StatusNotFound, "Transaction not found")
+		case *errors.ErrAccountNotFound:
+			h.writeError(w, http.StatusNotFound, "Account not found")
+		case *errors.ErrInvalidAmount, *errors.ErrRefundExceedsOriginal, *errors.ErrInsufficientFunds:
+			h.writeError(w, http.StatusBadRequest, err.Error())
+		case *errors.ErrAlreadyReversed, *errors.ErrTransactionNotReversible:
+			h.writeError(w, http.StatusConflict, err.Error())
+		case *errors.ErrAccountFrozen, *errors.ErrAccountDormant, *errors.ErrAccountClosed, *errors.ErrPotRestricted:
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			h.writeError(w, http.StatusInternalServerError, "Failed to reverse transaction")
+		}
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"transaction_id":          reversal.ID,
+		"original_transaction_id": transactionID,
+		"type":                    reversal.Type,
+		"amount":                  reversal.Amount,
+	}).Info("Transaction reversed")
+
+	h.writeJSON(w, http.StatusOK, reversal)
+}
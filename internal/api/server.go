@@ -2,24 +2,40 @@ package api
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"banking-service/internal/account"
+	"banking-service/internal/accountnumber"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/config"
+	"banking-service/internal/fx"
+	"banking-service/internal/limit"
+	"banking-service/internal/payment"
 	"banking-service/internal/store"
 )
 
 type Server struct {
 	server *http.Server
 	logger *logrus.Logger
-	store  *store.Store
+	store  store.Repository
+	rates  fx.RateProvider
+	fees   account.FeePolicy
+	limits []limit.Limit
+	config *config.Config
+	
+	numbers   *accountnumber.Service
+	payees    *beneficiary.Service
+	transfers *payment.Dispatcher
 }
 
-func NewServer(port string, logger *logrus.Logger, store *store.Store) *Server {
+func NewServer(cfg *config.Config, logger *logrus.Logger, store store.Repository, rates fx.RateProvider, fees account.FeePolicy, limits []limit.Limit, numbers *accountnumber.Service, payees *beneficiary.Service, transfers *payment.Dispatcher) *Server {
 	mux := http.NewServeMux()
 	
 	server := &http.Server{
-		Addr:         ":" + port,
+		Addr:         ":" + cfg.Port,
 		Handler:      mux,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
@@ -30,26 +46,184 @@ func NewServer(port string, logger *logrus.Logger, store *store.Store) *Server {
 		server: server,
 		logger: logger,
 		store:  store,
+		rates:  rates,
+		fees:   fees,
+		limits: limits,
+		config: cfg,
+		
+		numbers:   numbers,
+		payees:    payees,
+		transfers: transfers,
 	}
 }
 
 func (s *Server) SetupRoutes() {
-	handler := NewHandler(s.store, s.logger)
+	handler := NewHandler(s.store, s.logger, s.config, s.rates, s.fees, s.limits, s.numbers, s.payees, s.transfers)
 	
 	mux := s.server.Handler.(*http.ServeMux)
-	mux.HandleFunc("/accounts", handler.CreateAccount)
-	mux.HandleFunc("/accounts/", s.getAccountHandler)
+	mux.HandleFunc("/accounts", handler.idempotent(handler.CreateAccount))
+	mux.HandleFunc("/accounts/", s.accountRoutes(handler))
+	
+	mux.HandleFunc("/transactions/deposit", handler.idempotent(handler.Deposit))
+	mux.HandleFunc("/transactions/withdraw", handler.idempotent(handler.Withdraw))
+	mux.HandleFunc("/transactions/transfer", handler.idempotent(handler.Transfer))
+	mux.HandleFunc("/transactions/move", handler.idempotent(handler.MovePotMoney))
+	mux.HandleFunc("/transactions/quote", handler.QuoteTransaction)
+	mux.HandleFunc("/transactions/batch", handler.idempotent(handler.CreateBatch))
+	mux.HandleFunc("/transactions/batch/", handler.GetBatch)
+	mux.HandleFunc("/transactions/", s.transactionRoutes(handler))
+	
+	mux.HandleFunc("/customers", handler.Customers)
+	mux.HandleFunc("/customers/", s.customerRoutes(handler))
+	
+	mux.HandleFunc("/approvals/", s.approvalRoutes(handler))
+	
+	mux.HandleFunc("/holds", handler.idempotent(handler.PlaceHold))
+	mux.HandleFunc("/holds/", s.holdRoutes(handler))
+	
+	mux.HandleFunc("/standing-orders", handler.StandingOrders)
+	mux.HandleFunc("/standing-orders/", s.standingOrderRoutes(handler))
 	
-	mux.HandleFunc("/transactions/deposit", handler.Deposit)
-	mux.HandleFunc("/transactions/withdraw", handler.Withdraw)
-	mux.HandleFunc("/transactions/transfer", handler.Transfer)
+	mux.HandleFunc("/fx/quotes", handler.CreateQuote)
+	mux.HandleFunc("/fx/quotes/", handler.GetQuote)
+	
+	mux.HandleFunc("/ledger/verify", handler.VerifyLedger)
 	
 	mux.HandleFunc("/health", s.healthCheck)
 }
 
-func (s *Server) getAccountHandler(w http.ResponseWriter, r *http.Request) {
-	handler := NewHandler(s.store, s.logger)
-	handler.GetAccount(w, r)
+// accountRoutes dispatches /accounts/{id} and its sub-resources.
+func (s *Server) accountRoutes(handler *Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/accounts/"), "/")
+		id = handler.accountID(r.Context(), id)
+		
+		if holders, customerID, ok := strings.Cut(resource, "/"); ok && holders == "holders" {
+			handler.AccountHolders(w, r, id, customerID)
+			return
+		}
+		
+		if beneficiaries, rest, ok := strings.Cut(resource, "/"); ok && beneficiaries == "beneficiaries" {
+			beneficiaryID, action, _ := strings.Cut(rest, "/")
+			handler.AccountBeneficiary(w, r, id, beneficiaryID, action)
+			return
+		}
+		
+		switch resource {
+		case "":
+			handler.GetAccount(w, r)
+		case "transactions":
+			handler.ListAccountTransactions(w, r, id)
+		case "freeze", "unfreeze", "close":
+			handler.ChangeAccountStatus(w, r, id, resource)
+		case "overdraft":
+			handler.SetOverdraftLimit(w, r, id)
+		case "limits":
+			handler.AccountLimits(w, r, id)
+		case "customer":
+			handler.LinkAccountCustomer(w, r, id)
+		case "holders":
+			handler.AccountHolders(w, r, id, "")
+		case "mandate":
+			handler.SetAccountMandate(w, r, id)
+		case "approvals":
+			handler.AccountApprovals(w, r, id)
+		case "beneficiaries":
+			handler.AccountBeneficiaries(w, r, id)
+		case "pots":
+			handler.AccountPots(w, r, id)
+		default:
+			handler.writeError(w, http.StatusNotFound, "Not found")
+		}
+	}
+}
+
+// transactionRoutes dispatches /transactions/{id} and its actions.
+func (s *Server) transactionRoutes(handler *Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/transactions/"), "/")
+		
+		switch action {
+		case "":
+			handler.GetTransaction(w, r, id)
+		case "reverse":
+			handler.idempotent(func(w http.ResponseWriter, r *http.Request) {
+				handler.ReverseTransaction(w, r, id)
+			})(w, r)
+		default:
+			handler.writeError(w, http.StatusNotFound, "Not found")
+		}
+	}
+}
+
+// customerRoutes dispatches /customers/{id} and its sub-resources.
+func (s *Server) customerRoutes(handler *Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/customers/"), "/")
+		
+		switch resource {
+		case "":
+			handler.Customer(w, r, id)
+		case "accounts":
+			handler.CustomerAccounts(w, r, id)
+		default:
+			handler.writeError(w, http.StatusNotFound, "Not found")
+		}
+	}
+}
+
+// approvalRoutes dispatches /approvals/{id} and its actions.
+func (s *Server) approvalRoutes(handler *Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/approvals/"), "/")
+		
+		switch action {
+		case "":
+			handler.GetApproval(w, r, id)
+		case "approve":
+			handler.ApprovePayment(w, r, id)
+		case "reject":
+			handler.RejectPayment(w, r, id)
+		default:
+			handler.writeError(w, http.StatusNotFound, "Not found")
+		}
+	}
+}
+
+// holdRoutes dispatches /holds/{id} and its actions.
+func (s *Server) holdRoutes(handler *Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/holds/"), "/")
+		
+		switch action {
+		case "":
+			handler.GetHold(w, r, id)
+		case "capture":
+			handler.idempotent(func(w http.ResponseWriter, r *http.Request) {
+				handler.CaptureHold(w, r, id)
+			})(w, r)
+		case "release":
+			handler.ReleaseHold(w, r, id)
+		default:
+			handler.writeError(w, http.StatusNotFound, "Not found")
+		}
+	}
+}
+
+// standingOrderRoutes dispatches /standing-orders/{id} and its actions.
+func (s *Server) standingOrderRoutes(handler *Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/standing-orders/"), "/")
+		
+		switch action {
+		case "":
+			handler.GetStandingOrder(w, r, id)
+		case "cancel":
+			handler.CancelStandingOrder(w, r, id)
+		default:
+			handler.writeError(w, http.StatusNotFound, "Not found")
+		}
+	}
 }
 
 func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/standingorder"
+	"banking-service/internal/store"
+	"banking-service/pkg/errors"
+)
+
+type StandingOrderList struct {
+	StandingOrders []*standingorder.StandingOrder `json:"standing_orders"`
+}
+
+// StandingOrders handles POST /standing-orders, which creates an order, and
+// GET /standing-orders, which lists them, optionally for one account_id.
+func (h *Handler) StandingOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		orders := h.store.ListStandingOrders(r.Context(), r.URL.Query().Get("account_id"))
+		h.writeJSON(w, http.StatusOK, StandingOrderList{StandingOrders: append([]*standingorder.StandingOrder{}, orders...)})
+	case "POST":
+		h.idempotent(h.CreateStandingOrder)(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) CreateStandingOrder(w http.ResponseWriter, r *http.Request) {
+	var req standingorder.CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithError(err).Error("Failed to decode standing order request")
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.FromAccountID = h.accountID(r.Context(), req.FromAccountID)
+	req.ToAccountID = h.accountID(r.Context(), req.ToAccountID)
+
+	customerID := caller(r)
+	var created *standingorder.StandingOrder
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		fromAccount, err := uow.GetAccount(req.FromAccountID)
+		if err != nil {
+			return err
+		}
+		// The order's payments are made without anyone asking for them, so
+		// setting it up must satisfy the mandate.
+		if err := h.accountService.AuthorizeAlone(fromAccount, customerID, "set up standing orders"); err != nil {
+			return err
+		}
+		toAccount, err := uow.GetAccount(req.ToAccountID)
+		if err != nil {
+			return err
+		}
+		if err := h.accountService.ValidateCurrency(fromAccount, req.Currency); err != nil {
+			return err
+		}
+
+		// Standing orders carry no exchange rate, so both accounts must
+		// hold the same currency.
+		currency := fromAccount.CurrentCurrency()
+		if toAccount.CurrentCurrency() != currency {
+			return &errors.ErrCurrencyMismatch{AccountID: toAccount.ID, Expected: toAccount.CurrentCurrency(), Actual: currency}
+		}
+
+		created, err = h.standingOrderService.Create(req, currency, time.Now())
+		if err != nil {
+			return err
+		}
+		return uow.SaveStandingOrder(created)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"from_account_id": req.FromAccountID,
+			"to_account_id":   req.ToAccountID,
+			"amount":          req.Amount,
+		}).Error("Failed to create standing order")
+		h.writeStandingOrderError(w, err, "Failed to create standing order")
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"standing_order_id": created.ID,
+		"from_account_id":   created.FromAccountID,
+		"to_account_id":     created.ToAccountID,
+		"amount":            created.Amount,
+		"frequency":         created.Frequency,
+	}).Info("Standing order created")
+
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+// GetStandingOrder handles GET /standing-orders/{id}, including the order's
+// execution history.
+func (h *Handler) GetStandingOrder(w http.ResponseWriter, r *http.Request, orderID string) {
+	if r.Method != "GET" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	found, err := h.store.GetStandingOrder(r.Context(), orderID)
+	if err != nil {
+		h.logger.WithError(err).WithField("standing_order_id", orderID).Error("Failed to get standing order")
+		h.writeStandingOrderError(w, err, "Failed to get standing order")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, found)
+}
+
+// CancelStandingOrder handles POST /standing-orders/{id}/cancel. Payments
+// already made are not affected.
+func (h *Handler) CancelStandingOrder(w http.ResponseWriter, r *http.Request, orderID string) {
+	if r.Method != "POST" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var cancelled *standingorder.StandingOrder
+	err := h.apply(r.Context(), func(uow store.UnitOfWork) error {
+		o, err := uow.GetStandingOrder(orderID)
+		if err != nil {
+			return err
+		}
+		if err := h.standingOrderService.Cancel(o, time.Now()); err != nil {
+			return err
+		}
+
+		cancelled = o
+		return uow.SaveStandingOrder(o)
+	})
+	if err != nil {
+		h.logger.WithError(err).WithField("standing_order_id", orderID).Error("Failed to cancel standing order")
+		h.writeStandingOrderError(w, err, "Failed to cancel standing order")
+		return
+	}
+
+	h.logger.WithField("standing_order_id", cancelled.ID).Info("Standing order cancelled")
+
+	h.writeJSON(w, http.StatusOK, cancelled)
+}
+
+func (h *Handler) writeStandingOrderError(w http.ResponseWriter, err error, message string) {
+	switch err.(type) {
+	case *errors.ErrStandingOrderNotFound:
+		h.writeError(w, http.StatusNotFound, "Standing order not found")
+	case *errors.ErrAccountNotFound:
+		h.writeError(w, http.StatusNotFound, "Account not found")
+	case *errors.ErrInvalidAmount, *errors.ErrCurrencyMismatch, *errors.ErrInvalidParameter, *errors.ErrSameAccountTransfer:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrUnsupportedCurrency:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case *errors.ErrCallerNotAuthorized, *errors.ErrApprovalRequired:
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case *errors.ErrStandingOrderNotActive:
+		h.writeError(w, http.StatusConflict, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, message)
+	}
+}
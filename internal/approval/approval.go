@@ -0,0 +1,190 @@
+package approval
+
+import (
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// Kind is the payment waiting for approval.
+type Kind string
+
+const (
+	KindWithdrawal Kind = "withdrawal"
+	KindTransfer   Kind = "transfer"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusExecuted Status = "executed"
+	StatusRejected Status = "rejected"
+	StatusFailed   Status = "failed"
+	StatusExpired  Status = "expired"
+)
+
+// Approval is a withdrawal or transfer from an account whose mandate needs
+// more holders to agree than the one who asked for it. It is executed once
+// enough signing holders have approved, or dropped when one rejects it or it
+// expires. ApprovedBy starts with the holder who requested it.
+type Approval struct {
+	ID          string                       `json:"id"`
+	AccountID   string                       `json:"account_id"`
+	Kind        Kind                         `json:"kind"`
+	Withdrawal  *transaction.WithdrawRequest `json:"withdrawal,omitempty"`
+	Transfer    *transaction.TransferRequest `json:"transfer,omitempty"`
+	Mandate     account.Mandate              `json:"mandate"`
+	Required    int                          `json:"required_approvals"`
+	RequestedBy string                       `json:"requested_by"`
+	ApprovedBy  []string                     `json:"approved_by"`
+	RejectedBy  string                       `json:"rejected_by,omitempty"`
+	Status      Status                       `json:"status"`
+	CreatedAt   time.Time                    `json:"created_at"`
+	UpdatedAt   time.Time                    `json:"updated_at"`
+	ExpiresAt   time.Time                    `json:"expires_at"`
+
+	// TransactionID is the payment made on approval, or the failed
+	// transaction recorded when it was declined.
+	TransactionID string `json:"transaction_id,omitempty"`
+	FailureCode   string `json:"failure_code,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// Expired reports whether a pending approval has outlived its expiry.
+func (a *Approval) Expired(now time.Time) bool {
+	return a.Status == StatusPending && !now.Before(a.ExpiresAt)
+}
+
+// Clone returns a copy of the approval that shares nothing with it.
+func (a *Approval) Clone() *Approval {
+	c := *a
+	c.ApprovedBy = append([]string(nil), a.ApprovedBy...)
+	if a.Withdrawal != nil {
+		withdrawal := *a.Withdrawal
+		c.Withdrawal = &withdrawal
+	}
+	if a.Transfer != nil {
+		transfer := *a.Transfer
+		c.Transfer = &transfer
+	}
+	return &c
+}
+
+type Service struct {
+	ttl time.Duration
+}
+
+func NewService(ttl time.Duration) *Service {
+	return &Service{ttl: ttl}
+}
+
+// Withdrawal builds a pending approval for a withdrawal requested by the
+// customer.
+func (s *Service) Withdrawal(acc *account.Account, req transaction.WithdrawRequest, requestedBy string, now time.Time) *Approval {
+	a := s.new(acc, KindWithdrawal, requestedBy, now)
+	a.Withdrawal = &req
+	return a
+}
+
+// Transfer builds a pending approval for a transfer requested by the
+// customer.
+func (s *Service) Transfer(acc *account.Account, req transaction.TransferRequest, requestedBy string, now time.Time) *Approval {
+	a := s.new(acc, KindTransfer, requestedBy, now)
+	a.Transfer = &req
+	return a
+}
+
+func (s *Service) new(acc *account.Account, kind Kind, requestedBy string, now time.Time) *Approval {
+	return &Approval{
+		ID:          uuid.New().String(),
+		AccountID:   acc.ID,
+		Kind:        kind,
+		Mandate:     acc.CurrentMandate(),
+		Required:    acc.RequiredApprovals(),
+		RequestedBy: requestedBy,
+		ApprovedBy:  []string{requestedBy},
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+}
+
+// Approve adds the customer's approval and reports whether the account's
+// mandate is now satisfied, in which case the payment should be made.
+func (s *Service) Approve(a *Approval, acc *account.Account, customerID string, now time.Time) (bool, error) {
+	if err := s.checkPending(a, now); err != nil {
+		return false, err
+	}
+	if !acc.CanSign(customerID) {
+		return false, &errors.ErrCallerNotAuthorized{AccountID: acc.ID, CustomerID: customerID, Action: "approve payments"}
+	}
+	for _, approver := range a.ApprovedBy {
+		if approver == customerID {
+			return false, &errors.ErrAlreadyApproved{ApprovalID: a.ID, CustomerID: customerID}
+		}
+	}
+
+	a.ApprovedBy = append(a.ApprovedBy, customerID)
+	a.Mandate = acc.CurrentMandate()
+	a.Required = acc.RequiredApprovals()
+	a.UpdatedAt = now
+	return acc.MandateSatisfied(a.ApprovedBy...), nil
+}
+
+// Reject drops the approval. Any signing holder may reject it.
+func (s *Service) Reject(a *Approval, acc *account.Account, customerID string, now time.Time) error {
+	if err := s.checkPending(a, now); err != nil {
+		return err
+	}
+	if !acc.CanSign(customerID) {
+		return &errors.ErrCallerNotAuthorized{AccountID: acc.ID, CustomerID: customerID, Action: "reject payments"}
+	}
+
+	a.Status = StatusRejected
+	a.RejectedBy = customerID
+	a.UpdatedAt = now
+	return nil
+}
+
+// Executed records the payment made once the approval was complete.
+func (s *Service) Executed(a *Approval, transactionID string, now time.Time) {
+	a.Status = StatusExecuted
+	a.TransactionID = transactionID
+	a.UpdatedAt = now
+}
+
+// Failed records that the approved payment was declined because of cause.
+func (s *Service) Failed(a *Approval, cause error, transactionID string, now time.Time) {
+	a.Status = StatusFailed
+	a.TransactionID = transactionID
+	a.FailureCode = errors.Code(cause)
+	a.FailureReason = cause.Error()
+	a.UpdatedAt = now
+}
+
+// Expire marks a pending approval past its expiry as expired.
+func (s *Service) Expire(a *Approval, now time.Time) error {
+	if !a.Expired(now) {
+		return &errors.ErrApprovalNotPending{ApprovalID: a.ID, Status: string(a.Status)}
+	}
+
+	a.Status = StatusExpired
+	a.UpdatedAt = now
+	return nil
+}
+
+func (s *Service) checkPending(a *Approval, now time.Time) error {
+	if a.Status != StatusPending {
+		return &errors.ErrApprovalNotPending{ApprovalID: a.ID, Status: string(a.Status)}
+	}
+	if a.Expired(now) {
+		return &errors.ErrApprovalNotPending{ApprovalID: a.ID, Status: string(StatusExpired)}
+	}
+	return nil
+}
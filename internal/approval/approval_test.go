@@ -0,0 +1,93 @@
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+func jointAccount(mandate account.Mandate) *account.Account {
+	return &account.Account{
+		ID:         "joint",
+		CustomerID: "a",
+		Holders: []account.Holder{
+			{CustomerID: "a", Role: account.RolePrimary},
+			{CustomerID: "b", Role: account.RoleJoint},
+			{CustomerID: "c", Role: account.RoleSignatory},
+			{CustomerID: "d", Role: account.RoleViewer},
+		},
+		Mandate: mandate,
+	}
+}
+
+func TestApprove(t *testing.T) {
+	s := NewService(time.Hour)
+	now := time.Now()
+	acc := jointAccount(account.MandateAll)
+
+	a := s.Withdrawal(acc, transaction.WithdrawRequest{AccountID: acc.ID, Amount: 500}, "a", now)
+	if a.Status != StatusPending || a.Required != 3 || len(a.ApprovedBy) != 1 {
+		t.Fatalf("Withdrawal() = %+v, want pending, 3 required, approved by the requester", a)
+	}
+
+	if _, ok := errorOf(s.Approve(a, acc, "a", now)).(*errors.ErrAlreadyApproved); !ok {
+		t.Error("Approve() expected *errors.ErrAlreadyApproved for the requester")
+	}
+	if _, ok := errorOf(s.Approve(a, acc, "d", now)).(*errors.ErrCallerNotAuthorized); !ok {
+		t.Error("Approve() expected *errors.ErrCallerNotAuthorized for a view-only holder")
+	}
+
+	ready, err := s.Approve(a, acc, "b", now)
+	if err != nil || ready {
+		t.Fatalf("Approve(b) = %v, %v, want not ready", ready, err)
+	}
+	ready, err = s.Approve(a, acc, "c", now)
+	if err != nil || !ready {
+		t.Fatalf("Approve(c) = %v, %v, want ready", ready, err)
+	}
+
+	s.Executed(a, "tx-1", now)
+	if _, ok := errorOf(s.Approve(a, acc, "b", now)).(*errors.ErrApprovalNotPending); !ok {
+		t.Error("Approve() expected *errors.ErrApprovalNotPending once executed")
+	}
+}
+
+func TestRejectAndExpire(t *testing.T) {
+	s := NewService(time.Hour)
+	now := time.Now()
+	acc := jointAccount(account.MandateTwoOfN)
+
+	a := s.Transfer(acc, transaction.TransferRequest{FromAccountID: acc.ID, ToAccountID: "other", Amount: 500}, "a", now)
+	if err := s.Reject(a, acc, "c", now); err != nil {
+		t.Fatalf("Reject() unexpected error = %v", err)
+	}
+	if a.Status != StatusRejected || a.RejectedBy != "c" {
+		t.Errorf("Reject() = %s by %q, want rejected by c", a.Status, a.RejectedBy)
+	}
+
+	late := s.Transfer(acc, transaction.TransferRequest{FromAccountID: acc.ID, ToAccountID: "other", Amount: 500}, "a", now)
+	later := now.Add(time.Hour)
+	if _, ok := errorOf(s.Approve(late, acc, "b", later)).(*errors.ErrApprovalNotPending); !ok {
+		t.Error("Approve() expected *errors.ErrApprovalNotPending after expiry")
+	}
+	if err := s.Expire(late, later); err != nil || late.Status != StatusExpired {
+		t.Errorf("Expire() = %v with status %s, want expired", err, late.Status)
+	}
+}
+
+func TestFailed(t *testing.T) {
+	s := NewService(time.Hour)
+	a := s.Withdrawal(jointAccount(account.MandateAnyOne), transaction.WithdrawRequest{Amount: 500}, "a", time.Now())
+
+	s.Failed(a, &errors.ErrInsufficientFunds{AccountID: "joint"}, "tx-failed", time.Now())
+	if a.Status != StatusFailed || a.FailureCode != "insufficient_funds" || a.TransactionID != "tx-failed" {
+		t.Errorf("Failed() = %+v", a)
+	}
+}
+
+func errorOf(_ bool, err error) error {
+	return err
+}
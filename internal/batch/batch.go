@@ -0,0 +1,307 @@
+package batch
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// MaxLegs caps the number of transfers in one batch.
+const MaxLegs = 1000
+
+// Mode decides what happens when a leg fails. All-or-nothing batches are
+// applied as a single unit, so one failed leg undoes the rest; best-effort
+// batches apply each leg on its own.
+type Mode string
+
+const (
+	ModeAllOrNothing Mode = "all_or_nothing"
+	ModeBestEffort   Mode = "best_effort"
+)
+
+type Status string
+
+const (
+	StatusCompleted          Status = "completed"
+	StatusPartiallyCompleted Status = "partially_completed"
+	StatusFailed             Status = "failed"
+
+	// StatusRejected means an all-or-nothing batch failed validation and
+	// none of it was attempted.
+	StatusRejected Status = "rejected"
+)
+
+type LegStatus string
+
+const (
+	LegPending   LegStatus = "pending"
+	LegCompleted LegStatus = "completed"
+	LegFailed    LegStatus = "failed"
+	LegInvalid   LegStatus = "invalid"
+
+	// LegRolledBack legs succeeded but were undone because a later leg of
+	// an all-or-nothing batch failed; LegSkipped legs were never attempted.
+	LegRolledBack LegStatus = "rolled_back"
+	LegSkipped    LegStatus = "skipped"
+)
+
+// Leg is one transfer in a batch.
+type Leg struct {
+	FromAccountID string `json:"from_account_id"`
+	ToAccountID   string `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency,omitempty"`
+	Reference     string `json:"reference,omitempty"`
+}
+
+func (l Leg) TransferRequest() transaction.TransferRequest {
+	return transaction.TransferRequest{
+		FromAccountID: l.FromAccountID,
+		ToAccountID:   l.ToAccountID,
+		Amount:        l.Amount,
+		Currency:      l.Currency,
+	}
+}
+
+// Request submits a batch. FromAccountID, if set, is used for every leg
+// that does not name its own source account, as for a payroll run.
+type Request struct {
+	Mode          Mode   `json:"mode,omitempty"`
+	FromAccountID string `json:"from_account_id,omitempty"`
+	Legs          []Leg  `json:"legs"`
+}
+
+// LegResult reports what happened to the leg at Index in the request.
+type LegResult struct {
+	Leg
+	Index         int       `json:"index"`
+	Status        LegStatus `json:"status"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	FailureCode   string    `json:"failure_code,omitempty"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+}
+
+type Batch struct {
+	ID          string      `json:"id"`
+	Mode        Mode        `json:"mode"`
+	Status      Status      `json:"status"`
+	Total       int         `json:"total"`
+	Succeeded   int         `json:"succeeded"`
+	Failed      int         `json:"failed"`
+	Legs        []LegResult `json:"legs"`
+	CreatedAt   time.Time   `json:"created_at"`
+	CompletedAt time.Time   `json:"completed_at"`
+}
+
+// Clone returns a copy of the batch that shares no leg results with it.
+func (b *Batch) Clone() *Batch {
+	copied := *b
+	copied.Legs = append([]LegResult(nil), b.Legs...)
+	return &copied
+}
+
+// HasInvalid reports whether any leg failed validation.
+func (b *Batch) HasInvalid() bool {
+	for _, leg := range b.Legs {
+		if leg.Status == LegInvalid {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCSV reads batch legs from CSV with a header row naming the columns:
+// to_account_id and amount are required, from_account_id, currency and
+// reference optional.
+func ParseCSV(r io.Reader) ([]Leg, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &errors.ErrInvalidParameter{Name: "csv header", Value: errString(err)}
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "from_account_id", "to_account_id", "amount", "currency", "reference":
+			columns[name] = i
+		default:
+			return nil, &errors.ErrInvalidParameter{Name: "csv column", Value: name}
+		}
+	}
+	for _, required := range []string{"to_account_id", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, &errors.ErrInvalidParameter{Name: "csv column", Value: required}
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var legs []Leg
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return legs, nil
+		}
+		if err != nil {
+			return nil, &errors.ErrInvalidParameter{Name: fmt.Sprintf("csv line %d", line), Value: errString(err)}
+		}
+
+		amount, err := strconv.ParseInt(field(record, "amount"), 10, 64)
+		if err != nil {
+			return nil, &errors.ErrInvalidParameter{Name: fmt.Sprintf("csv line %d amount", line), Value: field(record, "amount")}
+		}
+		legs = append(legs, Leg{
+			FromAccountID: field(record, "from_account_id"),
+			ToAccountID:   field(record, "to_account_id"),
+			Amount:        amount,
+			Currency:      field(record, "currency"),
+			Reference:     field(record, "reference"),
+		})
+	}
+}
+
+func errString(err error) string {
+	if err == io.EOF {
+		return "empty file"
+	}
+	return err.Error()
+}
+
+type Service struct{}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+// New builds a batch for req with every leg pending, or invalid if it fails
+// the checks that need no account data. Only an unusable request as a whole
+// is an error.
+func (s *Service) New(req Request, now time.Time) (*Batch, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = ModeAllOrNothing
+	}
+	if mode != ModeAllOrNothing && mode != ModeBestEffort {
+		return nil, &errors.ErrInvalidParameter{Name: "mode", Value: string(req.Mode)}
+	}
+	if len(req.Legs) == 0 || len(req.Legs) > MaxLegs {
+		return nil, &errors.ErrInvalidParameter{Name: "legs", Value: strconv.Itoa(len(req.Legs))}
+	}
+
+	b := &Batch{
+		ID:        uuid.New().String(),
+		Mode:      mode,
+		Total:     len(req.Legs),
+		Legs:      make([]LegResult, len(req.Legs)),
+		CreatedAt: now,
+	}
+	for i, leg := range req.Legs {
+		if leg.FromAccountID == "" {
+			leg.FromAccountID = req.FromAccountID
+		}
+		b.Legs[i] = LegResult{Leg: leg, Index: i, Status: LegPending}
+		if err := validate(leg); err != nil {
+			s.Invalid(b, i, err)
+		}
+	}
+	return b, nil
+}
+
+func validate(leg Leg) error {
+	switch {
+	case leg.FromAccountID == "":
+		return &errors.ErrInvalidParameter{Name: "from_account_id", Value: ""}
+	case leg.ToAccountID == "":
+		return &errors.ErrInvalidParameter{Name: "to_account_id", Value: ""}
+	case leg.Amount <= 0:
+		return &errors.ErrInvalidAmount{Amount: leg.Amount}
+	case leg.FromAccountID == leg.ToAccountID:
+		return &errors.ErrSameAccountTransfer{FromAccountID: leg.FromAccountID, ToAccountID: leg.ToAccountID}
+	}
+	return nil
+}
+
+// Invalid marks leg i as failing validation because of cause.
+func (s *Service) Invalid(b *Batch, i int, cause error) {
+	b.Legs[i].Status = LegInvalid
+	b.Legs[i].FailureCode = failureCode(cause)
+	b.Legs[i].FailureReason = cause.Error()
+}
+
+func (s *Service) Succeeded(b *Batch, i int, transactionID string) {
+	b.Legs[i].Status = LegCompleted
+	b.Legs[i].TransactionID = transactionID
+}
+
+// Failed marks leg i as declined because of cause; transactionID is the
+// failed transaction recorded for it, if any.
+func (s *Service) Failed(b *Batch, i int, cause error, transactionID string) {
+	b.Legs[i].Status = LegFailed
+	b.Legs[i].TransactionID = transactionID
+	b.Legs[i].FailureCode = failureCode(cause)
+	b.Legs[i].FailureReason = cause.Error()
+}
+
+// Abort settles an all-or-nothing batch that did not go through: legs that
+// had succeeded are rolled back and those not yet attempted skipped.
+func (s *Service) Abort(b *Batch) {
+	for i := range b.Legs {
+		switch b.Legs[i].Status {
+		case LegCompleted:
+			b.Legs[i].Status = LegRolledBack
+			b.Legs[i].TransactionID = ""
+		case LegPending:
+			b.Legs[i].Status = LegSkipped
+		}
+	}
+}
+
+// Complete counts the leg results and sets the batch's overall status.
+func (s *Service) Complete(b *Batch, now time.Time) {
+	b.Succeeded, b.Failed = 0, 0
+	for _, leg := range b.Legs {
+		if leg.Status == LegCompleted {
+			b.Succeeded++
+		} else {
+			b.Failed++
+		}
+	}
+
+	switch {
+	case b.Succeeded == b.Total:
+		b.Status = StatusCompleted
+	case b.Succeeded > 0:
+		b.Status = StatusPartiallyCompleted
+	case b.Mode == ModeAllOrNothing && b.HasInvalid():
+		b.Status = StatusRejected
+	default:
+		b.Status = StatusFailed
+	}
+	b.CompletedAt = now
+}
+
+func failureCode(err error) string {
+	if code := errors.Code(err); code != "" {
+		return code
+	}
+	if _, ok := err.(*errors.ErrInvalidParameter); ok {
+		return "invalid_parameter"
+	}
+	return "processing_error"
+}
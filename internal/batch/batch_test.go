@@ -0,0 +1,120 @@
+package batch
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"banking-service/pkg/errors"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := "to_account_id, amount, reference\nemp-1, 250000, salary\nemp-2,300000,salary\n"
+
+	legs, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(legs) != 2 {
+		t.Fatalf("got %d legs, want 2", len(legs))
+	}
+	want := Leg{ToAccountID: "emp-2", Amount: 300000, Reference: "salary"}
+	if legs[1] != want {
+		t.Errorf("got %+v, want %+v", legs[1], want)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"unknown column", "to_account_id,amount,iban\nb,100,x\n"},
+		{"missing amount column", "to_account_id\nb\n"},
+		{"bad amount", "to_account_id,amount\nb,12.50\n"},
+		{"short row", "to_account_id,amount\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCSV(strings.NewReader(tt.input)); err == nil {
+				t.Error("ParseCSV() expected an error")
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	s := NewService()
+	now := time.Now()
+
+	b, err := s.New(Request{
+		FromAccountID: "payroll",
+		Legs: []Leg{
+			{ToAccountID: "emp-1", Amount: 100},
+			{ToAccountID: "emp-2", Amount: 0},
+			{FromAccountID: "other", ToAccountID: "other", Amount: 100},
+		},
+	}, now)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if b.Mode != ModeAllOrNothing {
+		t.Errorf("got mode %s, want all_or_nothing by default", b.Mode)
+	}
+	if b.Legs[0].Status != LegPending || b.Legs[0].FromAccountID != "payroll" {
+		t.Errorf("leg 0 = %+v, want pending from payroll", b.Legs[0])
+	}
+	if b.Legs[1].Status != LegInvalid || b.Legs[1].FailureCode != "invalid_amount" {
+		t.Errorf("leg 1 = %+v, want invalid amount", b.Legs[1])
+	}
+	if b.Legs[2].Status != LegInvalid || b.Legs[2].FailureCode != "same_account_transfer" {
+		t.Errorf("leg 2 = %+v, want same account transfer", b.Legs[2])
+	}
+
+	if _, err := s.New(Request{Mode: "sometimes", Legs: []Leg{{}}}, now); err == nil {
+		t.Error("New() expected an error for an unknown mode")
+	}
+	if _, err := s.New(Request{}, now); err == nil {
+		t.Error("New() expected an error for an empty batch")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	s := NewService()
+	now := time.Now()
+	legs := []Leg{
+		{FromAccountID: "a", ToAccountID: "b", Amount: 100},
+		{FromAccountID: "a", ToAccountID: "c", Amount: 100},
+		{FromAccountID: "a", ToAccountID: "d", Amount: 100},
+	}
+
+	best, _ := s.New(Request{Mode: ModeBestEffort, Legs: legs}, now)
+	s.Succeeded(best, 0, "tx-1")
+	s.Failed(best, 1, &errors.ErrInsufficientFunds{AccountID: "a"}, "tx-2")
+	s.Succeeded(best, 2, "tx-3")
+	s.Complete(best, now)
+	if best.Status != StatusPartiallyCompleted || best.Succeeded != 2 || best.Failed != 1 {
+		t.Errorf("best effort: got %s with %d/%d, want partially_completed with 2/1", best.Status, best.Succeeded, best.Failed)
+	}
+
+	atomic, _ := s.New(Request{Legs: legs}, now)
+	s.Succeeded(atomic, 0, "tx-1")
+	s.Failed(atomic, 1, &errors.ErrInsufficientFunds{AccountID: "a"}, "tx-2")
+	s.Abort(atomic)
+	s.Complete(atomic, now)
+	if atomic.Status != StatusFailed || atomic.Succeeded != 0 {
+		t.Errorf("all or nothing: got %s with %d succeeded, want failed with none", atomic.Status, atomic.Succeeded)
+	}
+	want := []LegStatus{LegRolledBack, LegFailed, LegSkipped}
+	for i, status := range want {
+		if atomic.Legs[i].Status != status {
+			t.Errorf("leg %d: got %s, want %s", i, atomic.Legs[i].Status, status)
+		}
+	}
+	if atomic.Legs[0].TransactionID != "" {
+		t.Errorf("rolled back leg keeps transaction %s", atomic.Legs[0].TransactionID)
+	}
+}
@@ -0,0 +1,266 @@
+package beneficiary
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// NameMatch is the result of checking the name a customer gave for a payee
+// against the name on the payee's account.
+type NameMatch string
+
+const (
+	NameMatchExact NameMatch = "match"
+	NameMatchClose NameMatch = "close_match"
+	NameMatchNone  NameMatch = "no_match"
+)
+
+// Beneficiary is a payee registered on an account. Transfers to it can only
+// be made once its name is verified, and are capped while it is cooling off.
+type Beneficiary struct {
+	ID             string    `json:"id"`
+	AccountID      string    `json:"account_id"`
+	PayeeAccountID string    `json:"payee_account_id"`
+	Name           string    `json:"name"`
+	Nickname       string    `json:"nickname,omitempty"`
+	NameMatch      NameMatch `json:"name_match,omitempty"`
+	// MatchedName is the name on the payee's account, given back on a close
+	// match so the customer can correct theirs.
+	MatchedName     string     `json:"matched_name,omitempty"`
+	AddedBy         string     `json:"added_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	VerifiedAt      *time.Time `json:"verified_at,omitempty"`
+	CoolingOffUntil time.Time  `json:"cooling_off_until"`
+}
+
+// Verified reports whether the payee's name matched when last checked.
+func (b *Beneficiary) Verified() bool {
+	return b.NameMatch == NameMatchExact
+}
+
+// CoolingOff reports whether transfers to the payee are still capped at now.
+func (b *Beneficiary) CoolingOff(now time.Time) bool {
+	return now.Before(b.CoolingOffUntil)
+}
+
+type AddBeneficiaryRequest struct {
+	// PayeeAccountID may also be the payee's account number or IBAN.
+	PayeeAccountID string `json:"payee_account_id"`
+	Name           string `json:"name"`
+	Nickname       string `json:"nickname,omitempty"`
+}
+
+// VerifyBeneficiaryRequest rechecks the payee's name, replacing it first if
+// Name is set.
+type VerifyBeneficiaryRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
+// Service adds and verifies payees and decides whether a transfer to one may
+// go ahead. For coolingOff after a payee is added, at most coolingOffLimit
+// may be sent to it in total. Payees that are not registered are capped the
+// same way for as long as they stay unregistered.
+type Service struct {
+	coolingOff      time.Duration
+	coolingOffLimit int64
+	required        bool
+}
+
+// NewService returns a service applying the cooling-off policy. With
+// required set, transfers may only be made to registered payees.
+func NewService(coolingOff time.Duration, coolingOffLimit int64, required bool) *Service {
+	return &Service{coolingOff: coolingOff, coolingOffLimit: coolingOffLimit, required: required}
+}
+
+// Required reports whether transfers to accounts that are not registered
+// payees are refused.
+func (s *Service) Required() bool {
+	return s.required
+}
+
+// Add registers payee on acc. The payee starts unverified and cooling off.
+func (s *Service) Add(acc, payee *account.Account, req AddBeneficiaryRequest, addedBy string, now time.Time) (*Beneficiary, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &errors.ErrInvalidParameter{Name: "name", Value: req.Name}
+	}
+	if payee.ID == acc.ID {
+		return nil, &errors.ErrInvalidParameter{Name: "payee_account_id", Value: req.PayeeAccountID}
+	}
+	if acc.CurrentStatus() == account.StatusClosed {
+		return nil, &errors.ErrAccountClosed{AccountID: acc.ID}
+	}
+	if payee.CurrentStatus() == account.StatusClosed {
+		return nil, &errors.ErrAccountClosed{AccountID: payee.ID}
+	}
+
+	return &Beneficiary{
+		ID:              uuid.New().String(),
+		AccountID:       acc.ID,
+		PayeeAccountID:  payee.ID,
+		Name:            name,
+		Nickname:        strings.TrimSpace(req.Nickname),
+		AddedBy:         addedBy,
+		CreatedAt:       now,
+		CoolingOffUntil: now.Add(s.coolingOff),
+	}, nil
+}
+
+// Verify checks the beneficiary's name against the name on the payee's
+// account, after replacing it with req.Name if one is given.
+func (s *Service) Verify(b *Beneficiary, payee *account.Account, req VerifyBeneficiaryRequest, now time.Time) {
+	if name := strings.TrimSpace(req.Name); name != "" {
+		b.Name = name
+	}
+
+	b.NameMatch = MatchName(b.Name, payee.CustomerName)
+	b.MatchedName = ""
+	if b.NameMatch == NameMatchClose {
+		b.MatchedName = payee.CustomerName
+	}
+	b.VerifiedAt = &now
+}
+
+// CountsFrom returns when the transfers that count toward a payee's
+// cooling-off limit at now start: one cooling-off period earlier, whether or
+// not the payee was registered then, so removing and re-adding a payee does
+// not reset what has been sent to it.
+func (s *Service) CountsFrom(now time.Time) time.Time {
+	return now.Add(-s.coolingOff)
+}
+
+// Check returns an error if amount may not be sent to the beneficiary at now,
+// given that sent has already been sent to it since CountsFrom(now).
+func (s *Service) Check(b *Beneficiary, amount, sent int64, now time.Time) error {
+	if !b.Verified() {
+		return &errors.ErrBeneficiaryNotVerified{BeneficiaryID: b.ID, NameMatch: string(b.NameMatch)}
+	}
+	if b.CoolingOff(now) && sent+amount > s.coolingOffLimit {
+		return s.coolingOffError(b.ID, b.PayeeAccountID, b.CoolingOffUntil, sent)
+	}
+	return nil
+}
+
+// CheckUnregistered returns an error if amount may not be sent to
+// payeeAccountID, which is not a registered payee, given that sent has
+// already been sent to it since CountsFrom(now). Such payees never finish
+// cooling off.
+func (s *Service) CheckUnregistered(payeeAccountID string, amount, sent int64) error {
+	if sent+amount > s.coolingOffLimit {
+		return s.coolingOffError("", payeeAccountID, time.Time{}, sent)
+	}
+	return nil
+}
+
+func (s *Service) coolingOffError(beneficiaryID, payeeAccountID string, until time.Time, sent int64) error {
+	remaining := s.coolingOffLimit - sent
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &errors.ErrBeneficiaryCoolingOff{
+		BeneficiaryID:  beneficiaryID,
+		PayeeAccountID: payeeAccountID,
+		Until:          until,
+		Max:            s.coolingOffLimit,
+		Remaining:      remaining,
+	}
+}
+
+// MatchName compares the name a customer gave for a payee with the name on
+// the payee's account. Case, punctuation and titles are ignored; names with
+// the same words in another order, initials for words, or a small typo are a
+// close match.
+func MatchName(given, actual string) NameMatch {
+	g, a := nameWords(given), nameWords(actual)
+	if len(g) == 0 || len(a) == 0 {
+		return NameMatchNone
+	}
+	if strings.Join(g, " ") == strings.Join(a, " ") {
+		return NameMatchExact
+	}
+	if sameWords(g, a) || initialsMatch(g, a) || nearSpelling(strings.Join(g, ""), strings.Join(a, "")) {
+		return NameMatchClose
+	}
+	return NameMatchNone
+}
+
+var titles = map[string]bool{"mr": true, "mrs": true, "ms": true, "miss": true, "dr": true}
+
+func nameWords(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	kept := words[:0]
+	for _, w := range words {
+		if !titles[w] {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
+
+func sameWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return strings.Join(a, " ") == strings.Join(b, " ")
+}
+
+// initialsMatch reports whether given is actual with some words shortened
+// to their first letter, keeping at least one word in full.
+func initialsMatch(given, actual []string) bool {
+	if len(given) != len(actual) {
+		return false
+	}
+	full := false
+	for i, w := range given {
+		switch {
+		case w == actual[i]:
+			full = true
+		case len([]rune(w)) == 1 && strings.HasPrefix(actual[i], w):
+		default:
+			return false
+		}
+	}
+	return full
+}
+
+// nearSpelling reports whether a and b are at most two edits apart, and no
+// more than one edit per four letters.
+func nearSpelling(a, b string) bool {
+	d := editDistance(a, b)
+	return d <= 2 && d*4 <= len([]rune(b))
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,109 @@
+package beneficiary
+
+import (
+	"testing"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/errors"
+)
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		given, actual string
+		want          NameMatch
+	}{
+		{"Ravi Kumar", "Ravi Kumar", NameMatchExact},
+		{"ravi  kumar.", "Ravi Kumar", NameMatchExact},
+		{"Mr Ravi Kumar", "Ravi Kumar", NameMatchExact},
+		{"Kumar Ravi", "Ravi Kumar", NameMatchClose},
+		{"R Kumar", "Ravi Kumar", NameMatchClose},
+		{"Ravi Kumaar", "Ravi Kumar", NameMatchClose},
+		{"R K", "Ravi Kumar", NameMatchNone},
+		{"Asha Rao", "Ravi Kumar", NameMatchNone},
+		{"Al", "Bo", NameMatchNone},
+		{"", "Ravi Kumar", NameMatchNone},
+	}
+
+	for _, tt := range tests {
+		if got := MatchName(tt.given, tt.actual); got != tt.want {
+			t.Errorf("MatchName(%q, %q) = %s, want %s", tt.given, tt.actual, got, tt.want)
+		}
+	}
+}
+
+func TestAddAndVerify(t *testing.T) {
+	s := NewService(24*time.Hour, 5000, false)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	acc := &account.Account{ID: "acc-1", CustomerName: "Asha Rao"}
+	payee := &account.Account{ID: "acc-2", CustomerName: "Ravi Kumar"}
+
+	if _, err := s.Add(acc, acc, AddBeneficiaryRequest{Name: "Asha Rao"}, "", now); err == nil {
+		t.Error("Add() expected an error for the account itself")
+	}
+	if _, err := s.Add(acc, payee, AddBeneficiaryRequest{Name: " "}, "", now); err == nil {
+		t.Error("Add() expected an error for a blank name")
+	}
+	closed := &account.Account{ID: "acc-3", CustomerName: "Ravi Kumar", Status: account.StatusClosed}
+	if _, err := s.Add(acc, closed, AddBeneficiaryRequest{Name: "Ravi Kumar"}, "", now); err == nil {
+		t.Error("Add() expected an error for a closed payee")
+	} else if _, ok := err.(*errors.ErrAccountClosed); !ok {
+		t.Errorf("Add() error = %v, want *errors.ErrAccountClosed", err)
+	}
+
+	b, err := s.Add(acc, payee, AddBeneficiaryRequest{Name: "R Kumar", Nickname: "Landlord"}, "cust-1", now)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if b.PayeeAccountID != "acc-2" || b.Verified() || !b.CoolingOffUntil.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("Add() = %+v, want an unverified payee cooling off for a day", b)
+	}
+
+	s.Verify(b, payee, VerifyBeneficiaryRequest{}, now)
+	if b.NameMatch != NameMatchClose || b.MatchedName != "Ravi Kumar" || b.Verified() {
+		t.Errorf("Verify() = %s with %q, want a close match naming the account holder", b.NameMatch, b.MatchedName)
+	}
+
+	s.Verify(b, payee, VerifyBeneficiaryRequest{Name: "Ravi Kumar"}, now)
+	if !b.Verified() || b.MatchedName != "" || b.VerifiedAt == nil {
+		t.Errorf("Verify() with the corrected name = %+v, want verified", b)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := NewService(24*time.Hour, 5000, false)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := &Beneficiary{ID: "ben-1", CreatedAt: now, CoolingOffUntil: now.Add(24 * time.Hour)}
+
+	if _, ok := s.Check(b, 100, 0, now).(*errors.ErrBeneficiaryNotVerified); !ok {
+		t.Error("Check() expected *errors.ErrBeneficiaryNotVerified before verification")
+	}
+
+	b.NameMatch = NameMatchExact
+	if err := s.Check(b, 3000, 2000, now); err != nil {
+		t.Errorf("Check() up to the cooling-off limit error = %v", err)
+	}
+
+	err := s.Check(b, 3000, 4000, now)
+	coolingOff, ok := err.(*errors.ErrBeneficiaryCoolingOff)
+	if !ok {
+		t.Fatalf("Check() error = %v, want *errors.ErrBeneficiaryCoolingOff", err)
+	}
+	if coolingOff.Remaining != 1000 || coolingOff.Max != 5000 {
+		t.Errorf("Check() remaining %d of %d, want 1000 of 5000", coolingOff.Remaining, coolingOff.Max)
+	}
+
+	if err := s.Check(b, 100000, 4000, b.CoolingOffUntil); err != nil {
+		t.Errorf("Check() after cooling off error = %v", err)
+	}
+
+	if err := s.CheckUnregistered("payee-id", 3000, 2000); err != nil {
+		t.Errorf("CheckUnregistered() up to the limit error = %v", err)
+	}
+	if _, ok := s.CheckUnregistered("payee-id", 3000, 4000).(*errors.ErrBeneficiaryCoolingOff); !ok {
+		t.Error("CheckUnregistered() expected *errors.ErrBeneficiaryCoolingOff beyond the limit")
+	}
+	if got := s.CountsFrom(b.CoolingOffUntil); !got.Equal(now) {
+		t.Errorf("CountsFrom() = %v, want %v", got, now)
+	}
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	StoreBackendMemory = "memory"
+	StoreBackendFile   = "file"
+)
+
+type Config struct {
+	Port         string
+	LogLevel     string
+	StoreBackend string
+
+	StoreDir          string
+	SnapshotInterval  time.Duration
+	SnapshotThreshold int
+
+	IdempotencyKeyTTL        time.Duration
+	IdempotencyPurgeInterval time.Duration
+
+	DormancyPeriod        time.Duration
+	DormancySweepInterval time.Duration
+
+	// FXRatesFile, when set, is a JSON file of "FROM/TO" pairs to rates and
+	// takes precedence over the inline FXRates list.
+	FXRatesFile          string
+	FXRates              string
+	FXQuoteTTL           time.Duration
+	FXQuotePurgeInterval time.Duration
+
+	HoldTTL            time.Duration
+	HoldExpiryInterval time.Duration
+
+	// AsyncTransferThreshold is the amount from which transfers are accepted
+	// as pending and settled in the background. Zero keeps every transfer
+	// synchronous.
+	AsyncTransferThreshold int64
+	TransferWorkers        int
+	TransferQueueSize      int
+	TransferMaxAttempts    int
+	TransferRetryBackoff   time.Duration
+	// TransferRecoveryInterval is how often pending transfers that never
+	// made it onto the queue, e.g. because it was full, are queued again.
+	TransferRecoveryInterval time.Duration
+
+	// OverdraftInterestRate is the annual rate, such as "0.18", charged daily
+	// on overdrawn balances. Empty charges no interest.
+	OverdraftInterestRate    string
+	OverdraftDailyFee        int64
+	OverdraftAccrualInterval time.Duration
+
+	// InterestRatesFile is a JSON rate table by account type. Without one no
+	// account earns interest.
+	InterestRatesFile       string
+	InterestAccrualInterval time.Duration
+
+	// FeeScheduleFile is a JSON list of fee rules for withdrawals and
+	// transfers. Without one they are free.
+	FeeScheduleFile string
+
+	// LimitsFile is a JSON list of the default transaction limits for
+	// accounts that do not set their own.
+	LimitsFile string
+
+	// A standing order payment declined for insufficient funds is retried
+	// StandingOrderMaxRetries times, StandingOrderRetryDelay apart.
+	StandingOrderInterval   time.Duration
+	StandingOrderMaxRetries int
+	StandingOrderRetryDelay time.Duration
+
+	// ApprovalTTL is how long a payment waiting for joint holders to approve
+	// it stays open.
+	ApprovalTTL            time.Duration
+	ApprovalExpiryInterval time.Duration
+
+	// Account numbers are allocated in AccountBranchCode unless another
+	// branch is asked for. IBANs are generated only when IBANCountryCode and
+	// IBANBankCode are both set.
+	AccountBranchCode string
+	IBANCountryCode   string
+	IBANBankCode      string
+
+	// For BeneficiaryCoolingOff after a payee is added, and for as long as
+	// an account is not a registered payee, at most
+	// BeneficiaryCoolingOffLimit may be transferred to it. With
+	// BeneficiaryRequired, transfers may only be made to registered payees.
+	BeneficiaryCoolingOff      time.Duration
+	BeneficiaryCoolingOffLimit int64
+	BeneficiaryRequired        bool
+}
+
+func Load() (*Config, error) {
+	cfg := &Config{
+		Port:         getEnv("PORT", "8080"),
+		LogLevel:     getEnv("LOG_LEVEL", "info"),
+		StoreBackend: getEnv("STORE_BACKEND", StoreBackendMemory),
+		StoreDir:     getEnv("STORE_DIR", "data"),
+		FXRatesFile:  os.Getenv("FX_RATES_FILE"),
+		FXRates:      os.Getenv("FX_RATES"),
+
+		OverdraftInterestRate: os.Getenv("OVERDRAFT_INTEREST_RATE"),
+		InterestRatesFile:     os.Getenv("INTEREST_RATES_FILE"),
+		FeeScheduleFile:       os.Getenv("FEE_SCHEDULE_FILE"),
+		LimitsFile:            os.Getenv("LIMITS_FILE"),
+
+		AccountBranchCode: getEnv("ACCOUNT_BRANCH_CODE", "0001"),
+		IBANCountryCode:   os.Getenv("IBAN_COUNTRY_CODE"),
+		IBANBankCode:      os.Getenv("IBAN_BANK_CODE"),
+	}
+
+	var err error
+	if cfg.SnapshotInterval, err = getDuration("SNAPSHOT_INTERVAL", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.SnapshotThreshold, err = getInt("SNAPSHOT_THRESHOLD", 1000); err != nil {
+		return nil, err
+	}
+	if cfg.IdempotencyKeyTTL, err = getDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.IdempotencyPurgeInterval, err = getDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+
+	dormancyDays, err := getInt("DORMANCY_DAYS", 365)
+	if err != nil {
+		return nil, err
+	}
+	cfg.DormancyPeriod = time.Duration(dormancyDays) * 24 * time.Hour
+	if cfg.DormancySweepInterval, err = getDuration("DORMANCY_SWEEP_INTERVAL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.FXQuoteTTL, err = getDuration("FX_QUOTE_TTL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.FXQuotePurgeInterval, err = getDuration("FX_QUOTE_PURGE_INTERVAL", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.HoldTTL, err = getDuration("HOLD_TTL", 7*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.HoldExpiryInterval, err = getDuration("HOLD_EXPIRY_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+
+	threshold, err := getInt("ASYNC_TRANSFER_THRESHOLD", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AsyncTransferThreshold = int64(threshold)
+	if cfg.TransferWorkers, err = getInt("TRANSFER_WORKERS", 4); err != nil {
+		return nil, err
+	}
+	if cfg.TransferQueueSize, err = getInt("TRANSFER_QUEUE_SIZE", 1024); err != nil {
+		return nil, err
+	}
+	if cfg.TransferMaxAttempts, err = getInt("TRANSFER_MAX_ATTEMPTS", 5); err != nil {
+		return nil, err
+	}
+	if cfg.TransferRetryBackoff, err = getDuration("TRANSFER_RETRY_BACKOFF", time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.TransferRecoveryInterval, err = getDuration("TRANSFER_RECOVERY_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+
+	dailyFee, err := getInt("OVERDRAFT_DAILY_FEE", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OverdraftDailyFee = int64(dailyFee)
+	if cfg.OverdraftAccrualInterval, err = getDuration("OVERDRAFT_ACCRUAL_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.InterestAccrualInterval, err = getDuration("INTEREST_ACCRUAL_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.StandingOrderInterval, err = getDuration("STANDING_ORDER_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.StandingOrderMaxRetries, err = getInt("STANDING_ORDER_MAX_RETRIES", 3); err != nil {
+		return nil, err
+	}
+	if cfg.StandingOrderRetryDelay, err = getDuration("STANDING_ORDER_RETRY_DELAY", 4*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.ApprovalTTL, err = getDuration("APPROVAL_TTL", 72*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.ApprovalExpiryInterval, err = getDuration("APPROVAL_EXPIRY_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.BeneficiaryCoolingOff, err = getDuration("BENEFICIARY_COOLING_OFF", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	coolingOffLimit, err := getInt("BENEFICIARY_COOLING_OFF_LIMIT", 1000000)
+	if err != nil {
+		return nil, err
+	}
+	cfg.BeneficiaryCoolingOffLimit = int64(coolingOffLimit)
+	if cfg.BeneficiaryRequired, err = getBool("BENEFICIARY_REQUIRED", false); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return d, nil
+}
+
+func getInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return n, nil
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,174 @@
+package customer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// DateLayout is the format of a customer's date of birth.
+const DateLayout = "2006-01-02"
+
+type Address struct {
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city,omitempty"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+// Customer is a person who holds accounts with the bank. Accounts point at
+// their customer through account.Account.CustomerID.
+type Customer struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	DateOfBirth string    `json:"date_of_birth,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Phone       string    `json:"phone,omitempty"`
+	Address     Address   `json:"address"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateCustomerRequest struct {
+	Name        string  `json:"name"`
+	DateOfBirth string  `json:"date_of_birth,omitempty"`
+	Email       string  `json:"email,omitempty"`
+	Phone       string  `json:"phone,omitempty"`
+	Address     Address `json:"address"`
+}
+
+// UpdateCustomerRequest changes the fields that are set and leaves the rest
+// as they are. An Address replaces the whole address.
+type UpdateCustomerRequest struct {
+	Name        *string  `json:"name,omitempty"`
+	DateOfBirth *string  `json:"date_of_birth,omitempty"`
+	Email       *string  `json:"email,omitempty"`
+	Phone       *string  `json:"phone,omitempty"`
+	Address     *Address `json:"address,omitempty"`
+}
+
+// Balance is the total of a customer's accounts in one currency.
+type Balance struct {
+	Currency         string `json:"currency"`
+	Balance          int64  `json:"balance"`
+	AvailableBalance int64  `json:"available_balance"`
+	Accounts         int    `json:"accounts"`
+}
+
+type Service struct{}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Create(req CreateCustomerRequest, now time.Time) (*Customer, error) {
+	c := &Customer{
+		ID:          uuid.New().String(),
+		Name:        strings.TrimSpace(req.Name),
+		DateOfBirth: req.DateOfBirth,
+		Email:       strings.TrimSpace(req.Email),
+		Phone:       strings.TrimSpace(req.Phone),
+		Address:     req.Address,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := validate(c, now); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Service) Update(c *Customer, req UpdateCustomerRequest, now time.Time) error {
+	updated := *c
+	if req.Name != nil {
+		updated.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.DateOfBirth != nil {
+		updated.DateOfBirth = *req.DateOfBirth
+	}
+	if req.Email != nil {
+		updated.Email = strings.TrimSpace(*req.Email)
+	}
+	if req.Phone != nil {
+		updated.Phone = strings.TrimSpace(*req.Phone)
+	}
+	if req.Address != nil {
+		updated.Address = *req.Address
+	}
+	if err := validate(&updated, now); err != nil {
+		return err
+	}
+
+	updated.UpdatedAt = now
+	*c = updated
+	return nil
+}
+
+func validate(c *Customer, now time.Time) error {
+	if c.Name == "" {
+		return &errors.ErrInvalidCustomerName{Name: c.Name}
+	}
+	if c.DateOfBirth != "" {
+		dob, err := time.Parse(DateLayout, c.DateOfBirth)
+		if err != nil || dob.After(now) {
+			return &errors.ErrInvalidParameter{Name: "date_of_birth", Value: c.DateOfBirth}
+		}
+	}
+	if c.Email != "" {
+		local, domain, ok := strings.Cut(c.Email, "@")
+		if !ok || local == "" || !strings.Contains(domain, ".") {
+			return &errors.ErrInvalidParameter{Name: "email", Value: c.Email}
+		}
+	}
+	return nil
+}
+
+// CanDelete returns *errors.ErrCustomerHasAccounts if any of the customer's
+// accounts is still open.
+func (s *Service) CanDelete(c *Customer, accounts []*account.Account) error {
+	open := 0
+	for _, acc := range accounts {
+		if acc.CurrentStatus() != account.StatusClosed {
+			open++
+		}
+	}
+	if open > 0 {
+		return &errors.ErrCustomerHasAccounts{CustomerID: c.ID, Accounts: open}
+	}
+	return nil
+}
+
+// Balances totals the accounts' balances per currency, ignoring closed
+// accounts, sorted by currency.
+func (s *Service) Balances(accounts []*account.Account) []Balance {
+	totals := make(map[string]*Balance)
+	for _, acc := range accounts {
+		if acc.CurrentStatus() == account.StatusClosed {
+			continue
+		}
+
+		currency := acc.CurrentCurrency()
+		total, ok := totals[currency]
+		if !ok {
+			total = &Balance{Currency: currency}
+			totals[currency] = total
+		}
+		total.Balance += acc.Balance
+		total.AvailableBalance += acc.AvailableBalance()
+		total.Accounts++
+	}
+
+	balances := make([]Balance, 0, len(totals))
+	for _, total := range totals {
+		balances = append(balances, *total)
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Currency < balances[j].Currency })
+	return balances
+}
@@ -0,0 +1,103 @@
+package customer
+
+import (
+	"testing"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/errors"
+)
+
+func TestCreate(t *testing.T) {
+	s := NewService()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		req     CreateCustomerRequest
+		wantErr bool
+	}{
+		{"valid", CreateCustomerRequest{Name: "Asha Rao", DateOfBirth: "1990-04-12", Email: "asha@example.com"}, false},
+		{"name only", CreateCustomerRequest{Name: "Asha Rao"}, false},
+		{"blank name", CreateCustomerRequest{Name: "  "}, true},
+		{"bad date", CreateCustomerRequest{Name: "Asha Rao", DateOfBirth: "12/04/1990"}, true},
+		{"born in the future", CreateCustomerRequest{Name: "Asha Rao", DateOfBirth: "2030-01-01"}, true},
+		{"bad email", CreateCustomerRequest{Name: "Asha Rao", Email: "asha.example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := s.Create(tt.req, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (c.ID == "" || c.Name != "Asha Rao") {
+				t.Errorf("Create() = %+v", c)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s := NewService()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := s.Create(CreateCustomerRequest{Name: "Asha Rao", Email: "asha@example.com"}, now)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	phone := "+91 98450 00000"
+	if err := s.Update(c, UpdateCustomerRequest{Phone: &phone}, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if c.Phone != phone || c.Email != "asha@example.com" || !c.UpdatedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Update() = %+v, want phone set and email kept", c)
+	}
+
+	blank := ""
+	if _, ok := s.Update(c, UpdateCustomerRequest{Name: &blank}, now).(*errors.ErrInvalidCustomerName); !ok {
+		t.Error("Update() expected *errors.ErrInvalidCustomerName for a blank name")
+	}
+	if c.Name != "Asha Rao" {
+		t.Errorf("failed update changed name to %q", c.Name)
+	}
+}
+
+func TestBalances(t *testing.T) {
+	accounts := []*account.Account{
+		{ID: "a", Balance: 1000, HeldAmount: 200, Currency: "INR"},
+		{ID: "b", Balance: 500, OverdraftLimit: 100},
+		{ID: "c", Balance: 2500, Currency: "USD"},
+		{ID: "d", Balance: 0, Currency: "EUR", Status: account.StatusClosed},
+	}
+
+	got := NewService().Balances(accounts)
+	want := []Balance{
+		{Currency: "INR", Balance: 1500, AvailableBalance: 1400, Accounts: 2},
+		{Currency: "USD", Balance: 2500, AvailableBalance: 2500, Accounts: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Balances() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Balances()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCanDelete(t *testing.T) {
+	s := NewService()
+	c := &Customer{ID: "cust"}
+
+	closed := []*account.Account{{ID: "a", Status: account.StatusClosed}}
+	if err := s.CanDelete(c, closed); err != nil {
+		t.Errorf("CanDelete() with only closed accounts = %v", err)
+	}
+
+	open := append(closed, &account.Account{ID: "b", Status: account.StatusFrozen})
+	err, ok := s.CanDelete(c, open).(*errors.ErrCustomerHasAccounts)
+	if !ok || err.Accounts != 1 {
+		t.Errorf("CanDelete() = %v, want one open account", err)
+	}
+}
@@ -0,0 +1,169 @@
+package fee
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/money"
+)
+
+// Tier prices amounts up to and including UpTo. A zero UpTo has no upper
+// bound.
+type Tier struct {
+	UpTo int64  `json:"up_to,omitempty"`
+	Flat int64  `json:"flat,omitempty"`
+	Rate string `json:"rate,omitempty"`
+}
+
+// Rule prices one operation. The fee is Flat plus Rate (a fraction, such as
+// "0.005" for 0.5%) of the amount, or that of the first tier the amount
+// falls in when Tiers are given, then kept between Min and Max. A zero Max
+// means no cap. Amounts are in the account currency's minor unit.
+type Rule struct {
+	Operation account.Operation `json:"operation"`
+
+	// An empty AccountType or Currency matches any account.
+	AccountType account.Type `json:"account_type,omitempty"`
+	Currency    string       `json:"currency,omitempty"`
+
+	Flat  int64  `json:"flat,omitempty"`
+	Rate  string `json:"rate,omitempty"`
+	Tiers []Tier `json:"tiers,omitempty"`
+	Min   int64  `json:"min,omitempty"`
+	Max   int64  `json:"max,omitempty"`
+}
+
+func (r Rule) Validate() error {
+	switch r.Operation {
+	case account.OperationWithdrawal, account.OperationTransfer:
+	default:
+		return fmt.Errorf("invalid fee operation %q", r.Operation)
+	}
+	if r.AccountType != "" && !account.ValidType(r.AccountType) {
+		return fmt.Errorf("invalid account type %q", r.AccountType)
+	}
+	if r.Currency != "" {
+		if err := money.ValidateCurrency(r.Currency); err != nil {
+			return err
+		}
+	}
+	if r.Flat < 0 || r.Min < 0 || r.Max < 0 || (r.Max > 0 && r.Min > r.Max) {
+		return fmt.Errorf("invalid %s fee amounts", r.Operation)
+	}
+	if err := validateRate(r.Rate); err != nil {
+		return err
+	}
+
+	for i, tier := range r.Tiers {
+		if tier.Flat < 0 || tier.UpTo < 0 {
+			return fmt.Errorf("invalid %s fee tier %d", r.Operation, i)
+		}
+		if i > 0 && (r.Tiers[i-1].UpTo == 0 || tier.UpTo != 0 && tier.UpTo <= r.Tiers[i-1].UpTo) {
+			return fmt.Errorf("%s fee tiers must be in ascending order", r.Operation)
+		}
+		if err := validateRate(tier.Rate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r Rule) matches(acc *account.Account, op account.Operation) bool {
+	return r.Operation == op &&
+		(r.AccountType == "" || r.AccountType == acc.CurrentType()) &&
+		(r.Currency == "" || r.Currency == acc.CurrentCurrency())
+}
+
+// specificity ranks rules that match the same account: the more of the
+// account's attributes a rule names, the more specific it is.
+func (r Rule) specificity() int {
+	n := 0
+	if r.AccountType != "" {
+		n++
+	}
+	if r.Currency != "" {
+		n++
+	}
+	return n
+}
+
+// Fee prices amount under the rule.
+func (r Rule) Fee(amount money.Money) int64 {
+	flat, rate := r.Flat, r.Rate
+	for _, tier := range r.Tiers {
+		if tier.UpTo == 0 || amount.Amount <= tier.UpTo {
+			flat, rate = tier.Flat, tier.Rate
+			break
+		}
+	}
+
+	fee := flat
+	if rate != "" {
+		factor, _ := money.ParseRate(rate)
+		fee += amount.Mul(factor).Amount
+	}
+
+	if fee < r.Min {
+		fee = r.Min
+	}
+	if r.Max > 0 && fee > r.Max {
+		fee = r.Max
+	}
+	return fee
+}
+
+// Schedule is the bank's list of fee rules. For each withdrawal or transfer
+// the most specific matching rule applies, the earliest on a tie; with no
+// match the operation is free.
+type Schedule []Rule
+
+func (s Schedule) Fee(acc *account.Account, op account.Operation, amount int64) int64 {
+	var best *Rule
+	for i, rule := range s {
+		if rule.matches(acc, op) && (best == nil || rule.specificity() > best.specificity()) {
+			best = &s[i]
+		}
+	}
+	if best == nil {
+		return 0
+	}
+	return best.Fee(money.Money{Amount: amount, Currency: acc.CurrentCurrency()})
+}
+
+// ParseSchedule decodes a JSON list of rules, e.g.
+// [{"operation": "transfer", "rate": "0.001", "min": 500, "max": 5000}].
+func ParseSchedule(data []byte) (Schedule, error) {
+	var schedule Schedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("decode fee schedule: %w", err)
+	}
+
+	for _, rule := range schedule {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return schedule, nil
+}
+
+func LoadSchedule(path string) (Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fee schedule: %w", err)
+	}
+	return ParseSchedule(data)
+}
+
+func validateRate(rate string) error {
+	if rate == "" {
+		return nil
+	}
+	if _, err := money.ParseRate(rate); err != nil {
+		return fmt.Errorf("invalid fee rate %q", rate)
+	}
+	return nil
+}
+
+var _ account.FeePolicy = Schedule(nil)
@@ -0,0 +1,105 @@
+package fee
+
+import (
+	"testing"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/money"
+)
+
+func TestRuleFee(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   Rule
+		amount int64
+		want   int64
+	}{
+		{"flat", Rule{Flat: 500}, 100000, 500},
+		{"percentage", Rule{Rate: "0.005"}, 123400, 617},
+		{"percentage rounds half to even", Rule{Rate: "0.005"}, 100100, 500},
+		{"flat plus percentage", Rule{Flat: 100, Rate: "0.01"}, 10000, 200},
+		{"minimum", Rule{Rate: "0.001", Min: 500}, 10000, 500},
+		{"maximum", Rule{Rate: "0.01", Max: 2500}, 1000000, 2500},
+		{
+			name:   "first tier",
+			rule:   Rule{Tiers: []Tier{{UpTo: 100000, Flat: 0}, {UpTo: 1000000, Flat: 250}, {Rate: "0.001"}}},
+			amount: 100000,
+			want:   0,
+		},
+		{
+			name:   "middle tier",
+			rule:   Rule{Tiers: []Tier{{UpTo: 100000, Flat: 0}, {UpTo: 1000000, Flat: 250}, {Rate: "0.001"}}},
+			amount: 100001,
+			want:   250,
+		},
+		{
+			name:   "open-ended tier with cap",
+			rule:   Rule{Tiers: []Tier{{UpTo: 100000, Flat: 0}, {Rate: "0.001"}}, Max: 1000},
+			amount: 50000000,
+			want:   1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Fee(money.Money{Amount: tt.amount, Currency: "INR"}); got != tt.want {
+				t.Errorf("Fee(%d) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleFee(t *testing.T) {
+	schedule := Schedule{
+		{Operation: account.OperationTransfer, Flat: 1000},
+		{Operation: account.OperationTransfer, AccountType: account.TypeSavings, Flat: 500},
+		{Operation: account.OperationTransfer, AccountType: account.TypeSavings, Currency: "USD", Flat: 50},
+		{Operation: account.OperationWithdrawal, AccountType: account.TypeCurrent, Rate: "0.01"},
+	}
+
+	tests := []struct {
+		name    string
+		account *account.Account
+		op      account.Operation
+		want    int64
+	}{
+		{"any account", &account.Account{Type: account.TypeCurrent}, account.OperationTransfer, 1000},
+		{"account type", &account.Account{Type: account.TypeSavings}, account.OperationTransfer, 500},
+		{"account type and currency", &account.Account{Type: account.TypeSavings, Currency: "USD"}, account.OperationTransfer, 50},
+		{"untyped account is current", &account.Account{}, account.OperationWithdrawal, 100},
+		{"no matching rule", &account.Account{Type: account.TypeSavings}, account.OperationWithdrawal, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.Fee(tt.account, tt.op, 10000); got != tt.want {
+				t.Errorf("Fee() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	schedule, err := ParseSchedule([]byte(`[{"operation": "transfer", "account_type": "savings", "rate": "0.001", "min": 500, "max": 5000}]`))
+	if err != nil {
+		t.Fatalf("ParseSchedule() error = %v", err)
+	}
+	if len(schedule) != 1 || schedule[0].Max != 5000 {
+		t.Errorf("ParseSchedule() = %+v", schedule)
+	}
+
+	invalid := []string{
+		`[{"operation": "deposit", "flat": 100}]`,
+		`[{"operation": "transfer", "account_type": "checking"}]`,
+		`[{"operation": "transfer", "currency": "XYZ"}]`,
+		`[{"operation": "transfer", "rate": "1/2"}]`,
+		`[{"operation": "transfer", "min": 500, "max": 100}]`,
+		`[{"operation": "transfer", "tiers": [{"up_to": 1000}, {"up_to": 500}]}]`,
+		`[{"operation": "transfer", "tiers": [{"flat": 10}, {"up_to": 500}]}]`,
+	}
+	for _, raw := range invalid {
+		if _, err := ParseSchedule([]byte(raw)); err == nil {
+			t.Errorf("ParseSchedule(%s) expected error", raw)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"banking-service/pkg/errors"
+	"banking-service/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// inverseRatePrecision is the number of decimal places kept when a rate is
+// derived by inverting the configured opposite pair.
+const inverseRatePrecision = 10
+
+// RateProvider supplies the current exchange rate between two currencies as a
+// decimal string of target units per source unit.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (string, error)
+}
+
+// StaticProvider serves rates from a fixed table, loaded from config or a
+// file. Pairs are keyed "FROM/TO"; the inverse of a configured pair is
+// derived when only one direction is given.
+type StaticProvider struct {
+	rates map[string]string
+}
+
+func NewStaticProvider(rates map[string]string) (*StaticProvider, error) {
+	p := &StaticProvider{rates: make(map[string]string, len(rates))}
+	for pair, rate := range rates {
+		from, to, ok := strings.Cut(pair, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid currency pair %q: want FROM/TO", pair)
+		}
+		if err := money.ValidateCurrency(from); err != nil {
+			return nil, err
+		}
+		if err := money.ValidateCurrency(to); err != nil {
+			return nil, err
+		}
+		if _, err := money.ParseRate(rate); err != nil {
+			return nil, err
+		}
+
+		p.rates[from+"/"+to] = strings.TrimSpace(rate)
+	}
+	return p, nil
+}
+
+// LoadFileProvider reads a JSON object of pair to rate, e.g.
+// {"USD/INR": "83.125"}.
+func LoadFileProvider(path string) (*StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fx rates: %w", err)
+	}
+
+	var rates map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("decode fx rates: %w", err)
+	}
+	return NewStaticProvider(rates)
+}
+
+// ParseRates parses the FX_RATES config format: comma-separated
+// FROM/TO=rate pairs.
+func ParseRates(raw string) (map[string]string, error) {
+	rates := make(map[string]string)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		pair, rate, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid fx rate %q: want FROM/TO=rate", item)
+		}
+		rates[strings.TrimSpace(pair)] = strings.TrimSpace(rate)
+	}
+	return rates, nil
+}
+
+func (p *StaticProvider) Rate(ctx context.Context, from, to string) (string, error) {
+	if from == to {
+		return "1", nil
+	}
+
+	if rate, ok := p.rates[from+"/"+to]; ok {
+		return rate, nil
+	}
+
+	if rate, ok := p.rates[to+"/"+from]; ok {
+		r, err := money.ParseRate(rate)
+		if err != nil {
+			return "", err
+		}
+		return new(big.Rat).Inv(r).FloatString(inverseRatePrecision), nil
+	}
+
+	return "", &errors.ErrRateUnavailable{From: from, To: to}
+}
+
+// Quote locks a rate for converting a specific amount until ExpiresAt. A
+// quote can back a single transfer; ConsumedBy records which.
+type Quote struct {
+	ID           string    `json:"id"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	Rate         string    `json:"rate"`
+	SourceAmount int64     `json:"source_amount"`
+	TargetAmount int64     `json:"target_amount"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	ConsumedBy   string    `json:"consumed_by,omitempty"`
+}
+
+func (q *Quote) Expired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
+
+func (q *Quote) Conversion() money.Conversion {
+	return money.Conversion{
+		Source: money.Money{Amount: q.SourceAmount, Currency: q.FromCurrency},
+		Target: money.Money{Amount: q.TargetAmount, Currency: q.ToCurrency},
+		Rate:   q.Rate,
+	}
+}
+
+type QuoteRequest struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	Amount       int64  `json:"amount"`
+}
+
+type Service struct {
+	provider RateProvider
+	ttl      time.Duration
+}
+
+func NewService(provider RateProvider, ttl time.Duration) *Service {
+	return &Service{
+		provider: provider,
+		ttl:      ttl,
+	}
+}
+
+func (s *Service) Quote(ctx context.Context, req QuoteRequest, now time.Time) (*Quote, error) {
+	if req.Amount <= 0 {
+		return nil, &errors.ErrInvalidAmount{Amount: req.Amount}
+	}
+
+	source, err := money.New(req.Amount, req.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if err := money.ValidateCurrency(req.ToCurrency); err != nil {
+		return nil, err
+	}
+
+	rate, err := s.provider.Rate(ctx, req.FromCurrency, req.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := money.Convert(source, req.ToCurrency, rate)
+	if err != nil {
+		return nil, err
+	}
+	if target.Amount <= 0 {
+		return nil, &errors.ErrInvalidConversion{Reason: fmt.Sprintf("%s converts to nothing in %s", source, req.ToCurrency)}
+	}
+
+	return &Quote{
+		ID:           uuid.New().String(),
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+		Rate:         rate,
+		SourceAmount: source.Amount,
+		TargetAmount: target.Amount,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.ttl),
+	}, nil
+}
+
+// Convert values m in another currency at the provider's current rate. It is
+// indicative only; money is moved at quoted or supplied rates.
+func (s *Service) Convert(ctx context.Context, m money.Money, to string) (money.Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+
+	rate, err := s.provider.Rate(ctx, m.Currency, to)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return money.Convert(m, to, rate)
+}
@@ -0,0 +1,71 @@
+package fx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/pkg/errors"
+)
+
+func TestStaticProviderRate(t *testing.T) {
+	p, err := NewStaticProvider(map[string]string{"USD/INR": "80"})
+	if err != nil {
+		t.Fatalf("NewStaticProvider() error = %v", err)
+	}
+
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"USD", "INR", "80"},
+		{"INR", "USD", "0.0125000000"},
+		{"EUR", "EUR", "1"},
+	}
+
+	for _, tt := range tests {
+		got, err := p.Rate(context.Background(), tt.from, tt.to)
+		if err != nil || got != tt.want {
+			t.Errorf("Rate(%s, %s) = %v, %v, want %v", tt.from, tt.to, got, err, tt.want)
+		}
+	}
+
+	if _, err := p.Rate(context.Background(), "EUR", "GBP"); err == nil {
+		t.Error("Rate(EUR, GBP) expected error")
+	} else if _, ok := err.(*errors.ErrRateUnavailable); !ok {
+		t.Errorf("Rate(EUR, GBP) error = %T, want *errors.ErrRateUnavailable", err)
+	}
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates("USD/INR=83.1, EUR/USD=1.08,")
+	if err != nil {
+		t.Fatalf("ParseRates() error = %v", err)
+	}
+	if len(rates) != 2 || rates["USD/INR"] != "83.1" || rates["EUR/USD"] != "1.08" {
+		t.Errorf("ParseRates() = %v", rates)
+	}
+
+	if _, err := ParseRates("USD/INR"); err == nil {
+		t.Error("ParseRates() expected error for missing rate")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	p, _ := NewStaticProvider(map[string]string{"USD/INR": "83.125"})
+	service := NewService(p, 30*time.Second)
+	now := time.Now()
+
+	quote, err := service.Quote(context.Background(), QuoteRequest{FromCurrency: "USD", ToCurrency: "INR", Amount: 1001}, now)
+	if err != nil {
+		t.Fatalf("Quote() error = %v", err)
+	}
+
+	// 10.01 USD * 83.125 = 832.08125 INR, rounded half-even to 832.08.
+	if quote.TargetAmount != 83208 || quote.Rate != "83.125" {
+		t.Errorf("Quote() = %d at %s, want 83208 at 83.125", quote.TargetAmount, quote.Rate)
+	}
+	if quote.Expired(now.Add(29*time.Second)) || !quote.Expired(now.Add(30*time.Second)) {
+		t.Errorf("Quote() expires at %v, want %v", quote.ExpiresAt, now.Add(30*time.Second))
+	}
+}
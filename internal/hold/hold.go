@@ -0,0 +1,140 @@
+package hold
+
+import (
+	"time"
+
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusCaptured Status = "captured"
+	StatusReleased Status = "released"
+	StatusExpired  Status = "expired"
+)
+
+// Hold reserves Amount of an account's balance until it is captured into a
+// withdrawal, released, or expires. CapturedAmount and TransactionID are set
+// on capture; any uncaptured remainder is released at the same time.
+type Hold struct {
+	ID             string    `json:"id"`
+	AccountID      string    `json:"account_id"`
+	Amount         int64     `json:"amount"`
+	Currency       string    `json:"currency"`
+	Status         Status    `json:"status"`
+	CapturedAmount int64     `json:"captured_amount,omitempty"`
+	TransactionID  string    `json:"transaction_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// Expired reports whether an active hold has outlived its expiry.
+func (h *Hold) Expired(now time.Time) bool {
+	return h.Status == StatusActive && !now.Before(h.ExpiresAt)
+}
+
+type PlaceHoldRequest struct {
+	AccountID string     `json:"account_id"`
+	Amount    int64      `json:"amount"`
+	Currency  string     `json:"currency,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CaptureRequest settles a hold. A zero Amount captures the full hold.
+type CaptureRequest struct {
+	Amount int64 `json:"amount,omitempty"`
+}
+
+type Service struct {
+	ttl time.Duration
+}
+
+func NewService(ttl time.Duration) *Service {
+	return &Service{ttl: ttl}
+}
+
+// Place builds a new active hold. The caller is responsible for reserving the
+// amount on the account.
+func (s *Service) Place(req PlaceHoldRequest, currency string, now time.Time) (*Hold, error) {
+	if req.Amount <= 0 {
+		return nil, &errors.ErrInvalidAmount{Amount: req.Amount}
+	}
+
+	expiresAt := now.Add(s.ttl)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, &errors.ErrInvalidParameter{Name: "expires_at", Value: req.ExpiresAt.Format(time.RFC3339)}
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	return &Hold{
+		ID:        uuid.New().String(),
+		AccountID: req.AccountID,
+		Amount:    req.Amount,
+		Currency:  currency,
+		Status:    StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Capture marks the hold captured for amount, or the full hold if amount is
+// zero, and returns the amount to settle.
+func (s *Service) Capture(h *Hold, amount int64, now time.Time) (int64, error) {
+	if err := s.checkActive(h, now); err != nil {
+		return 0, err
+	}
+
+	if amount == 0 {
+		amount = h.Amount
+	}
+	if amount < 0 {
+		return 0, &errors.ErrInvalidAmount{Amount: amount}
+	}
+	if amount > h.Amount {
+		return 0, &errors.ErrCaptureExceedsHold{HoldID: h.ID, Held: h.Amount, Amount: amount}
+	}
+
+	h.Status = StatusCaptured
+	h.CapturedAmount = amount
+	h.UpdatedAt = now
+	return amount, nil
+}
+
+func (s *Service) Release(h *Hold, now time.Time) error {
+	if err := s.checkActive(h, now); err != nil {
+		return err
+	}
+
+	h.Status = StatusReleased
+	h.UpdatedAt = now
+	return nil
+}
+
+// Expire marks an active hold past its expiry as expired.
+func (s *Service) Expire(h *Hold, now time.Time) error {
+	if !h.Expired(now) {
+		return &errors.ErrHoldNotActive{HoldID: h.ID, Status: string(h.Status)}
+	}
+
+	h.Status = StatusExpired
+	h.UpdatedAt = now
+	return nil
+}
+
+func (s *Service) checkActive(h *Hold, now time.Time) error {
+	if h.Status != StatusActive {
+		return &errors.ErrHoldNotActive{HoldID: h.ID, Status: string(h.Status)}
+	}
+	if h.Expired(now) {
+		return &errors.ErrHoldExpired{HoldID: h.ID}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package hold
+
+import (
+	"testing"
+	"time"
+
+	"banking-service/pkg/errors"
+)
+
+func TestPlace(t *testing.T) {
+	service := NewService(time.Hour)
+	now := time.Now()
+
+	h, err := service.Place(PlaceHoldRequest{AccountID: "acc-1", Amount: 500}, "INR", now)
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	if h.Status != StatusActive || !h.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Place() = %v expiring %v, want active expiring %v", h.Status, h.ExpiresAt, now.Add(time.Hour))
+	}
+
+	if _, err := service.Place(PlaceHoldRequest{AccountID: "acc-1", Amount: 0}, "INR", now); err == nil {
+		t.Error("Place() expected error for zero amount")
+	}
+
+	past := now.Add(-time.Minute)
+	_, err = service.Place(PlaceHoldRequest{AccountID: "acc-1", Amount: 500, ExpiresAt: &past}, "INR", now)
+	if _, ok := err.(*errors.ErrInvalidParameter); !ok {
+		t.Errorf("Place() error = %v, want *errors.ErrInvalidParameter", err)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	service := NewService(time.Hour)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		amount  int64
+		want    int64
+		errType interface{}
+	}{
+		{name: "full", amount: 0, want: 500},
+		{name: "partial", amount: 200, want: 200},
+		{name: "exceeds_hold", amount: 600, errType: &errors.ErrCaptureExceedsHold{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := service.Place(PlaceHoldRequest{AccountID: "acc-1", Amount: 500}, "INR", now)
+
+			got, err := service.Capture(h, tt.amount, now)
+			if tt.errType != nil {
+				if _, ok := err.(*errors.ErrCaptureExceedsHold); !ok {
+					t.Errorf("Capture() error = %v, want %T", err, tt.errType)
+				}
+				return
+			}
+			if err != nil || got != tt.want || h.Status != StatusCaptured {
+				t.Errorf("Capture() = %v, %v (status %v), want %v", got, err, h.Status, tt.want)
+			}
+
+			if _, ok := service.Release(h, now).(*errors.ErrHoldNotActive); !ok {
+				t.Error("Release() expected *errors.ErrHoldNotActive after capture")
+			}
+		})
+	}
+}
+
+func TestExpire(t *testing.T) {
+	service := NewService(time.Hour)
+	now := time.Now()
+
+	h, _ := service.Place(PlaceHoldRequest{AccountID: "acc-1", Amount: 500}, "INR", now)
+	if _, err := service.Capture(h, 0, now.Add(time.Hour)); err == nil {
+		t.Error("Capture() expected error for expired hold")
+	}
+	if err := service.Expire(h, now.Add(time.Hour)); err != nil || h.Status != StatusExpired {
+		t.Errorf("Expire() = %v (status %v), want nil (expired)", err, h.Status)
+	}
+}
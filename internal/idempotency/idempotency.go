@@ -0,0 +1,72 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+const HeaderKey = "Idempotency-Key"
+
+// Record remembers the outcome of the first request made with an
+// Idempotency-Key. A record with a zero StatusCode is still in flight.
+type Record struct {
+	Key         string `json:"key"`
+	Fingerprint string `json:"fingerprint"`
+	// Committed is set in the same commit as the request's changes, so a
+	// record left in flight by a crash shows whether they were made.
+	Committed  bool            `json:"committed,omitempty"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Response   json.RawMessage `json:"response,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+}
+
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Expired reports whether the key may be reused at now. A key whose request
+// made changes but never stored its response is kept, since a retry under it
+// would make them again.
+func (r *Record) Expired(now time.Time) bool {
+	if r.Committed && !r.Completed() {
+		return false
+	}
+	return !now.Before(r.ExpiresAt)
+}
+
+type contextKey struct{}
+
+// WithKey returns a context carrying the Idempotency-Key of the request being
+// handled.
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// KeyFrom returns the Idempotency-Key carried by ctx, or "".
+func KeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(contextKey{}).(string)
+	return key
+}
+
+// Fingerprint identifies a request by method, path and body. JSON bodies are
+// compacted first so that insignificant whitespace does not count as a
+// different request.
+func Fingerprint(method, path string, body []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, body); err == nil {
+		body = compact.Bytes()
+	}
+
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,174 @@
+package interest
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/ledger"
+	"banking-service/internal/payment"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+)
+
+// accruedPrecision is the number of decimal places of a minor unit kept in
+// an account's accrued interest between runs.
+const accruedPrecision = 6
+
+// Accruer accrues interest on each account's end-of-day balance and pays out
+// what has accrued at the end of every month. Days are UTC calendar days.
+type Accruer struct {
+	store              store.Repository
+	accountService     *account.Service
+	transactionService *transaction.Service
+	payments           *payment.Processor
+	table              Table
+}
+
+func NewAccruer(store store.Repository, accountService *account.Service, transactionService *transaction.Service, payments *payment.Processor, table Table) *Accruer {
+	return &Accruer{
+		store:              store,
+		accountService:     accountService,
+		transactionService: transactionService,
+		payments:           payments,
+		table:              table,
+	}
+}
+
+// change is a movement in an account's balance at a point in time.
+type change struct {
+	at    time.Time
+	delta int64
+}
+
+// Run accrues interest for every day that ended before now, catching up on
+// days missed since the last run, and returns how many accounts it updated.
+// Accounts that change while they are being worked on are left for the next
+// run.
+func (a *Accruer) Run(ctx context.Context, now time.Time) (int, error) {
+	if len(a.table) == 0 {
+		return 0, nil
+	}
+
+	accounts, entries := a.store.LedgerState(ctx)
+	history := balanceHistory(entries)
+	through := day(now).AddDate(0, 0, -1)
+
+	updated := 0
+	for _, candidate := range accounts {
+		product, ok := a.table[candidate.CurrentType()]
+		if !ok || candidate.CurrentStatus() == account.StatusClosed || nextAccrualDay(candidate).After(through) {
+			continue
+		}
+
+		accrued := false
+		err := a.store.Apply(ctx, func(uow store.UnitOfWork) error {
+			acc, err := uow.GetAccount(candidate.ID)
+			if err != nil {
+				return err
+			}
+			if acc.Version != candidate.Version {
+				return nil
+			}
+
+			accrued = true
+			return a.accrue(uow, acc, product, history[acc.ID], through)
+		})
+		if err != nil {
+			return updated, err
+		}
+		if accrued {
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// accrue brings acc's accrued interest up to date through the given day.
+// changes must hold every balance change the account has had, oldest first.
+func (a *Accruer) accrue(uow store.UnitOfWork, acc *account.Account, product Product, changes []change, through time.Time) error {
+	accrued, ok := new(big.Rat).SetString(acc.AccruedInterest)
+	if !ok {
+		accrued = new(big.Rat)
+	}
+
+	// The balance at the end of a day is the current balance less every
+	// change made after that day ended. Interest paid during this run is
+	// already in the balance and dated before the days that follow it.
+	later := int64(0)
+	for _, c := range changes {
+		later += c.delta
+	}
+	next := 0
+
+	for d := nextAccrualDay(acc); !d.After(through); d = d.AddDate(0, 0, 1) {
+		endOfDay := d.AddDate(0, 0, 1)
+		for next < len(changes) && changes[next].at.Before(endOfDay) {
+			later -= changes[next].delta
+			next++
+		}
+		balance := acc.Balance - later
+
+		if rate := product.RateFor(balance); rate != nil {
+			daily := new(big.Rat).SetInt64(balance)
+			if product.Method == MethodCompound {
+				daily.Add(daily, accrued)
+			}
+			daily.Mul(daily, rate)
+			daily.Quo(daily, big.NewRat(product.DayCount.DaysInYear(d), 1))
+			accrued.Add(accrued, daily)
+		}
+		acc.InterestAccruedThrough = d
+
+		if endOfDay.Day() != 1 {
+			continue
+		}
+
+		// Month end: pay out whole minor units and carry the fraction.
+		amount := new(big.Int).Quo(accrued.Num(), accrued.Denom()).Int64()
+		if amount <= 0 {
+			continue
+		}
+		if err := a.accountService.PayInterest(acc, amount); err != nil {
+			return err
+		}
+		tx := a.transactionService.CreateInterestTransaction(acc.ID, amount, acc.CurrentCurrency(), endOfDay)
+		if err := a.payments.Record(uow, tx); err != nil {
+			return err
+		}
+		accrued.Sub(accrued, new(big.Rat).SetInt64(amount))
+	}
+
+	acc.AccruedInterest = accrued.FloatString(accruedPrecision)
+	return nil
+}
+
+// balanceHistory groups the balance changes in entries by customer account,
+// oldest first.
+func balanceHistory(entries []*ledger.JournalEntry) map[string][]change {
+	history := make(map[string][]change)
+	for _, e := range entries {
+		for accountID, delta := range ledger.BalanceDeltas([]*ledger.JournalEntry{e}) {
+			history[accountID] = append(history[accountID], change{at: e.Timestamp, delta: delta})
+		}
+	}
+	for _, changes := range history {
+		sort.SliceStable(changes, func(i, j int) bool {
+			return changes[i].at.Before(changes[j].at)
+		})
+	}
+	return history
+}
+
+func nextAccrualDay(acc *account.Account) time.Time {
+	if acc.InterestAccruedThrough.IsZero() {
+		return day(acc.CreatedAt)
+	}
+	return day(acc.InterestAccruedThrough).AddDate(0, 0, 1)
+}
+
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,208 @@
+package interest
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/ledger"
+	"banking-service/internal/payment"
+	"banking-service/internal/scheduler"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+)
+
+type fixedClock struct{ now time.Time }
+
+func (c *fixedClock) Now() time.Time { return c.now }
+
+func newTestAccruer(repo store.Repository, table Table) *Accruer {
+	processor := payment.NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, nil)
+	return NewAccruer(repo, account.NewService(), transaction.NewService(), processor, table)
+}
+
+func openAccount(t *testing.T, repo *store.MemoryStore, id string, accountType account.Type, balance int64, createdAt time.Time) {
+	t.Helper()
+
+	acc := &account.Account{ID: id, CustomerName: id, Type: accountType, Balance: balance, Status: account.StatusActive, CreatedAt: createdAt}
+	err := repo.Apply(context.Background(), func(uow store.UnitOfWork) error {
+		if err := uow.CreateAccount(acc); err != nil {
+			return err
+		}
+		return uow.PostEntry(ledger.NewService().OpeningEntry(acc))
+	})
+	if err != nil {
+		t.Fatalf("open account %s: %v", id, err)
+	}
+}
+
+func TestAccruerRun(t *testing.T) {
+	ctx := context.Background()
+	repo := store.NewMemoryStore()
+	processor := payment.NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, nil)
+
+	openAccount(t, repo, "savings", account.TypeSavings, 1000000, time.Date(2024, 1, 30, 10, 0, 0, 0, time.UTC))
+	openAccount(t, repo, "current", account.TypeCurrent, 1000000, time.Date(2024, 1, 30, 10, 0, 0, 0, time.UTC))
+
+	// Deposit during Jan 31, so that day ends with twice the balance.
+	err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount("savings")
+		if err != nil {
+			return err
+		}
+		if err := account.NewService().Deposit(acc, 1000000); err != nil {
+			return err
+		}
+		tx := transaction.NewService().CreateDepositTransaction(acc.ID, 1000000, acc.CurrentCurrency())
+		tx.Timestamp = time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+		return processor.Record(uow, tx)
+	})
+	if err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+
+	accruer := newTestAccruer(repo, Table{
+		account.TypeSavings: {Method: MethodSimple, DayCount: DayCountACT365, Tiers: []Tier{{MinBalance: 0, Rate: "0.0365"}}},
+	})
+
+	clock := &fixedClock{now: time.Date(2024, 2, 2, 3, 0, 0, 0, time.UTC)}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	jobs := scheduler.New(clock, logger)
+	updated := 0
+	jobs.Every("accrue-interest", time.Hour, func(ctx context.Context, now time.Time) error {
+		n, err := accruer.Run(ctx, now)
+		updated += n
+		return err
+	})
+	jobs.RunOnce(ctx)
+
+	if updated != 1 {
+		t.Fatalf("Run() updated %d accounts, want 1", updated)
+	}
+
+	// Jan 30 earns 100 and Jan 31 200, paid at month end; Feb 1 earns 200.03
+	// on the new balance.
+	acc, _ := repo.GetAccount(ctx, "savings")
+	if acc.Balance != 2000300 {
+		t.Errorf("balance = %v, want 2000300", acc.Balance)
+	}
+	if acc.AccruedInterest != "200.030000" {
+		t.Errorf("AccruedInterest = %v, want 200.030000", acc.AccruedInterest)
+	}
+	if want := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC); !acc.InterestAccruedThrough.Equal(want) {
+		t.Errorf("InterestAccruedThrough = %v, want %v", acc.InterestAccruedThrough, want)
+	}
+
+	var paid []*transaction.Transaction
+	for _, tx := range repo.GetAllTransactions(ctx) {
+		if tx.Type == transaction.TransactionTypeInterest {
+			paid = append(paid, tx)
+		}
+	}
+	if len(paid) != 1 || paid[0].Amount != 300 || !paid[0].Timestamp.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("interest transactions = %+v, want one of 300 dated 2024-02-01", paid)
+	}
+
+	updated = 0
+	clock.now = clock.now.Add(time.Hour)
+	jobs.RunOnce(ctx)
+	if updated != 0 {
+		t.Errorf("Run() again the same day updated %d accounts, want 0", updated)
+	}
+
+	if err := ledger.NewService().Verify(repo.LedgerState(ctx)); err != nil {
+		t.Errorf("Verify() after interest error = %v", err)
+	}
+}
+
+func TestCompounding(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		method Method
+		want   string
+	}{
+		{MethodSimple, "300.000000"},
+		{MethodCompound, "300.030001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			repo := store.NewMemoryStore()
+			openAccount(t, repo, "fd", account.TypeFixedDeposit, 1000000, created)
+
+			accruer := newTestAccruer(repo, Table{
+				account.TypeFixedDeposit: {Method: tt.method, DayCount: DayCountACT365, Tiers: []Tier{{Rate: "0.0365"}}},
+			})
+			if _, err := accruer.Run(ctx, now); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			acc, _ := repo.GetAccount(ctx, "fd")
+			if acc.AccruedInterest != tt.want {
+				t.Errorf("AccruedInterest = %v, want %v", acc.AccruedInterest, tt.want)
+			}
+		})
+	}
+}
+
+func TestDaysInYear(t *testing.T) {
+	leap := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	common := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := DayCountACT365.DaysInYear(leap); got != 365 {
+		t.Errorf("ACT/365 = %v, want 365", got)
+	}
+	if got := DayCountACT360.DaysInYear(leap); got != 360 {
+		t.Errorf("ACT/360 = %v, want 360", got)
+	}
+	if got := DayCountACTACT.DaysInYear(leap); got != 366 {
+		t.Errorf("ACT/ACT in 2024 = %v, want 366", got)
+	}
+	if got := DayCountACTACT.DaysInYear(common); got != 365 {
+		t.Errorf("ACT/ACT in 2023 = %v, want 365", got)
+	}
+}
+
+func TestRateFor(t *testing.T) {
+	product := Product{Tiers: []Tier{{MinBalance: 0, Rate: "0.03"}, {MinBalance: 100000, Rate: "0.04"}}}
+
+	if rate := product.RateFor(50000); rate == nil || rate.FloatString(2) != "0.03" {
+		t.Errorf("RateFor(50000) = %v, want 0.03", rate)
+	}
+	if rate := product.RateFor(100000); rate == nil || rate.FloatString(2) != "0.04" {
+		t.Errorf("RateFor(100000) = %v, want 0.04", rate)
+	}
+	if rate := product.RateFor(-10); rate != nil {
+		t.Errorf("RateFor(-10) = %v, want nil", rate)
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	table, err := ParseTable([]byte(`{"savings": {"method": "compound", "day_count": "ACT/ACT", "tiers": [{"min_balance": 0, "rate": "0.035"}]}}`))
+	if err != nil {
+		t.Fatalf("ParseTable() error = %v", err)
+	}
+	if table[account.TypeSavings].Method != MethodCompound {
+		t.Errorf("ParseTable() savings method = %v, want compound", table[account.TypeSavings].Method)
+	}
+
+	invalid := []string{
+		`{"checking": {"method": "simple", "day_count": "ACT/365"}}`,
+		`{"savings": {"method": "yearly", "day_count": "ACT/365"}}`,
+		`{"savings": {"method": "simple", "day_count": "30/360"}}`,
+		`{"savings": {"method": "simple", "day_count": "ACT/365", "tiers": [{"rate": "-1"}]}}`,
+	}
+	for _, raw := range invalid {
+		if _, err := ParseTable([]byte(raw)); err == nil {
+			t.Errorf("ParseTable(%s) expected error", raw)
+		}
+	}
+}
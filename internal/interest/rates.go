@@ -0,0 +1,131 @@
+package interest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/pkg/money"
+)
+
+type Method string
+
+const (
+	// MethodSimple accrues interest on the balance alone.
+	MethodSimple Method = "simple"
+	// MethodCompound also accrues interest on interest accrued but not yet
+	// paid, compounding daily.
+	MethodCompound Method = "compound"
+)
+
+// DayCount is the convention for turning an annual rate into a daily one.
+type DayCount string
+
+const (
+	DayCountACT365 DayCount = "ACT/365"
+	DayCountACT360 DayCount = "ACT/360"
+	DayCountACTACT DayCount = "ACT/ACT"
+)
+
+// DaysInYear returns what a day's share of the annual rate is divided by.
+func (d DayCount) DaysInYear(day time.Time) int64 {
+	switch d {
+	case DayCountACT360:
+		return 360
+	case DayCountACTACT:
+		year := day.Year()
+		if time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay() == 366 {
+			return 366
+		}
+	}
+	return 365
+}
+
+// Tier applies Rate to balances of at least MinBalance.
+type Tier struct {
+	MinBalance int64  `json:"min_balance"`
+	Rate       string `json:"rate"`
+}
+
+// Product is how one account type earns interest.
+type Product struct {
+	Method   Method   `json:"method"`
+	DayCount DayCount `json:"day_count"`
+	Tiers    []Tier   `json:"tiers"`
+}
+
+func (p Product) Validate() error {
+	switch p.Method {
+	case MethodSimple, MethodCompound:
+	default:
+		return fmt.Errorf("invalid interest method %q", p.Method)
+	}
+	switch p.DayCount {
+	case DayCountACT365, DayCountACT360, DayCountACTACT:
+	default:
+		return fmt.Errorf("invalid day count %q", p.DayCount)
+	}
+	for _, tier := range p.Tiers {
+		if _, err := money.ParseRate(tier.Rate); err != nil {
+			return fmt.Errorf("invalid interest rate %q", tier.Rate)
+		}
+	}
+	return nil
+}
+
+// RateFor returns the annual rate for balance: that of the highest tier the
+// balance reaches. It is nil when no tier applies or the balance is not in
+// credit.
+func (p Product) RateFor(balance int64) *big.Rat {
+	if balance <= 0 {
+		return nil
+	}
+
+	var best *Tier
+	for i, tier := range p.Tiers {
+		if tier.MinBalance <= balance && (best == nil || tier.MinBalance > best.MinBalance) {
+			best = &p.Tiers[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	rate, _ := money.ParseRate(best.Rate)
+	return rate
+}
+
+// Table maps account types to the products they earn interest under. Types
+// missing from the table earn nothing.
+type Table map[account.Type]Product
+
+// ParseTable decodes a JSON rate table, e.g.
+// {"savings": {"method": "compound", "day_count": "ACT/365",
+// "tiers": [{"min_balance": 0, "rate": "0.035"}]}}.
+func ParseTable(data []byte) (Table, error) {
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("decode interest rates: %w", err)
+	}
+
+	for accountType, product := range table {
+		if !account.ValidType(accountType) {
+			return nil, fmt.Errorf("invalid account type %q", accountType)
+		}
+		if err := product.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", accountType, err)
+		}
+	}
+	return table, nil
+}
+
+func LoadTable(path string) (Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read interest rates: %w", err)
+	}
+	return ParseTable(data)
+}
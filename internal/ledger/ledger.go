@@ -0,0 +1,215 @@
+package ledger
+
+import (
+	"strings"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+const systemAccountPrefix = "system:"
+
+// CashAccount is the bank's own clearing account. Money entering the bank is
+// debited to it and money leaving is credited to it.
+const CashAccount = systemAccountPrefix + "cash"
+
+// FeeAccount collects the fees and charges the bank takes from customers.
+const FeeAccount = systemAccountPrefix + "fees"
+
+// InterestAccount pays the interest the bank owes on customer balances.
+const InterestAccount = systemAccountPrefix + "interest"
+
+// FXAccount holds the bank's currency position from cross-currency
+// transfers. It balances each currency leg of a conversion separately.
+const FXAccount = systemAccountPrefix + "fx"
+
+// Posting moves Amount into or out of a single ledger account. Debits are
+// positive and credits negative, so a customer account's balance is the
+// negated sum of its postings.
+type Posting struct {
+	AccountID string `json:"account_id"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency,omitempty"`
+}
+
+type JournalEntry struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Postings      []Posting `json:"postings"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+func IsSystemAccount(accountID string) bool {
+	return strings.HasPrefix(accountID, systemAccountPrefix)
+}
+
+func Debit(accountID, currency string, amount int64) Posting {
+	return Posting{AccountID: accountID, Amount: amount, Currency: currency}
+}
+
+func Credit(accountID, currency string, amount int64) Posting {
+	return Posting{AccountID: accountID, Amount: -amount, Currency: currency}
+}
+
+// Validate checks that the entry has at least two non-zero legs and that its
+// debits and credits cancel out in every currency.
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return &errors.ErrLedgerImbalance{EntryID: e.ID}
+	}
+
+	sums := make(map[string]int64)
+	for _, p := range e.Postings {
+		if p.Amount == 0 {
+			return &errors.ErrLedgerImbalance{EntryID: e.ID}
+		}
+		sums[p.Currency] += p.Amount
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			return &errors.ErrLedgerImbalance{EntryID: e.ID, Actual: sum}
+		}
+	}
+	return nil
+}
+
+// BalanceDeltas returns how much each customer account's balance changes as a
+// result of the given entries.
+func BalanceDeltas(entries []*JournalEntry) map[string]int64 {
+	deltas := make(map[string]int64)
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			if !IsSystemAccount(p.AccountID) {
+				deltas[p.AccountID] -= p.Amount
+			}
+		}
+	}
+	return deltas
+}
+
+type Service struct{}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) newEntry(transactionID string, timestamp time.Time, postings ...Posting) *JournalEntry {
+	return &JournalEntry{
+		ID:            uuid.New().String(),
+		TransactionID: transactionID,
+		Postings:      postings,
+		Timestamp:     timestamp,
+	}
+}
+
+// OpeningEntry funds a new account's initial balance from cash. It returns nil
+// when there is nothing to post.
+func (s *Service) OpeningEntry(acc *account.Account) *JournalEntry {
+	if acc.Balance == 0 {
+		return nil
+	}
+
+	currency := acc.CurrentCurrency()
+	return s.newEntry("", acc.CreatedAt,
+		Debit(CashAccount, currency, acc.Balance),
+		Credit(acc.ID, currency, acc.Balance),
+	)
+}
+
+func (s *Service) EntryForTransaction(tx *transaction.Transaction) (*JournalEntry, error) {
+	switch tx.Type {
+	case transaction.TransactionTypeDeposit:
+		return s.newEntry(tx.ID, tx.Timestamp,
+			Debit(CashAccount, tx.Currency, tx.Amount),
+			Credit(tx.AccountID, tx.Currency, tx.Amount),
+		), nil
+	case transaction.TransactionTypeWithdrawal:
+		return s.newEntry(tx.ID, tx.Timestamp,
+			Debit(tx.AccountID, tx.Currency, tx.Amount),
+			Credit(CashAccount, tx.Currency, tx.Amount),
+		), nil
+	case transaction.TransactionTypeFee:
+		return s.newEntry(tx.ID, tx.Timestamp,
+			Debit(tx.AccountID, tx.Currency, tx.Amount),
+			Credit(FeeAccount, tx.Currency, tx.Amount),
+		), nil
+	case transaction.TransactionTypeInterest:
+		return s.newEntry(tx.ID, tx.Timestamp,
+			Debit(InterestAccount, tx.Currency, tx.Amount),
+			Credit(tx.AccountID, tx.Currency, tx.Amount),
+		), nil
+	case transaction.TransactionTypeTransfer, transaction.TransactionTypeMove:
+		if tx.IsConverted() {
+			return s.newEntry(tx.ID, tx.Timestamp,
+				Debit(tx.FromAccountID, tx.Currency, tx.Amount),
+				Credit(FXAccount, tx.Currency, tx.Amount),
+				Debit(FXAccount, tx.TargetCurrency, tx.TargetAmount),
+				Credit(tx.ToAccountID, tx.TargetCurrency, tx.TargetAmount),
+			), nil
+		}
+		return s.newEntry(tx.ID, tx.Timestamp,
+			Debit(tx.FromAccountID, tx.Currency, tx.Amount),
+			Credit(tx.ToAccountID, tx.Currency, tx.Amount),
+		), nil
+	default:
+		return nil, &errors.ErrTransactionFailed{
+			TransactionID: tx.ID,
+			Reason:        "no ledger mapping for transaction type " + string(tx.Type),
+		}
+	}
+}
+
+// ReversalEntry posts the original transaction's legs in the opposite
+// direction, scaled to the reversal's amount.
+func (s *Service) ReversalEntry(original, reversal *transaction.Transaction) (*JournalEntry, error) {
+	mirror := *original
+	mirror.ID = reversal.ID
+	mirror.Amount = reversal.Amount
+	mirror.TargetAmount = reversal.TargetAmount
+	mirror.Timestamp = reversal.Timestamp
+
+	entry, err := s.EntryForTransaction(&mirror)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entry.Postings {
+		entry.Postings[i].Amount = -entry.Postings[i].Amount
+	}
+	return entry, nil
+}
+
+// Verify checks the ledger invariants: every entry balances, the postings in
+// each currency sum to zero, and each account's stored balance equals the
+// balance its postings imply.
+func (s *Service) Verify(accounts []*account.Account, entries []*JournalEntry) error {
+	totals := make(map[string]int64)
+	for _, e := range entries {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+		for _, p := range e.Postings {
+			totals[p.Currency] += p.Amount
+		}
+	}
+	for _, total := range totals {
+		if total != 0 {
+			return &errors.ErrLedgerImbalance{Actual: total}
+		}
+	}
+
+	deltas := BalanceDeltas(entries)
+	for _, acc := range accounts {
+		if deltas[acc.ID] != acc.Balance {
+			return &errors.ErrLedgerImbalance{
+				AccountID: acc.ID,
+				Expected:  acc.Balance,
+				Actual:    deltas[acc.ID],
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+func TestEntryForTransaction(t *testing.T) {
+	service := NewService()
+
+	tests := []struct {
+		name   string
+		tx     *transaction.Transaction
+		deltas map[string]int64
+	}{
+		{
+			name: "deposit",
+			tx: &transaction.Transaction{
+				ID:        "tx-1",
+				Type:      transaction.TransactionTypeDeposit,
+				AccountID: "acc-1",
+				Amount:    500,
+			},
+			deltas: map[string]int64{"acc-1": 500},
+		},
+		{
+			name: "withdrawal",
+			tx: &transaction.Transaction{
+				ID:        "tx-2",
+				Type:      transaction.TransactionTypeWithdrawal,
+				AccountID: "acc-1",
+				Amount:    200,
+			},
+			deltas: map[string]int64{"acc-1": -200},
+		},
+		{
+			name: "transfer",
+			tx: &transaction.Transaction{
+				ID:            "tx-3",
+				Type:          transaction.TransactionTypeTransfer,
+				FromAccountID: "acc-1",
+				ToAccountID:   "acc-2",
+				Amount:        300,
+			},
+			deltas: map[string]int64{"acc-1": -300, "acc-2": 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := service.EntryForTransaction(tt.tx)
+			if err != nil {
+				t.Fatalf("EntryForTransaction() error = %v", err)
+			}
+
+			if err := entry.Validate(); err != nil {
+				t.Errorf("EntryForTransaction() entry does not balance: %v", err)
+			}
+
+			if entry.TransactionID != tt.tx.ID {
+				t.Errorf("EntryForTransaction() transaction ID = %v, want %v", entry.TransactionID, tt.tx.ID)
+			}
+
+			deltas := BalanceDeltas([]*JournalEntry{entry})
+			for id, want := range tt.deltas {
+				if deltas[id] != want {
+					t.Errorf("EntryForTransaction() delta for %s = %v, want %v", id, deltas[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	unbalanced := &JournalEntry{
+		ID:       "entry-1",
+		Postings: []Posting{Debit(CashAccount, "INR", 100), Credit("acc-1", "INR", 90)},
+	}
+	if _, ok := unbalanced.Validate().(*errors.ErrLedgerImbalance); !ok {
+		t.Error("Validate() expected *errors.ErrLedgerImbalance for unbalanced entry")
+	}
+
+	single := &JournalEntry{ID: "entry-2", Postings: []Posting{Debit(CashAccount, "INR", 100)}}
+	if single.Validate() == nil {
+		t.Error("Validate() expected error for single-legged entry")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	service := NewService()
+
+	acc := &account.Account{ID: "acc-1", CustomerName: "Ravi Kumar", Balance: 1000, CreatedAt: time.Now()}
+	opening := service.OpeningEntry(acc)
+
+	withdrawal, _ := service.EntryForTransaction(&transaction.Transaction{
+		ID:        "tx-1",
+		Type:      transaction.TransactionTypeWithdrawal,
+		AccountID: "acc-1",
+		Amount:    400,
+	})
+	acc.Balance -= 400
+
+	entries := []*JournalEntry{opening, withdrawal}
+	if err := service.Verify([]*account.Account{acc}, entries); err != nil {
+		t.Errorf("Verify() unexpected error = %v", err)
+	}
+
+	acc.Balance = 700
+	if _, ok := service.Verify([]*account.Account{acc}, entries).(*errors.ErrLedgerImbalance); !ok {
+		t.Error("Verify() expected *errors.ErrLedgerImbalance for drifted balance")
+	}
+}
+
+func TestReversalEntry(t *testing.T) {
+	service := NewService()
+
+	original := &transaction.Transaction{
+		ID: "tx-1", Type: transaction.TransactionTypeTransfer, FromAccountID: "a", ToAccountID: "b",
+		Amount: 8300, Currency: "INR", TargetAmount: 100, TargetCurrency: "USD", Rate: "0.012048",
+		Timestamp: time.Now(),
+	}
+	reversal := &transaction.Transaction{
+		ID: "tx-2", Type: transaction.TransactionTypeReversal, FromAccountID: "a", ToAccountID: "b",
+		Amount: 8300, Currency: "INR", TargetAmount: 100, TargetCurrency: "USD",
+		OriginalTransactionID: "tx-1", Timestamp: time.Now(),
+	}
+
+	entry, err := service.ReversalEntry(original, reversal)
+	if err != nil {
+		t.Fatalf("ReversalEntry() error = %v", err)
+	}
+	if err := entry.Validate(); err != nil {
+		t.Errorf("ReversalEntry() does not balance: %v", err)
+	}
+
+	deltas := BalanceDeltas([]*JournalEntry{entry})
+	if deltas["a"] != 8300 || deltas["b"] != -100 || entry.TransactionID != "tx-2" {
+		t.Errorf("ReversalEntry() deltas = %v for %s, want a +8300, b -100 for tx-2", deltas, entry.TransactionID)
+	}
+}
@@ -0,0 +1,56 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	"banking-service/internal/approval"
+	"banking-service/internal/store"
+)
+
+// ApprovalExpirer closes payments that were not approved by enough holders
+// before they expired.
+type ApprovalExpirer struct {
+	store           store.Repository
+	approvalService *approval.Service
+}
+
+func NewApprovalExpirer(store store.Repository, approvalService *approval.Service) *ApprovalExpirer {
+	return &ApprovalExpirer{
+		store:           store,
+		approvalService: approvalService,
+	}
+}
+
+// Run expires every overdue approval and returns how many were expired.
+func (e *ApprovalExpirer) Run(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	for _, candidate := range e.store.ListExpiredApprovals(ctx, now) {
+		marked := false
+		err := e.store.Apply(ctx, func(uow store.UnitOfWork) error {
+			a, err := uow.GetApproval(candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			// The approval may have been completed or rejected since it was
+			// listed.
+			marked = a.Expired(now)
+			if !marked {
+				return nil
+			}
+
+			if err := e.approvalService.Expire(a, now); err != nil {
+				return err
+			}
+			return uow.SaveApproval(a)
+		})
+		if err != nil {
+			return expired, err
+		}
+		if marked {
+			expired++
+		}
+	}
+	return expired, nil
+}
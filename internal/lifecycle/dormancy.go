@@ -0,0 +1,58 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/store"
+)
+
+// DormancySweeper marks active accounts dormant once they have gone a
+// configured period without any transaction.
+type DormancySweeper struct {
+	store          store.Repository
+	accountService *account.Service
+	after          time.Duration
+}
+
+func NewDormancySweeper(store store.Repository, accountService *account.Service, after time.Duration) *DormancySweeper {
+	return &DormancySweeper{
+		store:          store,
+		accountService: accountService,
+		after:          after,
+	}
+}
+
+// Run sweeps every account once and returns how many were marked dormant.
+func (d *DormancySweeper) Run(ctx context.Context, now time.Time) (int, error) {
+	swept := 0
+	for _, candidate := range d.store.GetAllAccounts(ctx) {
+		if !d.accountService.IsDormant(candidate, now, d.after) {
+			continue
+		}
+
+		marked := false
+		err := d.store.Apply(ctx, func(uow store.UnitOfWork) error {
+			acc, err := uow.GetAccount(candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			// Re-check under the store lock: the account may have been used
+			// since the snapshot above.
+			marked = d.accountService.IsDormant(acc, now, d.after)
+			if !marked {
+				return nil
+			}
+			return d.accountService.Transition(acc, account.StatusDormant)
+		})
+		if err != nil {
+			return swept, err
+		}
+		if marked {
+			swept++
+		}
+	}
+	return swept, nil
+}
@@ -0,0 +1,75 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/hold"
+	"banking-service/internal/store"
+)
+
+// HoldExpirer releases the funds of holds that were neither captured nor
+// released before they expired.
+type HoldExpirer struct {
+	store          store.Repository
+	accountService *account.Service
+	holdService    *hold.Service
+	logger         *logrus.Logger
+}
+
+func NewHoldExpirer(store store.Repository, accountService *account.Service, holdService *hold.Service, logger *logrus.Logger) *HoldExpirer {
+	return &HoldExpirer{
+		store:          store,
+		accountService: accountService,
+		holdService:    holdService,
+		logger:         logger,
+	}
+}
+
+// Run expires every overdue hold and returns how many were expired. A hold
+// that cannot be expired is logged and left for the next run, so it does not
+// hold up the others.
+func (e *HoldExpirer) Run(ctx context.Context, now time.Time) (int, error) {
+	expired := 0
+	for _, candidate := range e.store.ListExpiredHolds(ctx, now) {
+		marked := false
+		err := e.store.Apply(ctx, func(uow store.UnitOfWork) error {
+			h, err := uow.GetHold(candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			// The hold may have been captured or released since it was listed.
+			marked = h.Expired(now)
+			if !marked {
+				return nil
+			}
+
+			acc, err := uow.GetAccount(h.AccountID)
+			if err != nil {
+				return err
+			}
+			if err := e.accountService.ReleaseReserved(acc, h.Amount); err != nil {
+				return err
+			}
+			if err := e.holdService.Expire(h, now); err != nil {
+				return err
+			}
+			return uow.SaveHold(h)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return expired, err
+			}
+			e.logger.WithError(err).WithField("hold_id", candidate.ID).Error("Failed to expire hold")
+			continue
+		}
+		if marked {
+			expired++
+		}
+	}
+	return expired, nil
+}
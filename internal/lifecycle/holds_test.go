@@ -0,0 +1,61 @@
+package lifecycle
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/hold"
+	"banking-service/internal/store"
+)
+
+func TestHoldExpirerSkipsFailingHolds(t *testing.T) {
+	ctx := context.Background()
+	repo := store.NewMemoryStore()
+	openAccount(t, repo, "payer", 1000)
+
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount("payer")
+		if err != nil {
+			return err
+		}
+		if err := account.NewService().Reserve(acc, 300); err != nil {
+			return err
+		}
+		for _, h := range []*hold.Hold{
+			{ID: "orphan", AccountID: "missing", Amount: 100, Status: hold.StatusActive, ExpiresAt: now.Add(-2 * time.Hour)},
+			{ID: "stale", AccountID: "payer", Amount: 300, Status: hold.StatusActive, ExpiresAt: now.Add(-time.Hour)},
+		} {
+			if err := uow.SaveHold(h); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("place holds: %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	expirer := NewHoldExpirer(repo, account.NewService(), hold.NewService(time.Hour), logger)
+
+	expired, err := expirer.Run(ctx, now)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("Run() expired %d holds, want 1", expired)
+	}
+	if h, _ := repo.GetHold(ctx, "stale"); h.Status != hold.StatusExpired {
+		t.Errorf("stale hold status = %v, want %v", h.Status, hold.StatusExpired)
+	}
+	if acc, _ := repo.GetAccount(ctx, "payer"); acc.HeldAmount != 0 {
+		t.Errorf("held amount = %d, want 0 after the stale hold expired", acc.HeldAmount)
+	}
+}
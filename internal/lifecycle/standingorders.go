@@ -0,0 +1,114 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	"banking-service/internal/payment"
+	"banking-service/internal/standingorder"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// StandingOrderRunner pays the standing orders that have fallen due through
+// the same transfer path as the API.
+type StandingOrderRunner struct {
+	store              store.Repository
+	transactionService *transaction.Service
+	orderService       *standingorder.Service
+	payments           *payment.Processor
+}
+
+func NewStandingOrderRunner(store store.Repository, transactionService *transaction.Service, orderService *standingorder.Service, payments *payment.Processor) *StandingOrderRunner {
+	return &StandingOrderRunner{
+		store:              store,
+		transactionService: transactionService,
+		orderService:       orderService,
+		payments:           payments,
+	}
+}
+
+// Run attempts every due standing order and returns how many payments were
+// made. Occurrences missed while the job was not running are caught up on one
+// at a time.
+func (r *StandingOrderRunner) Run(ctx context.Context, now time.Time) (int, error) {
+	paid := 0
+	for _, candidate := range r.store.ListDueStandingOrders(ctx, now) {
+		for {
+			attempted, ok, err := r.execute(ctx, candidate.ID, now)
+			if err != nil {
+				return paid, err
+			}
+			if ok {
+				paid++
+			}
+			if !attempted {
+				break
+			}
+		}
+	}
+	return paid, nil
+}
+
+// execute makes one attempt at the order's current occurrence, if one is
+// due, and reports whether it was attempted and whether it was paid.
+func (r *StandingOrderRunner) execute(ctx context.Context, id string, now time.Time) (bool, bool, error) {
+	var attempted bool
+	var req transaction.TransferRequest
+	var declined error
+	err := r.store.Apply(ctx, func(uow store.UnitOfWork) error {
+		o, err := uow.GetStandingOrder(id)
+		if err != nil {
+			return err
+		}
+
+		attempted = o.Due(now)
+		if !attempted {
+			return nil
+		}
+
+		req = transaction.TransferRequest{
+			FromAccountID: o.FromAccountID,
+			ToAccountID:   o.ToAccountID,
+			Amount:        o.Amount,
+			Currency:      o.Currency,
+		}
+		tx, err := r.payments.Transfer(uow, req, now)
+		if err != nil {
+			declined = err
+			return err
+		}
+
+		r.orderService.Succeeded(o, tx.ID, now)
+		return uow.SaveStandingOrder(o)
+	})
+	if err == nil {
+		return attempted, attempted, nil
+	}
+	if err != declined || errors.Code(declined) == "" {
+		return attempted, false, err
+	}
+
+	// The transfer was declined, so nothing it staged was written. Record
+	// the failed attempt and what happens next in a unit of its own.
+	err = r.store.Apply(ctx, func(uow store.UnitOfWork) error {
+		o, err := uow.GetStandingOrder(id)
+		if err != nil {
+			return err
+		}
+		if !o.Due(now) {
+			return nil
+		}
+
+		failed := r.transactionService.CreateTransferTransaction(req.FromAccountID, req.ToAccountID, req.Amount, req.Currency)
+		r.transactionService.MarkFailed(failed, errors.Code(declined), declined.Error())
+		if err := uow.StoreTransaction(failed); err != nil {
+			return err
+		}
+
+		r.orderService.Declined(o, declined, failed.ID, now)
+		return uow.SaveStandingOrder(o)
+	})
+	return attempted, false, err
+}
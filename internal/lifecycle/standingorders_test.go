@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/ledger"
+	"banking-service/internal/payment"
+	"banking-service/internal/standingorder"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+)
+
+func openAccount(t *testing.T, repo *store.MemoryStore, id string, balance int64) {
+	t.Helper()
+
+	acc := &account.Account{ID: id, CustomerName: id, Balance: balance, Status: account.StatusActive, CreatedAt: time.Now()}
+	err := repo.Apply(context.Background(), func(uow store.UnitOfWork) error {
+		if err := uow.CreateAccount(acc); err != nil {
+			return err
+		}
+		if entry := ledger.NewService().OpeningEntry(acc); entry != nil {
+			return uow.PostEntry(entry)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("open account %s: %v", id, err)
+	}
+}
+
+func TestStandingOrderRunner(t *testing.T) {
+	ctx := context.Background()
+	repo := store.NewMemoryStore()
+	openAccount(t, repo, "payer", 1500)
+	openAccount(t, repo, "payee", 0)
+
+	orders := standingorder.NewService(1, time.Hour)
+	processor := payment.NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, nil)
+	runner := NewStandingOrderRunner(repo, transaction.NewService(), orders, processor)
+
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	order, err := orders.Create(standingorder.CreateRequest{
+		FromAccountID: "payer",
+		ToAccountID:   "payee",
+		Amount:        1000,
+		Frequency:     standingorder.FrequencyDaily,
+		StartAt:       &start,
+		MaxExecutions: 3,
+	}, "INR", start)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.Apply(ctx, func(uow store.UnitOfWork) error { return uow.SaveStandingOrder(order) }); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// A day late: the first occurrence is paid, the second is declined
+	// and scheduled for a retry an hour later.
+	now := start.AddDate(0, 0, 1).Add(time.Minute)
+	paid, err := runner.Run(ctx, now)
+	if err != nil || paid != 1 {
+		t.Fatalf("first run: paid %d, err %v; want 1 payment", paid, err)
+	}
+
+	got, err := repo.GetStandingOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Occurrences != 1 || got.Attempts != 1 || !got.NextRunAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("got occurrences %d attempts %d next run %v, want a retry of the second", got.Occurrences, got.Attempts, got.NextRunAt)
+	}
+	if len(got.History) != 2 || got.History[1].Status != standingorder.ExecutionRetrying {
+		t.Fatalf("got history %+v, want a payment and a retry", got.History)
+	}
+
+	failed, err := repo.GetTransaction(ctx, got.History[1].TransactionID)
+	if err != nil || failed.Status != transaction.TransactionStatusFailed || failed.FailureCode != "insufficient_funds" {
+		t.Fatalf("got failed transaction %+v, err %v", failed, err)
+	}
+
+	// The retry fails too and uses up the retries, so the second occurrence
+	// is skipped. The third is not due yet.
+	if paid, err := runner.Run(ctx, now.Add(time.Hour)); err != nil || paid != 0 {
+		t.Fatalf("retry run: paid %d, err %v; want none", paid, err)
+	}
+	got, _ = repo.GetStandingOrder(ctx, order.ID)
+	if got.Occurrences != 2 || !got.NextRunAt.Equal(start.AddDate(0, 0, 2)) {
+		t.Fatalf("got occurrences %d next run %v, want the third day", got.Occurrences, got.NextRunAt)
+	}
+
+	payer, _ := repo.GetAccount(ctx, "payer")
+	payee, _ := repo.GetAccount(ctx, "payee")
+	if payer.Balance != 500 || payee.Balance != 1000 {
+		t.Errorf("got balances %d and %d, want 500 and 1000", payer.Balance, payee.Balance)
+	}
+
+	accounts, entries := repo.LedgerState(ctx)
+	if err := ledger.NewService().Verify(accounts, entries); err != nil {
+		t.Errorf("ledger does not balance: %v", err)
+	}
+}
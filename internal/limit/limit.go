@@ -0,0 +1,179 @@
+package limit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+type Period string
+
+const (
+	PeriodTransaction Period = "transaction"
+	// Daily and monthly limits run over UTC calendar days and months.
+	PeriodDaily   Period = "daily"
+	PeriodMonthly Period = "monthly"
+)
+
+// Limit caps transactions of one type that an account makes: how much a
+// single one may be for, or how much and how many there may be per day or
+// month. A zero MaxAmount or MaxCount means no cap of that kind.
+type Limit struct {
+	Type      transaction.TransactionType `json:"type"`
+	Period    Period                      `json:"period"`
+	MaxAmount int64                       `json:"max_amount,omitempty"`
+	MaxCount  int64                       `json:"max_count,omitempty"`
+}
+
+func (l Limit) Validate() error {
+	switch l.Type {
+	case transaction.TransactionTypeDeposit, transaction.TransactionTypeWithdrawal, transaction.TransactionTypeTransfer:
+	default:
+		return &errors.ErrInvalidParameter{Name: "type", Value: string(l.Type)}
+	}
+	switch l.Period {
+	case PeriodTransaction, PeriodDaily, PeriodMonthly:
+	default:
+		return &errors.ErrInvalidParameter{Name: "period", Value: string(l.Period)}
+	}
+
+	if l.MaxAmount < 0 || (l.MaxAmount == 0 && l.MaxCount == 0) {
+		return &errors.ErrInvalidParameter{Name: "max_amount", Value: fmt.Sprint(l.MaxAmount)}
+	}
+	if l.MaxCount < 0 || (l.MaxCount > 0 && l.Period == PeriodTransaction) {
+		return &errors.ErrInvalidParameter{Name: "max_count", Value: fmt.Sprint(l.MaxCount)}
+	}
+	return nil
+}
+
+// Validate checks every limit and that no two share a type and period.
+func Validate(limits []Limit) error {
+	seen := make(map[Limit]bool)
+	for _, l := range limits {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+
+		key := Limit{Type: l.Type, Period: l.Period}
+		if seen[key] {
+			return &errors.ErrInvalidParameter{Name: "limits", Value: fmt.Sprintf("duplicate %s %s limit", l.Period, l.Type)}
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Effective returns the limits that apply to an account: its own, plus the
+// defaults for any type and period it does not set itself.
+func Effective(defaults, own []Limit) []Limit {
+	limits := append([]Limit(nil), own...)
+	for _, d := range defaults {
+		overridden := false
+		for _, l := range own {
+			if l.Type == d.Type && l.Period == d.Period {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			limits = append(limits, d)
+		}
+	}
+	return limits
+}
+
+// Window returns the start and end of the period containing t.
+func (p Period) Window(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	switch p {
+	case PeriodDaily:
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	case PeriodMonthly:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	return t, t
+}
+
+// Filter selects the history a periodic limit on l.Type is measured against
+// for a transaction made at t.
+func (l Limit) Filter(t time.Time) transaction.Filter {
+	from, to := l.Period.Window(t)
+	return transaction.Filter{
+		Types:    []transaction.TransactionType{l.Type},
+		Statuses: []transaction.TransactionStatus{transaction.TransactionStatusPending, transaction.TransactionStatusCompleted, transaction.TransactionStatusReversed},
+		From:     from,
+		To:       to,
+	}
+}
+
+// Check returns *errors.ErrLimitExceeded if tx, made by accountID, would
+// break l given history, the account's transactions that l.Filter selects.
+// Transactions the account only received, such as incoming transfers, do
+// not count.
+func (l Limit) Check(accountID string, tx *transaction.Transaction, history []*transaction.Transaction) error {
+	if tx.Type != l.Type {
+		return nil
+	}
+
+	if l.Period == PeriodTransaction {
+		if l.MaxAmount > 0 && tx.Amount > l.MaxAmount {
+			return l.exceeded(accountID, "amount", l.MaxAmount, l.MaxAmount)
+		}
+		return nil
+	}
+
+	var used, count int64
+	for _, h := range history {
+		if h.ID == tx.ID || (h.AccountID != accountID && h.FromAccountID != accountID) {
+			continue
+		}
+		used += h.Amount
+		count++
+	}
+
+	if l.MaxAmount > 0 && used+tx.Amount > l.MaxAmount {
+		return l.exceeded(accountID, "amount", l.MaxAmount, max(l.MaxAmount-used, 0))
+	}
+	if l.MaxCount > 0 && count+1 > l.MaxCount {
+		return l.exceeded(accountID, "count", l.MaxCount, max(l.MaxCount-count, 0))
+	}
+	return nil
+}
+
+func (l Limit) exceeded(accountID, measure string, maximum, remaining int64) error {
+	return &errors.ErrLimitExceeded{
+		AccountID:       accountID,
+		TransactionType: string(l.Type),
+		Period:          string(l.Period),
+		Measure:         measure,
+		Max:             maximum,
+		Remaining:       remaining,
+	}
+}
+
+// ParseLimits decodes a JSON list of limits, e.g.
+// [{"type": "withdrawal", "period": "daily", "max_amount": 5000000, "max_count": 10}].
+func ParseLimits(data []byte) ([]Limit, error) {
+	var limits []Limit
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return nil, fmt.Errorf("decode limits: %w", err)
+	}
+	if err := Validate(limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+func LoadLimits(path string) ([]Limit, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read limits: %w", err)
+	}
+	return ParseLimits(data)
+}
@@ -0,0 +1,111 @@
+package limit
+
+import (
+	"testing"
+	"time"
+
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+func TestCheck(t *testing.T) {
+	history := []*transaction.Transaction{
+		{ID: "w1", Type: transaction.TransactionTypeWithdrawal, AccountID: "acc", Amount: 3000},
+		{ID: "w2", Type: transaction.TransactionTypeWithdrawal, AccountID: "acc", Amount: 4000},
+		{ID: "in", Type: transaction.TransactionTypeTransfer, FromAccountID: "other", ToAccountID: "acc", Amount: 9000},
+	}
+	withdrawal := &transaction.Transaction{ID: "new", Type: transaction.TransactionTypeWithdrawal, AccountID: "acc", Amount: 2000}
+
+	tests := []struct {
+		name          string
+		limit         Limit
+		history       []*transaction.Transaction
+		wantMeasure   string
+		wantRemaining int64
+	}{
+		{"per transaction within", Limit{Type: transaction.TransactionTypeWithdrawal, Period: PeriodTransaction, MaxAmount: 2000}, nil, "", 0},
+		{"per transaction over", Limit{Type: transaction.TransactionTypeWithdrawal, Period: PeriodTransaction, MaxAmount: 1999}, nil, "amount", 1999},
+		{"daily amount within", Limit{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily, MaxAmount: 9000}, history, "", 0},
+		{"daily amount over", Limit{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily, MaxAmount: 8000}, history, "amount", 1000},
+		{"daily count over", Limit{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily, MaxCount: 2}, history, "count", 0},
+		{"other type", Limit{Type: transaction.TransactionTypeTransfer, Period: PeriodDaily, MaxCount: 1}, history, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limit.Check("acc", withdrawal, tt.history)
+			if tt.wantMeasure == "" {
+				if err != nil {
+					t.Errorf("Check() unexpected error = %v", err)
+				}
+				return
+			}
+
+			exceeded, ok := err.(*errors.ErrLimitExceeded)
+			if !ok {
+				t.Fatalf("Check() error = %v, want *errors.ErrLimitExceeded", err)
+			}
+			if exceeded.Measure != tt.wantMeasure || exceeded.Remaining != tt.wantRemaining {
+				t.Errorf("Check() = %s with %d remaining, want %s with %d", exceeded.Measure, exceeded.Remaining, tt.wantMeasure, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	at := time.Date(2024, 2, 29, 17, 30, 0, 0, time.UTC)
+
+	from, to := PeriodDaily.Window(at)
+	if !from.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("daily Window() = %v to %v", from, to)
+	}
+
+	from, to = PeriodMonthly.Window(at)
+	if !from.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("monthly Window() = %v to %v", from, to)
+	}
+}
+
+func TestEffective(t *testing.T) {
+	defaults := []Limit{
+		{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily, MaxAmount: 5000},
+		{Type: transaction.TransactionTypeTransfer, Period: PeriodDaily, MaxAmount: 5000},
+	}
+	own := []Limit{{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily, MaxAmount: 100}}
+
+	limits := Effective(defaults, own)
+	if len(limits) != 2 {
+		t.Fatalf("Effective() = %v, want 2 limits", limits)
+	}
+	for _, l := range limits {
+		if l.Type == transaction.TransactionTypeWithdrawal && l.MaxAmount != 100 {
+			t.Errorf("Effective() withdrawal limit = %v, want the account's own", l.MaxAmount)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []Limit{
+		{Type: transaction.TransactionTypeWithdrawal, Period: PeriodTransaction, MaxAmount: 100},
+		{Type: transaction.TransactionTypeWithdrawal, Period: PeriodMonthly, MaxCount: 30},
+	}
+	if err := Validate(valid); err != nil {
+		t.Errorf("Validate() unexpected error = %v", err)
+	}
+
+	invalid := [][]Limit{
+		{{Type: transaction.TransactionTypeFee, Period: PeriodDaily, MaxAmount: 100}},
+		{{Type: transaction.TransactionTypeWithdrawal, Period: "weekly", MaxAmount: 100}},
+		{{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily}},
+		{{Type: transaction.TransactionTypeWithdrawal, Period: PeriodTransaction, MaxCount: 1}},
+		{
+			{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily, MaxAmount: 100},
+			{Type: transaction.TransactionTypeWithdrawal, Period: PeriodDaily, MaxCount: 1},
+		},
+	}
+	for _, limits := range invalid {
+		if err := Validate(limits); err == nil {
+			t.Errorf("Validate(%v) expected error", limits)
+		}
+	}
+}
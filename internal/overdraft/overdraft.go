@@ -0,0 +1,148 @@
+package overdraft
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/payment"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/money"
+)
+
+const daysPerYear = 365
+
+const (
+	InterestReason = "overdraft interest"
+	FeeReason      = "overdraft fee"
+)
+
+// Charge is an amount an overdrawn account owes, with the reason recorded on
+// its fee transaction.
+type Charge struct {
+	money.Money
+	Reason string
+}
+
+// Accruer charges overdrawn accounts once per calendar day (UTC): interest on
+// the overdrawn amount at an annual rate, and a flat daily fee. Days the
+// job did not run are not charged retroactively.
+type Accruer struct {
+	store              store.Repository
+	accountService     *account.Service
+	transactionService *transaction.Service
+	payments           *payment.Processor
+	logger             *logrus.Logger
+
+	dailyRate *big.Rat
+	dailyFee  int64
+}
+
+// NewAccruer builds an accruer for the given annual interest rate, such as
+// "0.18" for 18%, and daily fee in minor units. An empty rate charges no
+// interest.
+func NewAccruer(store store.Repository, accountService *account.Service, transactionService *transaction.Service, payments *payment.Processor, annualRate string, dailyFee int64, logger *logrus.Logger) (*Accruer, error) {
+	a := &Accruer{
+		store:              store,
+		accountService:     accountService,
+		transactionService: transactionService,
+		payments:           payments,
+		logger:             logger,
+		dailyFee:           dailyFee,
+	}
+
+	if annualRate != "" {
+		rate, err := money.ParseRate(annualRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid overdraft interest rate %q", annualRate)
+		}
+		a.dailyRate = rate.Quo(rate, big.NewRat(daysPerYear, 1))
+	}
+	return a, nil
+}
+
+// Charges returns what the account owes for today, or nothing if it is not
+// overdrawn or was already charged today.
+func (a *Accruer) Charges(acc *account.Account, now time.Time) []Charge {
+	if acc.Balance >= 0 || acc.CurrentStatus() == account.StatusClosed {
+		return nil
+	}
+	if !acc.OverdraftChargedAt.IsZero() && !day(acc.OverdraftChargedAt).Before(day(now)) {
+		return nil
+	}
+
+	overdrawn := money.Money{Amount: -acc.Balance, Currency: acc.CurrentCurrency()}
+
+	var charges []Charge
+	if a.dailyRate != nil {
+		if interest := overdrawn.Mul(a.dailyRate); interest.Amount > 0 {
+			charges = append(charges, Charge{Money: interest, Reason: InterestReason})
+		}
+	}
+	if a.dailyFee > 0 {
+		charges = append(charges, Charge{Money: money.Money{Amount: a.dailyFee, Currency: overdrawn.Currency}, Reason: FeeReason})
+	}
+	return charges
+}
+
+// Run charges every overdrawn account and returns how many were charged. An
+// account that cannot be charged is logged and skipped, so it does not hold
+// up the others; it is charged on a later run that day.
+func (a *Accruer) Run(ctx context.Context, now time.Time) (int, error) {
+	if a.dailyRate == nil && a.dailyFee <= 0 {
+		return 0, nil
+	}
+
+	charged := 0
+	for _, candidate := range a.store.GetAllAccounts(ctx) {
+		if len(a.Charges(candidate, now)) == 0 {
+			continue
+		}
+
+		posted := false
+		err := a.store.Apply(ctx, func(uow store.UnitOfWork) error {
+			acc, err := uow.GetAccount(candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			charges := a.Charges(acc, now)
+			for _, charge := range charges {
+				if err := a.accountService.Charge(acc, charge.Amount); err != nil {
+					return err
+				}
+
+				tx := a.transactionService.CreateFeeTransaction(acc.ID, charge.Amount, charge.Currency, charge.Reason)
+				if err := a.payments.Record(uow, tx); err != nil {
+					return err
+				}
+			}
+
+			posted = len(charges) > 0
+			if posted {
+				acc.OverdraftChargedAt = now
+			}
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return charged, err
+			}
+			a.logger.WithError(err).WithField("account_id", candidate.ID).Error("Failed to charge overdraft")
+			continue
+		}
+		if posted {
+			charged++
+		}
+	}
+	return charged, nil
+}
+
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
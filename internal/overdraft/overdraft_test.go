@@ -0,0 +1,128 @@
+package overdraft
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/ledger"
+	"banking-service/internal/payment"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+)
+
+func TestAccruerRun(t *testing.T) {
+	ctx := context.Background()
+	repo := store.NewMemoryStore()
+	for _, acc := range []*account.Account{
+		{ID: "overdrawn", CustomerName: "Meera", Balance: -100000, OverdraftLimit: 200000, Status: account.StatusActive},
+		{ID: "in-credit", CustomerName: "Arjun", Balance: 5000, Status: account.StatusActive},
+	} {
+		if err := repo.CreateAccount(ctx, acc); err != nil {
+			t.Fatalf("CreateAccount() error = %v", err)
+		}
+	}
+
+	processor := payment.NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, nil)
+	accruer, err := NewAccruer(repo, account.NewService(), transaction.NewService(), processor, "0.1825", 50, nil)
+	if err != nil {
+		t.Fatalf("NewAccruer() error = %v", err)
+	}
+
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	charged, err := accruer.Run(ctx, now)
+	if err != nil || charged != 1 {
+		t.Fatalf("Run() = %v, %v, want 1 account charged", charged, err)
+	}
+
+	// 100000 * 0.1825 / 365 = 50 interest, plus the 50 fee.
+	acc, _ := repo.GetAccount(ctx, "overdrawn")
+	if acc.Balance != -100100 {
+		t.Errorf("Run() balance = %v, want -100100", acc.Balance)
+	}
+
+	txs := repo.GetAllTransactions(ctx)
+	if len(txs) != 2 {
+		t.Fatalf("Run() recorded %d transactions, want 2", len(txs))
+	}
+	for _, tx := range txs {
+		if tx.Type != transaction.TransactionTypeFee || tx.Amount != 50 {
+			t.Errorf("Run() recorded %s of %d, want fee of 50", tx.Type, tx.Amount)
+		}
+	}
+
+	if charged, _ := accruer.Run(ctx, now.Add(10*time.Hour)); charged != 0 {
+		t.Errorf("Run() later the same day charged %d accounts, want 0", charged)
+	}
+	if charged, _ := accruer.Run(ctx, now.Add(24*time.Hour)); charged != 1 {
+		t.Errorf("Run() the next day charged %d accounts, want 1", charged)
+	}
+	if acc, _ := repo.GetAccount(ctx, "overdrawn"); acc.Balance != -100200 {
+		t.Errorf("Run() the next day balance = %v, want -100200", acc.Balance)
+	}
+}
+
+// vanishingStore lists an overdrawn account that is no longer in the store
+// ahead of the real ones, so charging it fails.
+type vanishingStore struct {
+	*store.MemoryStore
+}
+
+func (s vanishingStore) GetAllAccounts(ctx context.Context) []*account.Account {
+	gone := &account.Account{ID: "gone", Balance: -100000, Status: account.StatusActive}
+	return append([]*account.Account{gone}, s.MemoryStore.GetAllAccounts(ctx)...)
+}
+
+func TestAccruerRunSkipsFailingAccounts(t *testing.T) {
+	ctx := context.Background()
+	repo := store.NewMemoryStore()
+	overdrawn := &account.Account{ID: "overdrawn", CustomerName: "Meera", Balance: -100000, OverdraftLimit: 200000, Status: account.StatusActive}
+	if err := repo.CreateAccount(ctx, overdrawn); err != nil {
+		t.Fatalf("CreateAccount() error = %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	processor := payment.NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, nil)
+	accruer, err := NewAccruer(vanishingStore{repo}, account.NewService(), transaction.NewService(), processor, "0.1825", 50, logger)
+	if err != nil {
+		t.Fatalf("NewAccruer() error = %v", err)
+	}
+
+	charged, err := accruer.Run(ctx, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+	if err != nil || charged != 1 {
+		t.Fatalf("Run() = %v, %v, want 1 account charged past the failing one", charged, err)
+	}
+	if acc, _ := repo.GetAccount(ctx, "overdrawn"); acc.Balance != -100100 {
+		t.Errorf("Run() balance = %v, want -100100", acc.Balance)
+	}
+}
+
+func TestChargesReasons(t *testing.T) {
+	accruer, err := NewAccruer(nil, nil, nil, nil, "0.1825", 50, nil)
+	if err != nil {
+		t.Fatalf("NewAccruer() error = %v", err)
+	}
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	charges := accruer.Charges(&account.Account{ID: "overdrawn", Balance: -100000, Status: account.StatusActive}, now)
+	if len(charges) != 2 || charges[0].Reason != InterestReason || charges[1].Reason != FeeReason {
+		t.Errorf("Charges() = %+v, want interest then the fee", charges)
+	}
+
+	// 100 * 0.1825 / 365 rounds to no interest, leaving only the fee.
+	charges = accruer.Charges(&account.Account{ID: "slightly", Balance: -100, Status: account.StatusActive}, now)
+	if len(charges) != 1 || charges[0].Amount != 50 || charges[0].Reason != FeeReason {
+		t.Errorf("Charges() = %+v, want only the fee, recorded as %q", charges, FeeReason)
+	}
+}
+
+func TestNewAccruerRejectsInvalidRate(t *testing.T) {
+	if _, err := NewAccruer(nil, nil, nil, nil, "-0.1", 0, nil); err == nil {
+		t.Error("NewAccruer() expected error for negative rate")
+	}
+}
@@ -0,0 +1,199 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+type DispatcherOptions struct {
+	Workers   int
+	QueueSize int
+	// MaxAttempts is how many times a transfer is tried before it is marked
+	// failed. Errors that cannot go away on their own fail it immediately.
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// Dispatcher settles pending transfers on a pool of background workers.
+type Dispatcher struct {
+	store     store.Repository
+	processor *Processor
+	logger    *logrus.Logger
+	opts      DispatcherOptions
+
+	queue chan string
+	wg    sync.WaitGroup
+
+	mu     sync.Mutex
+	queued map[string]bool
+}
+
+func NewDispatcher(store store.Repository, processor *Processor, logger *logrus.Logger, opts DispatcherOptions) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 1
+	}
+	// Submit never waits, so the queue needs room for at least one transfer.
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1
+	}
+
+	return &Dispatcher{
+		store:     store,
+		processor: processor,
+		logger:    logger,
+		opts:      opts,
+		queue:     make(chan string, opts.QueueSize),
+		queued:    make(map[string]bool),
+	}
+}
+
+// Start launches the workers for the lifetime of ctx and requeues transfers
+// left pending by a previous run.
+func (d *Dispatcher) Start(ctx context.Context) {
+	for i := 0; i < d.opts.Workers; i++ {
+		d.wg.Add(1)
+		go d.work(ctx)
+	}
+
+	go func() {
+		if _, err := d.Recover(ctx); err != nil {
+			d.logger.WithError(err).Error("Failed to requeue pending transfers")
+		}
+	}()
+}
+
+// Wait blocks until every worker has stopped after its context ended.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+var errQueueFull = fmt.Errorf("transfer queue is full")
+
+// Submit queues a pending transfer without waiting. If the queue is full the
+// transfer stays pending until the next Recover, which the server runs
+// periodically.
+func (d *Dispatcher) Submit(transactionID string) error {
+	d.mu.Lock()
+	if d.queued[transactionID] {
+		d.mu.Unlock()
+		return nil
+	}
+	d.queued[transactionID] = true
+	d.mu.Unlock()
+
+	select {
+	case d.queue <- transactionID:
+		return nil
+	default:
+		d.done(transactionID)
+		return errQueueFull
+	}
+}
+
+// Recover queues pending transfers in the store until the queue is full and
+// returns how many it queued. The rest wait for the next call.
+func (d *Dispatcher) Recover(ctx context.Context) (int, error) {
+	recovered := 0
+	for _, tx := range d.store.GetAllTransactions(ctx) {
+		if err := ctx.Err(); err != nil {
+			return recovered, err
+		}
+		if tx.Type != transaction.TransactionTypeTransfer || tx.Status != transaction.TransactionStatusPending {
+			continue
+		}
+		if err := d.Submit(tx.ID); err != nil {
+			break
+		}
+		recovered++
+	}
+	return recovered, nil
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case id := <-d.queue:
+			d.process(ctx, id)
+			d.done(id)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) done(transactionID string) {
+	d.mu.Lock()
+	delete(d.queued, transactionID)
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) process(ctx context.Context, transactionID string) {
+	log := d.logger.WithField("transaction_id", transactionID)
+
+	for attempt := 1; ; attempt++ {
+		err := d.store.Apply(ctx, func(uow store.UnitOfWork) error {
+			_, err := d.processor.Settle(uow, transactionID)
+			return err
+		})
+		if err == nil {
+			log.WithField("attempt", attempt).Info("Pending transfer completed")
+			return
+		}
+		if _, ok := err.(*errors.ErrTransactionFailed); ok {
+			// Settled or failed by someone else in the meantime.
+			return
+		}
+
+		if permanent(err) || attempt >= d.opts.MaxAttempts {
+			d.fail(ctx, transactionID, err)
+			return
+		}
+
+		log.WithError(err).WithField("attempt", attempt).Warn("Pending transfer failed, retrying")
+		select {
+		case <-time.After(d.opts.RetryBackoff * time.Duration(attempt)):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) fail(ctx context.Context, transactionID string, cause error) {
+	log := d.logger.WithError(cause).WithField("transaction_id", transactionID)
+
+	err := d.store.Apply(ctx, func(uow store.UnitOfWork) error {
+		_, err := d.processor.Fail(uow, transactionID, cause)
+		return err
+	})
+	if err != nil {
+		log.WithField("fail_error", err.Error()).Error("Failed to mark pending transfer failed")
+		return
+	}
+	log.Error("Pending transfer failed")
+}
+
+// permanent reports whether retrying a transfer that failed with err is
+// pointless. Insufficient funds, frozen accounts and storage errors may clear
+// up, so they are retried.
+func permanent(err error) bool {
+	switch err.(type) {
+	case *errors.ErrAccountNotFound, *errors.ErrTransactionNotFound, *errors.ErrAccountClosed,
+		*errors.ErrSameAccountTransfer, *errors.ErrCurrencyMismatch, *errors.ErrInvalidAmount,
+		*errors.ErrInvalidConversion:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,408 @@
+package payment
+
+import (
+	"fmt"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/ledger"
+	"banking-service/internal/limit"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+	"banking-service/pkg/money"
+)
+
+// Processor moves money between accounts inside a store unit of work. It is
+// shared by the API handlers and the background transfer workers so that
+// both go through the same validation and ledger postings.
+type Processor struct {
+	accountService     *account.Service
+	transactionService *transaction.Service
+	ledgerService      *ledger.Service
+
+	// limits are the default transaction limits for accounts that do not
+	// set their own.
+	limits []limit.Limit
+
+	// payees, when set, checks transfers against the paying account's
+	// registered beneficiaries.
+	payees *beneficiary.Service
+}
+
+func NewProcessor(accountService *account.Service, transactionService *transaction.Service, ledgerService *ledger.Service, limits []limit.Limit, payees *beneficiary.Service) *Processor {
+	return &Processor{
+		accountService:     accountService,
+		transactionService: transactionService,
+		ledgerService:      ledgerService,
+		limits:             limits,
+		payees:             payees,
+	}
+}
+
+// Record stores tx together with the journal entry that explains its balance
+// changes.
+func (p *Processor) Record(uow store.UnitOfWork, tx *transaction.Transaction) error {
+	entry, err := p.ledgerService.EntryForTransaction(tx)
+	if err != nil {
+		return err
+	}
+	if err := uow.PostEntry(entry); err != nil {
+		return err
+	}
+	return uow.StoreTransaction(tx)
+}
+
+// Limits returns the transaction limits that apply to acc.
+func (p *Processor) Limits(acc *account.Account) []limit.Limit {
+	return limit.Effective(p.limits, acc.Limits)
+}
+
+// CheckLimits returns *errors.ErrLimitExceeded if tx, made by acc, would
+// break one of the account's limits, measured against its history in the
+// store.
+func (p *Processor) CheckLimits(uow store.UnitOfWork, acc *account.Account, tx *transaction.Transaction) error {
+	for _, l := range p.Limits(acc) {
+		if l.Type != tx.Type {
+			continue
+		}
+
+		var history []*transaction.Transaction
+		if l.Period != limit.PeriodTransaction {
+			history = uow.ListAccountTransactions(acc.ID, l.Filter(tx.Timestamp))
+		}
+		if err := l.Check(acc.ID, tx, history); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckBeneficiary returns an error if tx, a transfer from fromAccount to
+// toAccount, is to a payee that is unverified or cooling off, or to an
+// unregistered account when payees must be registered. Otherwise unregistered
+// accounts are capped as if always cooling off. Transfers between accounts of
+// the same customer need no payee.
+func (p *Processor) CheckBeneficiary(uow store.UnitOfWork, fromAccount, toAccount *account.Account, tx *transaction.Transaction) error {
+	if p.payees == nil {
+		return nil
+	}
+
+	b := uow.FindBeneficiary(fromAccount.ID, toAccount.ID)
+	if b == nil {
+		if fromAccount.CustomerID != "" && fromAccount.CustomerID == toAccount.CustomerID {
+			return nil
+		}
+		if p.payees.Required() {
+			return &errors.ErrBeneficiaryRequired{AccountID: fromAccount.ID, PayeeAccountID: toAccount.ID}
+		}
+	}
+
+	var sent int64
+	if b == nil || b.CoolingOff(tx.Timestamp) {
+		history := uow.ListAccountTransactions(fromAccount.ID, transaction.Filter{
+			Types:    []transaction.TransactionType{transaction.TransactionTypeTransfer},
+			Statuses: []transaction.TransactionStatus{transaction.TransactionStatusPending, transaction.TransactionStatusCompleted},
+			From:     p.payees.CountsFrom(tx.Timestamp),
+		})
+		for _, prior := range history {
+			if prior.FromAccountID == fromAccount.ID && prior.ToAccountID == toAccount.ID {
+				sent += prior.Amount
+			}
+		}
+	}
+	if b == nil {
+		return p.payees.CheckUnregistered(toAccount.ID, tx.Amount, sent)
+	}
+	return p.payees.Check(b, tx.Amount, sent, tx.Timestamp)
+}
+
+// RecordFee records fee, if there is one, as a fee transaction linked to tx,
+// the withdrawal or transfer it was charged on. The fee must already have
+// been taken from the account.
+func (p *Processor) RecordFee(uow store.UnitOfWork, tx *transaction.Transaction, fee int64) error {
+	if fee == 0 {
+		return nil
+	}
+
+	accountID, reason := tx.AccountID, "withdrawal fee"
+	if tx.Type == transaction.TransactionTypeTransfer {
+		accountID, reason = tx.FromAccountID, "transfer fee"
+	}
+
+	feeTx := p.transactionService.CreateFeeTransaction(accountID, fee, tx.Currency, reason)
+	feeTx.RelatedTransactionID = tx.ID
+	tx.FeeTransactionID = feeTx.ID
+	return p.Record(uow, feeTx)
+}
+
+// Transfer moves req.Amount between the two accounts and records the
+// completed transaction.
+func (p *Processor) Transfer(uow store.UnitOfWork, req transaction.TransferRequest, now time.Time) (*transaction.Transaction, error) {
+	fromAccount, toAccount, conv, err := p.prepare(uow, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, fee, err := p.move(fromAccount, toAccount, conv, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckLimits(uow, fromAccount, tx); err != nil {
+		return nil, err
+	}
+	if err := p.CheckBeneficiary(uow, fromAccount, toAccount, tx); err != nil {
+		return nil, err
+	}
+	if err := p.consumeQuote(uow, req, tx, now); err != nil {
+		return nil, err
+	}
+	if err := p.RecordFee(uow, tx, fee); err != nil {
+		return nil, err
+	}
+	return tx, p.Record(uow, tx)
+}
+
+// Move shifts req.Amount between an account and one of its pots and records
+// the move.
+func (p *Processor) Move(uow store.UnitOfWork, req transaction.MoveRequest) (*transaction.Transaction, error) {
+	fromAccount, err := uow.GetAccount(req.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	toAccount, err := uow.GetAccount(req.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.accountService.Move(fromAccount, toAccount, req.Amount); err != nil {
+		return nil, err
+	}
+
+	tx := p.transactionService.CreateMoveTransaction(fromAccount.ID, toAccount.ID, req.Amount, fromAccount.CurrentCurrency())
+	return tx, p.Record(uow, tx)
+}
+
+// SweepPot moves what is left in the pot back to its parent, recording the
+// move, and closes the pot.
+func (p *Processor) SweepPot(uow store.UnitOfWork, pot, parent *account.Account) error {
+	swept, err := p.accountService.SweepPot(pot, parent)
+	if err != nil || swept == 0 {
+		return err
+	}
+	return p.Record(uow, p.transactionService.CreateMoveTransaction(pot.ID, parent.ID, swept, pot.CurrentCurrency()))
+}
+
+// Close closes acc, an account staged in uow. A pot's balance is first moved
+// back to its parent; a parent's open pots are first swept into it and
+// closed, so the parent only closes if its balance and its pots' come to
+// zero. If acc cannot be closed, the unit of work is meant to be discarded,
+// sweeps included.
+func (p *Processor) Close(uow store.UnitOfWork, acc *account.Account) error {
+	if acc.IsPot() {
+		if acc.Balance > 0 {
+			if _, err := p.Move(uow, transaction.MoveRequest{FromAccountID: acc.ID, ToAccountID: acc.ParentID, Amount: acc.Balance}); err != nil {
+				return err
+			}
+		}
+		return p.accountService.Close(acc)
+	}
+
+	for _, listed := range uow.ListPots(acc.ID) {
+		if listed.CurrentStatus() == account.StatusClosed {
+			continue
+		}
+		pot, err := uow.GetAccount(listed.ID)
+		if err != nil {
+			return err
+		}
+		if err := p.SweepPot(uow, pot, acc); err != nil {
+			return err
+		}
+	}
+	return p.accountService.Close(acc)
+}
+
+// Accept validates req and records it as a pending transfer without moving
+// any money; Settle completes it later. A quote is consumed on acceptance so
+// the locked rate cannot expire while the transfer waits.
+func (p *Processor) Accept(uow store.UnitOfWork, req transaction.TransferRequest, now time.Time) (*transaction.Transaction, error) {
+	fromAccount, toAccount, conv, err := p.prepare(uow, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dry-run the transfer on copies to reject anything that cannot succeed
+	// as it stands, such as a closed destination.
+	fromCopy, toCopy := *fromAccount, *toAccount
+	tx, _, err := p.move(&fromCopy, &toCopy, conv, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckLimits(uow, fromAccount, tx); err != nil {
+		return nil, err
+	}
+	if err := p.CheckBeneficiary(uow, fromAccount, toAccount, tx); err != nil {
+		return nil, err
+	}
+
+	tx.Status = transaction.TransactionStatusPending
+	if err := p.consumeQuote(uow, req, tx, now); err != nil {
+		return nil, err
+	}
+	return tx, uow.StoreTransaction(tx)
+}
+
+// Settle moves the money for a pending transfer and marks it completed.
+func (p *Processor) Settle(uow store.UnitOfWork, transactionID string) (*transaction.Transaction, error) {
+	pending, err := p.pending(uow, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := uow.GetAccount(pending.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	toAccount, err := uow.GetAccount(pending.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fee int64
+	if pending.IsConverted() {
+		conv := money.Conversion{
+			Source: money.Money{Amount: pending.Amount, Currency: pending.Currency},
+			Target: money.Money{Amount: pending.TargetAmount, Currency: pending.TargetCurrency},
+			Rate:   pending.Rate,
+		}
+		fee, err = p.accountService.TransferConverted(fromAccount, toAccount, conv)
+	} else {
+		fee, err = p.accountService.Transfer(fromAccount, toAccount, pending.Amount)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := p.RecordFee(uow, pending, fee); err != nil {
+		return nil, err
+	}
+
+	pending.Status = transaction.TransactionStatusCompleted
+	entry, err := p.ledgerService.EntryForTransaction(pending)
+	if err != nil {
+		return nil, err
+	}
+	if err := uow.PostEntry(entry); err != nil {
+		return nil, err
+	}
+	return pending, uow.UpdateTransaction(pending)
+}
+
+// Fail marks a pending transfer failed because of cause.
+func (p *Processor) Fail(uow store.UnitOfWork, transactionID string, cause error) (*transaction.Transaction, error) {
+	pending, err := p.pending(uow, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	code := errors.Code(cause)
+	if code == "" {
+		code = "processing_error"
+	}
+	p.transactionService.MarkFailed(pending, code, cause.Error())
+	return pending, uow.UpdateTransaction(pending)
+}
+
+func (p *Processor) pending(uow store.UnitOfWork, transactionID string) (*transaction.Transaction, error) {
+	tx, err := uow.GetTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Type != transaction.TransactionTypeTransfer || tx.Status != transaction.TransactionStatusPending {
+		return nil, &errors.ErrTransactionFailed{
+			TransactionID: tx.ID,
+			Reason:        fmt.Sprintf("%s %s is not a pending transfer", tx.Status, tx.Type),
+		}
+	}
+	return tx, nil
+}
+
+// prepare loads both accounts and looks up the quoted currency conversion, if
+// the transfer needs one. A nil conversion means a same-currency transfer.
+func (p *Processor) prepare(uow store.UnitOfWork, req transaction.TransferRequest) (*account.Account, *account.Account, *money.Conversion, error) {
+	fromAccount, err := uow.GetAccount(req.FromAccountID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	toAccount, err := uow.GetAccount(req.ToAccountID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := p.accountService.ValidateCurrency(fromAccount, req.Currency); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if req.QuoteID == "" {
+		if fromAccount.CurrentCurrency() != toAccount.CurrentCurrency() {
+			return nil, nil, nil, &errors.ErrInvalidConversion{
+				Reason: fmt.Sprintf("a transfer from %s to %s needs an FX quote", fromAccount.CurrentCurrency(), toAccount.CurrentCurrency()),
+			}
+		}
+		return fromAccount, toAccount, nil, nil
+	}
+
+	conv, err := p.quotedConversion(uow, fromAccount, toAccount, req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return fromAccount, toAccount, &conv, nil
+}
+
+// move transfers the money and returns the transaction for it together with
+// the fee charged, which is not recorded yet.
+func (p *Processor) move(fromAccount, toAccount *account.Account, conv *money.Conversion, amount int64) (*transaction.Transaction, int64, error) {
+	if conv == nil {
+		fee, err := p.accountService.Transfer(fromAccount, toAccount, amount)
+		if err != nil {
+			return nil, 0, err
+		}
+		return p.transactionService.CreateTransferTransaction(fromAccount.ID, toAccount.ID, amount, fromAccount.CurrentCurrency()), fee, nil
+	}
+
+	fee, err := p.accountService.TransferConverted(fromAccount, toAccount, *conv)
+	if err != nil {
+		return nil, 0, err
+	}
+	return p.transactionService.CreateConvertedTransferTransaction(fromAccount.ID, toAccount.ID, *conv), fee, nil
+}
+
+func (p *Processor) consumeQuote(uow store.UnitOfWork, req transaction.TransferRequest, tx *transaction.Transaction, now time.Time) error {
+	if req.QuoteID == "" {
+		return nil
+	}
+
+	tx.QuoteID = req.QuoteID
+	return uow.ConsumeQuote(req.QuoteID, tx.ID, now)
+}
+
+// quotedConversion returns the conversion locked by the quote a transfer
+// refers to, provided the quote matches the transfer's accounts and amount.
+func (p *Processor) quotedConversion(uow store.UnitOfWork, fromAccount, toAccount *account.Account, req transaction.TransferRequest) (money.Conversion, error) {
+	quote, err := uow.GetQuote(req.QuoteID)
+	if err != nil {
+		return money.Conversion{}, err
+	}
+
+	if quote.FromCurrency != fromAccount.CurrentCurrency() || quote.ToCurrency != toAccount.CurrentCurrency() {
+		return money.Conversion{}, &errors.ErrInvalidConversion{
+			Reason: fmt.Sprintf("quote %s is for %s to %s, transfer is %s to %s", quote.ID, quote.FromCurrency, quote.ToCurrency, fromAccount.CurrentCurrency(), toAccount.CurrentCurrency()),
+		}
+	}
+	if quote.SourceAmount != req.Amount {
+		return money.Conversion{}, &errors.ErrInvalidConversion{
+			Reason: fmt.Sprintf("quote %s is for amount %d, transfer is for %d", quote.ID, quote.SourceAmount, req.Amount),
+		}
+	}
+
+	return quote.Conversion(), nil
+}
@@ -0,0 +1,439 @@
+package payment
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"banking-service/internal/account"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/ledger"
+	"banking-service/internal/limit"
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+func newTestStore(t *testing.T, balances map[string]int64) *store.MemoryStore {
+	t.Helper()
+
+	repo := store.NewMemoryStore()
+	for id, balance := range balances {
+		acc := &account.Account{ID: id, CustomerName: id, Balance: balance, Status: account.StatusActive}
+		if err := repo.CreateAccount(context.Background(), acc); err != nil {
+			t.Fatalf("CreateAccount() error = %v", err)
+		}
+	}
+	return repo
+}
+
+func newTestProcessor() *Processor {
+	return NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, nil)
+}
+
+func TestAcceptAndSettle(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestStore(t, map[string]int64{"from": 1000, "to": 0})
+	processor := newTestProcessor()
+	req := transaction.TransferRequest{FromAccountID: "from", ToAccountID: "to", Amount: 600}
+
+	var pending *transaction.Transaction
+	err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		pending, err = processor.Accept(uow, req, time.Now())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	if pending.Status != transaction.TransactionStatusPending {
+		t.Errorf("Accept() status = %v, want pending", pending.Status)
+	}
+	if from, _ := repo.GetAccount(ctx, "from"); from.Balance != 1000 {
+		t.Errorf("Accept() moved money: balance = %v, want 1000", from.Balance)
+	}
+
+	err = repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		_, err := processor.Settle(uow, pending.ID)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Settle() error = %v", err)
+	}
+
+	settled, _ := repo.GetTransaction(ctx, pending.ID)
+	from, _ := repo.GetAccount(ctx, "from")
+	to, _ := repo.GetAccount(ctx, "to")
+	if settled.Status != transaction.TransactionStatusCompleted || from.Balance != 400 || to.Balance != 600 {
+		t.Errorf("Settle() = %v with balances %v/%v, want completed with 400/600", settled.Status, from.Balance, to.Balance)
+	}
+}
+
+func TestAcceptRejectsInvalidTransfer(t *testing.T) {
+	repo := newTestStore(t, map[string]int64{"from": 1000})
+	processor := newTestProcessor()
+
+	err := repo.Apply(context.Background(), func(uow store.UnitOfWork) error {
+		_, err := processor.Accept(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: "from", Amount: 100}, time.Now())
+		return err
+	})
+	if err == nil {
+		t.Error("Accept() expected error for same-account transfer")
+	}
+}
+
+func TestTransferNeedsQuoteAcrossCurrencies(t *testing.T) {
+	ctx := context.Background()
+	repo := store.NewMemoryStore()
+	repo.CreateAccount(ctx, &account.Account{ID: "inr", CustomerName: "inr", Balance: 1000, Currency: "INR", Status: account.StatusActive})
+	repo.CreateAccount(ctx, &account.Account{ID: "usd", CustomerName: "usd", Currency: "USD", Status: account.StatusActive})
+	processor := newTestProcessor()
+
+	err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		_, err := processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "inr", ToAccountID: "usd", Amount: 100}, time.Now())
+		return err
+	})
+	if _, ok := err.(*errors.ErrInvalidConversion); !ok {
+		t.Errorf("Transfer() error = %v, want *errors.ErrInvalidConversion without a quote", err)
+	}
+}
+
+func TestDispatcherFailsTransfer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := newTestStore(t, map[string]int64{"from": 1000, "to": 0})
+	processor := newTestProcessor()
+
+	var pending *transaction.Transaction
+	err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		pending, err = processor.Accept(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: "to", Amount: 800}, time.Now())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+
+	// Drain the account so the transfer can never go through.
+	drained, _ := repo.GetAccount(ctx, "from")
+	drained.Balance = 0
+	if err := repo.UpdateAccount(ctx, drained); err != nil {
+		t.Fatalf("UpdateAccount() error = %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dispatcher := NewDispatcher(repo, processor, logger, DispatcherOptions{Workers: 1, MaxAttempts: 2, RetryBackoff: time.Millisecond})
+	dispatcher.Start(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		tx, _ := repo.GetTransaction(ctx, pending.ID)
+		if tx.Status == transaction.TransactionStatusFailed {
+			if tx.FailureCode != "insufficient_funds" || tx.FailureReason == "" {
+				t.Errorf("failed transfer code = %q, reason = %q, want insufficient_funds with a reason", tx.FailureCode, tx.FailureReason)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("transfer was not marked failed")
+}
+
+func TestDispatcherSubmitWhenFull(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	dispatcher := NewDispatcher(store.NewMemoryStore(), newTestProcessor(), logger, DispatcherOptions{QueueSize: 1})
+
+	// No workers are running, so the second transfer finds the queue full
+	// and Submit returns instead of waiting.
+	if err := dispatcher.Submit("tx-1"); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := dispatcher.Submit("tx-2"); err != errQueueFull {
+		t.Errorf("Submit() on a full queue error = %v, want errQueueFull", err)
+	}
+	if err := dispatcher.Submit("tx-1"); err != nil {
+		t.Errorf("Submit() of a queued transfer error = %v", err)
+	}
+}
+
+type flatFee int64
+
+func (f flatFee) Fee(acc *account.Account, op account.Operation, amount int64) int64 {
+	return int64(f)
+}
+
+func TestTransferChargesFee(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestStore(t, map[string]int64{"from": 1000, "to": 0})
+	processor := NewProcessor(account.NewServiceWithFees(flatFee(25)), transaction.NewService(), ledger.NewService(), nil, nil)
+
+	var tx *transaction.Transaction
+	err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		tx, err = processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: "to", Amount: 600}, time.Now())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+
+	from, _ := repo.GetAccount(ctx, "from")
+	to, _ := repo.GetAccount(ctx, "to")
+	if from.Balance != 375 || to.Balance != 600 {
+		t.Errorf("Transfer() balances = %v/%v, want 375/600", from.Balance, to.Balance)
+	}
+
+	feeTx, err := repo.GetTransaction(ctx, tx.FeeTransactionID)
+	if err != nil {
+		t.Fatalf("GetTransaction(fee) error = %v", err)
+	}
+	if feeTx.Type != transaction.TransactionTypeFee || feeTx.Amount != 25 || feeTx.AccountID != "from" || feeTx.RelatedTransactionID != tx.ID {
+		t.Errorf("fee transaction = %+v, want a fee of 25 on from linked to %s", feeTx, tx.ID)
+	}
+
+	// The fee counts towards what the account must be able to pay.
+	err = repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		_, err := processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: "to", Amount: 375}, time.Now())
+		return err
+	})
+	if err == nil {
+		t.Error("Transfer() expected error when the fee is not covered")
+	}
+}
+
+func TestTransferLimits(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestStore(t, map[string]int64{"from": 10000, "to": 0})
+	processor := NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), []limit.Limit{
+		{Type: transaction.TransactionTypeTransfer, Period: limit.PeriodDaily, MaxAmount: 1000, MaxCount: 2},
+	}, nil)
+
+	transfer := func(amount int64) error {
+		return repo.Apply(ctx, func(uow store.UnitOfWork) error {
+			_, err := processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: "to", Amount: amount}, time.Now())
+			return err
+		})
+	}
+
+	if err := transfer(700); err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+
+	err := transfer(400)
+	exceeded, ok := err.(*errors.ErrLimitExceeded)
+	if !ok {
+		t.Fatalf("Transfer() error = %v, want *errors.ErrLimitExceeded", err)
+	}
+	if exceeded.Measure != "amount" || exceeded.Remaining != 300 {
+		t.Errorf("Transfer() exceeded %s with %d remaining, want amount with 300", exceeded.Measure, exceeded.Remaining)
+	}
+
+	if err := transfer(300); err != nil {
+		t.Fatalf("Transfer() within the remaining allowance error = %v", err)
+	}
+	if _, ok := transfer(1).(*errors.ErrLimitExceeded); !ok {
+		t.Error("Transfer() expected *errors.ErrLimitExceeded once the daily amount is used up")
+	}
+
+	// The incoming side of a transfer does not count against the payee.
+	own := []limit.Limit{{Type: transaction.TransactionTypeTransfer, Period: limit.PeriodDaily, MaxCount: 1}}
+	err = repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		acc, err := uow.GetAccount("to")
+		if err != nil {
+			return err
+		}
+		acc.Limits = own
+		_, err = processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "to", ToAccountID: "from", Amount: 100}, time.Now())
+		return err
+	})
+	if err != nil {
+		t.Errorf("Transfer() from the payee error = %v", err)
+	}
+}
+
+func TestTransferBeneficiaries(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestStore(t, map[string]int64{"from": 10000, "to": 0, "own": 0})
+	processor := NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, beneficiary.NewService(24*time.Hour, 1000, true))
+	now := time.Now()
+
+	transfer := func(to string, amount int64) error {
+		return repo.Apply(ctx, func(uow store.UnitOfWork) error {
+			_, err := processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: to, Amount: amount}, time.Now())
+			return err
+		})
+	}
+	save := func(b *beneficiary.Beneficiary) {
+		if err := repo.Apply(ctx, func(uow store.UnitOfWork) error { return uow.SaveBeneficiary(b) }); err != nil {
+			t.Fatalf("SaveBeneficiary() error = %v", err)
+		}
+	}
+
+	if _, ok := transfer("to", 100).(*errors.ErrBeneficiaryRequired); !ok {
+		t.Error("Transfer() expected *errors.ErrBeneficiaryRequired for an unregistered payee")
+	}
+
+	payee := &beneficiary.Beneficiary{ID: "ben-1", AccountID: "from", PayeeAccountID: "to", CreatedAt: now.Add(-time.Minute), CoolingOffUntil: now.Add(time.Hour)}
+	save(payee)
+	if _, ok := transfer("to", 100).(*errors.ErrBeneficiaryNotVerified); !ok {
+		t.Error("Transfer() expected *errors.ErrBeneficiaryNotVerified before the payee is verified")
+	}
+
+	payee.NameMatch = beneficiary.NameMatchExact
+	save(payee)
+	if err := transfer("to", 700); err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	err := transfer("to", 400)
+	coolingOff, ok := err.(*errors.ErrBeneficiaryCoolingOff)
+	if !ok {
+		t.Fatalf("Transfer() error = %v, want *errors.ErrBeneficiaryCoolingOff", err)
+	}
+	if coolingOff.Remaining != 300 {
+		t.Errorf("Transfer() cooling off with %d remaining, want 300", coolingOff.Remaining)
+	}
+
+	payee.CoolingOffUntil = now.Add(-time.Second)
+	save(payee)
+	if err := transfer("to", 5000); err != nil {
+		t.Errorf("Transfer() after cooling off error = %v", err)
+	}
+
+	// Moving money between a customer's own accounts needs no payee.
+	err = repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		for _, id := range []string{"from", "own"} {
+			acc, err := uow.GetAccount(id)
+			if err != nil {
+				return err
+			}
+			acc.CustomerID = "cust-1"
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if err := transfer("own", 100); err != nil {
+		t.Errorf("Transfer() to an own account error = %v", err)
+	}
+}
+
+func TestTransferUnregisteredPayees(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestStore(t, map[string]int64{"from": 10000, "to": 0})
+	processor := NewProcessor(account.NewService(), transaction.NewService(), ledger.NewService(), nil, beneficiary.NewService(24*time.Hour, 1000, false))
+	now := time.Now()
+
+	transfer := func(amount int64) error {
+		return repo.Apply(ctx, func(uow store.UnitOfWork) error {
+			_, err := processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: "to", Amount: amount}, time.Now())
+			return err
+		})
+	}
+
+	// Without registration, payees are capped as if always cooling off.
+	if err := transfer(600); err != nil {
+		t.Fatalf("Transfer() to an unregistered payee error = %v", err)
+	}
+	err := transfer(500)
+	coolingOff, ok := err.(*errors.ErrBeneficiaryCoolingOff)
+	if !ok {
+		t.Fatalf("Transfer() error = %v, want *errors.ErrBeneficiaryCoolingOff", err)
+	}
+	if coolingOff.Remaining != 400 || coolingOff.PayeeAccountID != "to" {
+		t.Errorf("Transfer() capped with %d remaining to %s, want 400 to to", coolingOff.Remaining, coolingOff.PayeeAccountID)
+	}
+
+	// Registering the payee counts what was already sent, and removing it
+	// again does not lift the cap.
+	payee := &beneficiary.Beneficiary{ID: "ben-1", AccountID: "from", PayeeAccountID: "to", NameMatch: beneficiary.NameMatchExact, CreatedAt: now, CoolingOffUntil: now.Add(24 * time.Hour)}
+	if err := repo.Apply(ctx, func(uow store.UnitOfWork) error { return uow.SaveBeneficiary(payee) }); err != nil {
+		t.Fatalf("SaveBeneficiary() error = %v", err)
+	}
+	if _, ok := transfer(500).(*errors.ErrBeneficiaryCoolingOff); !ok {
+		t.Error("Transfer() expected *errors.ErrBeneficiaryCoolingOff after registering the payee")
+	}
+	if err := transfer(300); err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if err := repo.Apply(ctx, func(uow store.UnitOfWork) error { return uow.DeleteBeneficiary(payee.ID) }); err != nil {
+		t.Fatalf("DeleteBeneficiary() error = %v", err)
+	}
+	if _, ok := transfer(200).(*errors.ErrBeneficiaryCoolingOff); !ok {
+		t.Error("Transfer() expected *errors.ErrBeneficiaryCoolingOff after removing the payee")
+	}
+	if err := transfer(100); err != nil {
+		t.Errorf("Transfer() within the cap error = %v", err)
+	}
+}
+
+func TestCloseWithPots(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestStore(t, map[string]int64{"parent": 1000, "other": 0})
+	processor := newTestProcessor()
+
+	for id, balance := range map[string]int64{"pot-1": 300, "pot-2": 200} {
+		pot := &account.Account{ID: id, CustomerName: "parent", Balance: balance, Status: account.StatusActive, Type: account.TypeSavings, ParentID: "parent"}
+		if err := repo.CreateAccount(ctx, pot); err != nil {
+			t.Fatalf("CreateAccount() error = %v", err)
+		}
+	}
+	closeAccount := func(id string) error {
+		return repo.Apply(ctx, func(uow store.UnitOfWork) error {
+			acc, err := uow.GetAccount(id)
+			if err != nil {
+				return err
+			}
+			return processor.Close(uow, acc)
+		})
+	}
+
+	// The parent and its pots hold 1500 between them, so nothing is swept.
+	err := closeAccount("parent")
+	notZero, ok := err.(*errors.ErrAccountBalanceNotZero)
+	if !ok {
+		t.Fatalf("Close() error = %v, want *errors.ErrAccountBalanceNotZero", err)
+	}
+	if notZero.Balance != 1500 {
+		t.Errorf("Close() balance = %d, want 1500 including the pots", notZero.Balance)
+	}
+	if pot, _ := repo.GetAccount(ctx, "pot-1"); pot.Balance != 300 || pot.CurrentStatus() != account.StatusActive {
+		t.Errorf("Close() left pot-1 with %d and %v, want 300 and active", pot.Balance, pot.CurrentStatus())
+	}
+
+	// Closing a pot moves its balance back to the parent.
+	if err := closeAccount("pot-2"); err != nil {
+		t.Fatalf("Close() pot error = %v", err)
+	}
+	if parent, _ := repo.GetAccount(ctx, "parent"); parent.Balance != 1200 {
+		t.Errorf("Close() pot left the parent with %d, want 1200", parent.Balance)
+	}
+
+	// Once the parent's overdraft cancels out its pots, it closes with them.
+	err = repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		parent, err := uow.GetAccount("parent")
+		if err != nil {
+			return err
+		}
+		parent.OverdraftLimit = 500
+		_, err = processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "parent", ToAccountID: "other", Amount: 1500}, time.Now())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if err := closeAccount("parent"); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for _, id := range []string{"parent", "pot-1"} {
+		if acc, _ := repo.GetAccount(ctx, id); acc.Balance != 0 || acc.CurrentStatus() != account.StatusClosed {
+			t.Errorf("Close() left %s with %d and %v, want 0 and closed", id, acc.Balance, acc.CurrentStatus())
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package payment
+
+import (
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+// Reverse returns req.Amount of a completed transaction, or all of what has
+// not been refunded yet, by moving it back between the original accounts.
+// The original is marked reversed once nothing is left to return.
+func (p *Processor) Reverse(uow store.UnitOfWork, originalID string, req transaction.ReverseRequest) (*transaction.Transaction, error) {
+	original, err := uow.GetTransaction(originalID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := original.Amount - original.RefundedAmount
+	switch {
+	case original.Status == transaction.TransactionStatusReversed:
+		return nil, &errors.ErrAlreadyReversed{TransactionID: original.ID}
+	case original.Status != transaction.TransactionStatusCompleted:
+		return nil, &errors.ErrTransactionNotReversible{TransactionID: original.ID, Reason: "it is " + string(original.Status)}
+	case original.IsReversal():
+		return nil, &errors.ErrTransactionNotReversible{TransactionID: original.ID, Reason: "it is itself a " + string(original.Type)}
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount < 0 {
+		return nil, &errors.ErrInvalidAmount{Amount: amount}
+	}
+	if amount > remaining {
+		return nil, &errors.ErrRefundExceedsOriginal{TransactionID: original.ID, Remaining: remaining, Amount: amount}
+	}
+	if original.IsConverted() && amount != original.Amount {
+		return nil, &errors.ErrTransactionNotReversible{TransactionID: original.ID, Reason: "currency conversions can only be reversed in full"}
+	}
+
+	reversal := p.transactionService.CreateReversalTransaction(original, amount, req.Reason)
+	if err := p.returnFunds(uow, original, reversal); err != nil {
+		return nil, err
+	}
+
+	entry, err := p.ledgerService.ReversalEntry(original, reversal)
+	if err != nil {
+		return nil, err
+	}
+	if err := uow.PostEntry(entry); err != nil {
+		return nil, err
+	}
+	if err := uow.StoreTransaction(reversal); err != nil {
+		return nil, err
+	}
+
+	p.transactionService.MarkRefunded(original, reversal)
+	return reversal, uow.UpdateTransaction(original)
+}
+
+func (p *Processor) returnFunds(uow store.UnitOfWork, original, reversal *transaction.Transaction) error {
+	switch original.Type {
+	case transaction.TransactionTypeDeposit:
+		acc, err := uow.GetAccount(original.AccountID)
+		if err != nil {
+			return err
+		}
+		return p.accountService.ReturnDeposit(acc, reversal.Amount)
+	case transaction.TransactionTypeWithdrawal:
+		acc, err := uow.GetAccount(original.AccountID)
+		if err != nil {
+			return err
+		}
+		return p.accountService.Deposit(acc, reversal.Amount)
+	case transaction.TransactionTypeTransfer:
+		fromAccount, err := uow.GetAccount(original.FromAccountID)
+		if err != nil {
+			return err
+		}
+		toAccount, err := uow.GetAccount(original.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		credit := reversal.Amount
+		debit := reversal.Amount
+		if original.IsConverted() {
+			debit = original.TargetAmount
+		}
+		return p.accountService.ReturnTransfer(fromAccount, toAccount, debit, credit)
+	default:
+		return &errors.ErrTransactionNotReversible{TransactionID: original.ID, Reason: "unsupported type " + string(original.Type)}
+	}
+}
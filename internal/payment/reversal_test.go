@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/internal/store"
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+func TestReverse(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestStore(t, map[string]int64{"from": 1000, "to": 0})
+	processor := newTestProcessor()
+
+	var original *transaction.Transaction
+	err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+		var err error
+		original, err = processor.Transfer(uow, transaction.TransferRequest{FromAccountID: "from", ToAccountID: "to", Amount: 600}, time.Now())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+
+	reverse := func(amount int64) (*transaction.Transaction, error) {
+		var reversal *transaction.Transaction
+		err := repo.Apply(ctx, func(uow store.UnitOfWork) error {
+			var err error
+			reversal, err = processor.Reverse(uow, original.ID, transaction.ReverseRequest{Amount: amount})
+			return err
+		})
+		return reversal, err
+	}
+
+	refund, err := reverse(200)
+	if err != nil {
+		t.Fatalf("Reverse(200) error = %v", err)
+	}
+	if refund.Type != transaction.TransactionTypeRefund || refund.OriginalTransactionID != original.ID {
+		t.Errorf("Reverse(200) = %v of %v, want refund of %v", refund.Type, refund.OriginalTransactionID, original.ID)
+	}
+
+	if _, err := reverse(500); err == nil {
+		t.Error("Reverse(500) expected error for more than the remaining amount")
+	} else if _, ok := err.(*errors.ErrRefundExceedsOriginal); !ok {
+		t.Errorf("Reverse(500) error = %T, want *errors.ErrRefundExceedsOriginal", err)
+	}
+
+	if _, err := reverse(0); err != nil {
+		t.Fatalf("Reverse(0) error = %v", err)
+	}
+
+	stored, _ := repo.GetTransaction(ctx, original.ID)
+	if stored.Status != transaction.TransactionStatusReversed || stored.RefundedAmount != 600 || len(stored.ReversalIDs) != 2 {
+		t.Errorf("original = %v refunded %v by %v, want reversed refunded 600 by two", stored.Status, stored.RefundedAmount, stored.ReversalIDs)
+	}
+
+	from, _ := repo.GetAccount(ctx, "from")
+	to, _ := repo.GetAccount(ctx, "to")
+	if from.Balance != 1000 || to.Balance != 0 {
+		t.Errorf("balances = %v/%v, want 1000/0", from.Balance, to.Balance)
+	}
+
+	if _, err := reverse(0); err == nil {
+		t.Error("Reverse() expected error for already reversed transaction")
+	} else if _, ok := err.(*errors.ErrAlreadyReversed); !ok {
+		t.Errorf("Reverse() error = %T, want *errors.ErrAlreadyReversed", err)
+	}
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Clock lets jobs and the services they drive be tested against a fixed or
+// manually advanced time.
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+type JobFunc func(ctx context.Context, now time.Time) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler runs background jobs at fixed intervals for the lifetime of the
+// context passed to Start.
+type Scheduler struct {
+	clock  Clock
+	logger *logrus.Logger
+	jobs   []job
+	wg     sync.WaitGroup
+}
+
+func New(clock Clock, logger *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// Every registers fn to run once per interval. Jobs with a non-positive
+// interval are ignored, which lets callers disable them from config.
+func (s *Scheduler) Every(name string, interval time.Duration, fn JobFunc) {
+	if interval <= 0 {
+		return
+	}
+
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: fn})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Wait blocks until every job loop has stopped after its context ended.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// RunOnce runs every registered job immediately, in registration order.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	for _, j := range s.jobs {
+		s.runJob(ctx, j)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runJob(ctx, j)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, j job) {
+	start := s.clock.Now()
+	if err := j.run(ctx, start); err != nil {
+		s.logger.WithError(err).WithField("job", j.name).Error("Scheduled job failed")
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"job":      j.name,
+		"duration": s.clock.Now().Sub(start).String(),
+	}).Debug("Scheduled job completed")
+}
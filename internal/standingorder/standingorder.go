@@ -0,0 +1,289 @@
+package standingorder
+
+import (
+	"strconv"
+	"time"
+
+	"banking-service/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+type Frequency string
+
+const (
+	FrequencyOnce    Frequency = "once"
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+)
+
+func ValidFrequency(f Frequency) bool {
+	switch f {
+	case FrequencyOnce, FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+
+	// StatusFailed means the order stopped early because it can never
+	// succeed, for example because one of its accounts was closed.
+	StatusFailed Status = "failed"
+)
+
+type ExecutionStatus string
+
+const (
+	ExecutionSucceeded ExecutionStatus = "succeeded"
+	ExecutionFailed    ExecutionStatus = "failed"
+
+	// ExecutionRetrying is a failed attempt that will be tried again.
+	ExecutionRetrying ExecutionStatus = "retrying"
+)
+
+// Execution is one attempt at paying one occurrence of a standing order.
+type Execution struct {
+	ScheduledFor  time.Time       `json:"scheduled_for"`
+	AttemptedAt   time.Time       `json:"attempted_at"`
+	Attempt       int             `json:"attempt"`
+	Status        ExecutionStatus `json:"status"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	FailureCode   string          `json:"failure_code,omitempty"`
+	FailureReason string          `json:"failure_reason,omitempty"`
+}
+
+// StandingOrder transfers Amount from one account to another on a schedule:
+// once at StartAt, or every day, week or month from StartAt until EndAt or
+// until MaxExecutions occurrences have been paid or given up on.
+//
+// Occurrences counts the occurrences already dealt with and NextRunAt is when
+// the next attempt is due, which is later than the occurrence itself while
+// an attempt is being retried.
+type StandingOrder struct {
+	ID            string      `json:"id"`
+	FromAccountID string      `json:"from_account_id"`
+	ToAccountID   string      `json:"to_account_id"`
+	Amount        int64       `json:"amount"`
+	Currency      string      `json:"currency"`
+	Reference     string      `json:"reference,omitempty"`
+	Frequency     Frequency   `json:"frequency"`
+	StartAt       time.Time   `json:"start_at"`
+	EndAt         *time.Time  `json:"end_at,omitempty"`
+	MaxExecutions int         `json:"max_executions,omitempty"`
+	Status        Status      `json:"status"`
+	NextRunAt     *time.Time  `json:"next_run_at,omitempty"`
+	Occurrences   int         `json:"occurrences"`
+	Attempts      int         `json:"attempts,omitempty"`
+	History       []Execution `json:"history,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// Due reports whether an active order has an attempt due at or before now.
+func (o *StandingOrder) Due(now time.Time) bool {
+	return o.Status == StatusActive && o.NextRunAt != nil && !now.Before(*o.NextRunAt)
+}
+
+// ScheduledFor returns when the occurrence currently being paid was due.
+func (o *StandingOrder) ScheduledFor() time.Time {
+	return o.occurrence(o.Occurrences)
+}
+
+// Clone returns a copy of the order that shares no history with it.
+func (o *StandingOrder) Clone() *StandingOrder {
+	copied := *o
+	copied.History = append([]Execution(nil), o.History...)
+	return &copied
+}
+
+// occurrence returns the date of the n-th payment, counting from zero.
+// Monthly orders keep StartAt's day of the month, or the month's last day
+// if it is shorter.
+func (o *StandingOrder) occurrence(n int) time.Time {
+	switch o.Frequency {
+	case FrequencyDaily:
+		return o.StartAt.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		return o.StartAt.AddDate(0, 0, 7*n)
+	case FrequencyMonthly:
+		year, month, day := o.StartAt.Date()
+		first := time.Date(year, month+time.Month(n), 1, o.StartAt.Hour(), o.StartAt.Minute(), o.StartAt.Second(), o.StartAt.Nanosecond(), o.StartAt.Location())
+		if last := first.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		return first.AddDate(0, 0, day-1)
+	default:
+		return o.StartAt
+	}
+}
+
+// finished reports whether the n-th occurrence, counting from zero, is past
+// the end of the schedule.
+func (o *StandingOrder) finished(n int) bool {
+	if o.Frequency == FrequencyOnce {
+		return n >= 1
+	}
+	if o.MaxExecutions > 0 && n >= o.MaxExecutions {
+		return true
+	}
+	return o.EndAt != nil && o.occurrence(n).After(*o.EndAt)
+}
+
+// CreateRequest sets up a standing order. StartAt defaults to now; a
+// recurring order without EndAt or MaxExecutions runs until cancelled.
+type CreateRequest struct {
+	FromAccountID string     `json:"from_account_id"`
+	ToAccountID   string     `json:"to_account_id"`
+	Amount        int64      `json:"amount"`
+	Currency      string     `json:"currency,omitempty"`
+	Reference     string     `json:"reference,omitempty"`
+	Frequency     Frequency  `json:"frequency"`
+	StartAt       *time.Time `json:"start_at,omitempty"`
+	EndAt         *time.Time `json:"end_at,omitempty"`
+	MaxExecutions int        `json:"max_executions,omitempty"`
+}
+
+// Service applies the standing order rules. An attempt declined for
+// insufficient funds is retried maxRetries times, retryDelay apart, before
+// the occurrence is given up on.
+type Service struct {
+	maxRetries int
+	retryDelay time.Duration
+}
+
+func NewService(maxRetries int, retryDelay time.Duration) *Service {
+	return &Service{maxRetries: maxRetries, retryDelay: retryDelay}
+}
+
+// Create builds a new active order for req, paid in currency.
+func (s *Service) Create(req CreateRequest, currency string, now time.Time) (*StandingOrder, error) {
+	if req.Amount <= 0 {
+		return nil, &errors.ErrInvalidAmount{Amount: req.Amount}
+	}
+	if req.FromAccountID == req.ToAccountID {
+		return nil, &errors.ErrSameAccountTransfer{FromAccountID: req.FromAccountID, ToAccountID: req.ToAccountID}
+	}
+	if !ValidFrequency(req.Frequency) {
+		return nil, &errors.ErrInvalidParameter{Name: "frequency", Value: string(req.Frequency)}
+	}
+	if req.MaxExecutions < 0 {
+		return nil, &errors.ErrInvalidParameter{Name: "max_executions", Value: strconv.Itoa(req.MaxExecutions)}
+	}
+
+	startAt := now
+	if req.StartAt != nil {
+		if req.StartAt.Before(now.Add(-time.Minute)) {
+			return nil, &errors.ErrInvalidParameter{Name: "start_at", Value: req.StartAt.Format(time.RFC3339)}
+		}
+		startAt = *req.StartAt
+	}
+	if req.EndAt != nil && req.EndAt.Before(startAt) {
+		return nil, &errors.ErrInvalidParameter{Name: "end_at", Value: req.EndAt.Format(time.RFC3339)}
+	}
+
+	o := &StandingOrder{
+		ID:            uuid.New().String(),
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Currency:      currency,
+		Reference:     req.Reference,
+		Frequency:     req.Frequency,
+		StartAt:       startAt,
+		EndAt:         req.EndAt,
+		MaxExecutions: req.MaxExecutions,
+		Status:        StatusActive,
+		NextRunAt:     &startAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if o.Frequency == FrequencyOnce {
+		o.EndAt, o.MaxExecutions = nil, 0
+	}
+	return o, nil
+}
+
+func (s *Service) Cancel(o *StandingOrder, now time.Time) error {
+	if o.Status != StatusActive {
+		return &errors.ErrStandingOrderNotActive{StandingOrderID: o.ID, Status: string(o.Status)}
+	}
+
+	o.Status = StatusCancelled
+	o.NextRunAt = nil
+	o.UpdatedAt = now
+	return nil
+}
+
+// Succeeded records that the current occurrence was paid by transaction
+// transactionID and schedules the next one.
+func (s *Service) Succeeded(o *StandingOrder, transactionID string, now time.Time) {
+	o.History = append(o.History, Execution{
+		ScheduledFor:  o.ScheduledFor(),
+		AttemptedAt:   now,
+		Attempt:       o.Attempts + 1,
+		Status:        ExecutionSucceeded,
+		TransactionID: transactionID,
+	})
+	s.advance(o, now)
+}
+
+// Declined records that paying the current occurrence failed with cause,
+// and transactionID, if any, is the failed transaction recorded for it. The
+// attempt is retried later for insufficient funds, the order fails for good
+// when one of its accounts is gone, and otherwise the occurrence is skipped.
+func (s *Service) Declined(o *StandingOrder, cause error, transactionID string, now time.Time) {
+	execution := Execution{
+		ScheduledFor:  o.ScheduledFor(),
+		AttemptedAt:   now,
+		Attempt:       o.Attempts + 1,
+		Status:        ExecutionFailed,
+		TransactionID: transactionID,
+		FailureCode:   errors.Code(cause),
+		FailureReason: cause.Error(),
+	}
+
+	switch cause.(type) {
+	case *errors.ErrInsufficientFunds:
+		if o.Attempts < s.maxRetries {
+			execution.Status = ExecutionRetrying
+			o.History = append(o.History, execution)
+			o.Attempts++
+			retryAt := now.Add(s.retryDelay)
+			o.NextRunAt = &retryAt
+			o.UpdatedAt = now
+			return
+		}
+	case *errors.ErrAccountNotFound, *errors.ErrAccountClosed:
+		o.History = append(o.History, execution)
+		o.Status = StatusFailed
+		o.NextRunAt = nil
+		o.UpdatedAt = now
+		return
+	}
+
+	o.History = append(o.History, execution)
+	s.advance(o, now)
+}
+
+// advance moves on to the next occurrence, completing the order when there
+// is none.
+func (s *Service) advance(o *StandingOrder, now time.Time) {
+	o.Occurrences++
+	o.Attempts = 0
+	o.UpdatedAt = now
+
+	if o.finished(o.Occurrences) {
+		o.Status = StatusCompleted
+		o.NextRunAt = nil
+		return
+	}
+	next := o.occurrence(o.Occurrences)
+	o.NextRunAt = &next
+}
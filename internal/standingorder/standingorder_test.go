@@ -0,0 +1,146 @@
+package standingorder
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"banking-service/pkg/errors"
+)
+
+func TestCreate(t *testing.T) {
+	s := NewService(3, time.Hour)
+	now := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	start := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name string
+		req  CreateRequest
+		want error
+	}{
+		{"valid", CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: FrequencyMonthly, StartAt: &start}, nil},
+		{"zero amount", CreateRequest{FromAccountID: "a", ToAccountID: "b", Frequency: FrequencyOnce}, &errors.ErrInvalidAmount{}},
+		{"same account", CreateRequest{FromAccountID: "a", ToAccountID: "a", Amount: 100, Frequency: FrequencyOnce}, &errors.ErrSameAccountTransfer{}},
+		{"unknown frequency", CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: "yearly"}, &errors.ErrInvalidParameter{}},
+		{"start in the past", CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: FrequencyOnce, StartAt: &past}, &errors.ErrInvalidParameter{}},
+		{"end before start", CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: FrequencyDaily, StartAt: &start, EndAt: &now}, &errors.ErrInvalidParameter{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := s.Create(tt.req, "INR", now)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if o.Status != StatusActive || !o.NextRunAt.Equal(start) {
+					t.Errorf("got status %s next run %v, want active at %v", o.Status, o.NextRunAt, start)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected %T, got nil", tt.want)
+			}
+			if fmt.Sprintf("%T", err) != fmt.Sprintf("%T", tt.want) {
+				t.Errorf("Create() error = %v, want %T", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonthlyScheduleClampsToMonthEnd(t *testing.T) {
+	s := NewService(0, time.Hour)
+	start := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+	o, err := s.Create(CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: FrequencyMonthly, StartAt: &start, MaxExecutions: 3}, "INR", start)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	want := []time.Time{
+		time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+	}
+	for i, next := range want {
+		s.Succeeded(o, "tx", *o.NextRunAt)
+		if o.NextRunAt == nil || !o.NextRunAt.Equal(next) {
+			t.Fatalf("after payment %d: next run %v, want %v", i+1, o.NextRunAt, next)
+		}
+	}
+
+	s.Succeeded(o, "tx", *o.NextRunAt)
+	if o.Status != StatusCompleted || o.NextRunAt != nil {
+		t.Errorf("after last payment: status %s next run %v, want completed", o.Status, o.NextRunAt)
+	}
+	if len(o.History) != 3 || o.Occurrences != 3 {
+		t.Errorf("got %d executions over %d occurrences, want 3", len(o.History), o.Occurrences)
+	}
+}
+
+func TestEndDateCompletesOrder(t *testing.T) {
+	s := NewService(0, time.Hour)
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)
+	o, err := s.Create(CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: FrequencyWeekly, StartAt: &start, EndAt: &end}, "INR", start)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	s.Succeeded(o, "tx", start)
+	if o.Status != StatusActive || !o.NextRunAt.Equal(end) {
+		t.Fatalf("got status %s next run %v, want active at %v", o.Status, o.NextRunAt, end)
+	}
+	s.Succeeded(o, "tx", end)
+	if o.Status != StatusCompleted {
+		t.Errorf("got status %s, want completed", o.Status)
+	}
+}
+
+func TestDeclinedRetriesInsufficientFunds(t *testing.T) {
+	s := NewService(2, time.Hour)
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	o, err := s.Create(CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: FrequencyDaily, StartAt: &start}, "INR", start)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	broke := &errors.ErrInsufficientFunds{AccountID: "a", Amount: 100}
+	now := start
+	for attempt := 1; attempt <= 2; attempt++ {
+		s.Declined(o, broke, "failed-tx", now)
+		now = now.Add(time.Hour)
+		if !o.NextRunAt.Equal(now) || o.Attempts != attempt {
+			t.Fatalf("attempt %d: next run %v attempts %d, want retry at %v", attempt, o.NextRunAt, o.Attempts, now)
+		}
+		if got := o.History[len(o.History)-1].Status; got != ExecutionRetrying {
+			t.Fatalf("attempt %d: execution status %s, want retrying", attempt, got)
+		}
+	}
+
+	// Out of retries: the occurrence is given up on and the order moves on.
+	s.Declined(o, broke, "failed-tx", now)
+	last := o.History[len(o.History)-1]
+	if last.Status != ExecutionFailed || last.Attempt != 3 || last.FailureCode != "insufficient_funds" {
+		t.Errorf("got final execution %+v, want third attempt failed for insufficient funds", last)
+	}
+	if o.Occurrences != 1 || o.Attempts != 0 || !o.NextRunAt.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("got occurrences %d attempts %d next run %v, want the next day", o.Occurrences, o.Attempts, o.NextRunAt)
+	}
+}
+
+func TestDeclinedClosedAccountFailsOrder(t *testing.T) {
+	s := NewService(2, time.Hour)
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	o, err := s.Create(CreateRequest{FromAccountID: "a", ToAccountID: "b", Amount: 100, Frequency: FrequencyDaily, StartAt: &start}, "INR", start)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	s.Declined(o, &errors.ErrAccountClosed{AccountID: "b"}, "", start)
+	if o.Status != StatusFailed || o.NextRunAt != nil {
+		t.Errorf("got status %s next run %v, want failed", o.Status, o.NextRunAt)
+	}
+	if err := s.Cancel(o, start); err == nil {
+		t.Error("expected cancelling a failed order to be rejected")
+	}
+}
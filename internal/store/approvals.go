@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"banking-service/internal/approval"
+	"banking-service/pkg/errors"
+)
+
+func (s *MemoryStore) GetApproval(ctx context.Context, id string) (*approval.Approval, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, exists := s.approvals[id]
+	if !exists {
+		return nil, &errors.ErrApprovalNotFound{ApprovalID: id}
+	}
+	return a.Clone(), nil
+}
+
+func (s *MemoryStore) ListApprovals(ctx context.Context, accountID string) []*approval.Approval {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var approvals []*approval.Approval
+	for _, a := range s.approvals {
+		if a.AccountID == accountID {
+			approvals = append(approvals, a.Clone())
+		}
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		if !approvals[i].CreatedAt.Equal(approvals[j].CreatedAt) {
+			return approvals[i].CreatedAt.After(approvals[j].CreatedAt)
+		}
+		return approvals[i].ID > approvals[j].ID
+	})
+	return approvals
+}
+
+func (s *MemoryStore) ListExpiredApprovals(ctx context.Context, now time.Time) []*approval.Approval {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var expired []*approval.Approval
+	for _, a := range s.approvals {
+		if a.Expired(now) {
+			expired = append(expired, a.Clone())
+		}
+	}
+	return expired
+}
+
+// GetApproval returns a staged copy of the approval; changes to it are
+// persisted with SaveApproval.
+func (u *memoryUnitOfWork) GetApproval(id string) (*approval.Approval, error) {
+	if a, staged := u.approvals[id]; staged {
+		return a.Clone(), nil
+	}
+
+	a, exists := u.store.approvals[id]
+	if !exists {
+		return nil, &errors.ErrApprovalNotFound{ApprovalID: id}
+	}
+	return a.Clone(), nil
+}
+
+func (u *memoryUnitOfWork) SaveApproval(a *approval.Approval) error {
+	u.approvals[a.ID] = a.Clone()
+	return nil
+}
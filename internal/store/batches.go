@@ -0,0 +1,24 @@
+package store
+
+import (
+	"context"
+
+	"banking-service/internal/batch"
+	"banking-service/pkg/errors"
+)
+
+func (s *MemoryStore) GetBatch(ctx context.Context, id string) (*batch.Batch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, exists := s.batches[id]
+	if !exists {
+		return nil, &errors.ErrBatchNotFound{BatchID: id}
+	}
+	return b.Clone(), nil
+}
+
+func (u *memoryUnitOfWork) SaveBatch(b *batch.Batch) error {
+	u.batches[b.ID] = b.Clone()
+	return nil
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"banking-service/internal/beneficiary"
+	"banking-service/pkg/errors"
+)
+
+func (s *MemoryStore) GetBeneficiary(ctx context.Context, id string) (*beneficiary.Beneficiary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, exists := s.beneficiaries[id]
+	if !exists {
+		return nil, &errors.ErrBeneficiaryNotFound{BeneficiaryID: id}
+	}
+
+	copied := *b
+	return &copied, nil
+}
+
+func (s *MemoryStore) ListBeneficiaries(ctx context.Context, accountID string) []*beneficiary.Beneficiary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var beneficiaries []*beneficiary.Beneficiary
+	for _, b := range s.beneficiaries {
+		if b.AccountID == accountID {
+			copied := *b
+			beneficiaries = append(beneficiaries, &copied)
+		}
+	}
+	sort.Slice(beneficiaries, func(i, j int) bool {
+		if !beneficiaries[i].CreatedAt.Equal(beneficiaries[j].CreatedAt) {
+			return beneficiaries[i].CreatedAt.Before(beneficiaries[j].CreatedAt)
+		}
+		return beneficiaries[i].ID < beneficiaries[j].ID
+	})
+	return beneficiaries
+}
+
+// GetBeneficiary returns a staged copy of the beneficiary; changes to it are
+// persisted with SaveBeneficiary.
+func (u *memoryUnitOfWork) GetBeneficiary(id string) (*beneficiary.Beneficiary, error) {
+	if u.beneficiaryDeleted(id) {
+		return nil, &errors.ErrBeneficiaryNotFound{BeneficiaryID: id}
+	}
+	if b, staged := u.beneficiaries[id]; staged {
+		copied := *b
+		return &copied, nil
+	}
+
+	b, exists := u.store.beneficiaries[id]
+	if !exists {
+		return nil, &errors.ErrBeneficiaryNotFound{BeneficiaryID: id}
+	}
+
+	copied := *b
+	return &copied, nil
+}
+
+// FindBeneficiary returns a copy of the beneficiary registered on accountID
+// for payeeAccountID, or nil if there is none.
+func (u *memoryUnitOfWork) FindBeneficiary(accountID, payeeAccountID string) *beneficiary.Beneficiary {
+	matches := func(b *beneficiary.Beneficiary) bool {
+		return b.AccountID == accountID && b.PayeeAccountID == payeeAccountID && !u.beneficiaryDeleted(b.ID)
+	}
+
+	for _, b := range u.beneficiaries {
+		if matches(b) {
+			copied := *b
+			return &copied
+		}
+	}
+	for id, b := range u.store.beneficiaries {
+		if _, staged := u.beneficiaries[id]; staged {
+			continue
+		}
+		if matches(b) {
+			copied := *b
+			return &copied
+		}
+	}
+	return nil
+}
+
+func (u *memoryUnitOfWork) SaveBeneficiary(b *beneficiary.Beneficiary) error {
+	staged := *b
+	u.beneficiaries[b.ID] = &staged
+	return nil
+}
+
+func (u *memoryUnitOfWork) DeleteBeneficiary(id string) error {
+	if _, err := u.GetBeneficiary(id); err != nil {
+		return err
+	}
+
+	delete(u.beneficiaries, id)
+	u.deletedBeneficiaries = append(u.deletedBeneficiaries, id)
+	return nil
+}
+
+func (u *memoryUnitOfWork) beneficiaryDeleted(id string) bool {
+	for _, deleted := range u.deletedBeneficiaries {
+		if deleted == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/internal/beneficiary"
+	"banking-service/pkg/errors"
+)
+
+func TestDeleteBeneficiary(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		return uow.SaveBeneficiary(&beneficiary.Beneficiary{ID: "ben-1", AccountID: "acc-1", PayeeAccountID: "acc-2", CreatedAt: now})
+	})
+	if err != nil {
+		t.Fatalf("SaveBeneficiary() error = %v", err)
+	}
+
+	err = store.Apply(ctx, func(uow UnitOfWork) error {
+		if b := uow.FindBeneficiary("acc-1", "acc-2"); b == nil || b.ID != "ben-1" {
+			t.Errorf("FindBeneficiary() = %v, want ben-1", b)
+		}
+		if b := uow.FindBeneficiary("acc-2", "acc-1"); b != nil {
+			t.Errorf("FindBeneficiary() = %v for the reverse direction, want none", b)
+		}
+		if err := uow.DeleteBeneficiary("ben-1"); err != nil {
+			return err
+		}
+		if b := uow.FindBeneficiary("acc-1", "acc-2"); b != nil {
+			t.Errorf("FindBeneficiary() = %v after deleting it, want none", b)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DeleteBeneficiary() error = %v", err)
+	}
+
+	if _, err := store.GetBeneficiary(ctx, "ben-1"); err == nil {
+		t.Error("GetBeneficiary() found a deleted beneficiary")
+	} else if _, ok := err.(*errors.ErrBeneficiaryNotFound); !ok {
+		t.Errorf("GetBeneficiary() error = %v, want *errors.ErrBeneficiaryNotFound", err)
+	}
+	if got := store.ListBeneficiaries(ctx, "acc-1"); len(got) != 0 {
+		t.Errorf("ListBeneficiaries() = %d beneficiaries, want 0", len(got))
+	}
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"banking-service/internal/account"
+	"banking-service/internal/customer"
+	"banking-service/pkg/errors"
+)
+
+func (s *MemoryStore) GetCustomer(ctx context.Context, id string) (*customer.Customer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, exists := s.customers[id]
+	if !exists {
+		return nil, &errors.ErrCustomerNotFound{CustomerID: id}
+	}
+
+	copied := *c
+	return &copied, nil
+}
+
+func (s *MemoryStore) ListCustomers(ctx context.Context) []*customer.Customer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	customers := make([]*customer.Customer, 0, len(s.customers))
+	for _, c := range s.customers {
+		copied := *c
+		customers = append(customers, &copied)
+	}
+	sort.Slice(customers, func(i, j int) bool {
+		if !customers[i].CreatedAt.Equal(customers[j].CreatedAt) {
+			return customers[i].CreatedAt.Before(customers[j].CreatedAt)
+		}
+		return customers[i].ID < customers[j].ID
+	})
+	return customers
+}
+
+func (s *MemoryStore) ListCustomerAccounts(ctx context.Context, customerID string) []*account.Account {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var accounts []*account.Account
+	for _, acc := range s.accounts {
+		if _, held := acc.Holder(customerID); held {
+			copied := *acc
+			accounts = append(accounts, &copied)
+		}
+	}
+	sortAccounts(accounts)
+	return accounts
+}
+
+func sortAccounts(accounts []*account.Account) {
+	sort.Slice(accounts, func(i, j int) bool {
+		if !accounts[i].CreatedAt.Equal(accounts[j].CreatedAt) {
+			return accounts[i].CreatedAt.Before(accounts[j].CreatedAt)
+		}
+		return accounts[i].ID < accounts[j].ID
+	})
+}
+
+// GetCustomer returns a staged copy of the customer; changes to it are
+// persisted with SaveCustomer.
+func (u *memoryUnitOfWork) GetCustomer(id string) (*customer.Customer, error) {
+	for _, deleted := range u.deletedCustomers {
+		if deleted == id {
+			return nil, &errors.ErrCustomerNotFound{CustomerID: id}
+		}
+	}
+	if c, staged := u.customers[id]; staged {
+		copied := *c
+		return &copied, nil
+	}
+
+	c, exists := u.store.customers[id]
+	if !exists {
+		return nil, &errors.ErrCustomerNotFound{CustomerID: id}
+	}
+
+	copied := *c
+	return &copied, nil
+}
+
+func (u *memoryUnitOfWork) SaveCustomer(c *customer.Customer) error {
+	staged := *c
+	u.customers[c.ID] = &staged
+	return nil
+}
+
+func (u *memoryUnitOfWork) DeleteCustomer(id string) error {
+	if _, err := u.GetCustomer(id); err != nil {
+		return err
+	}
+
+	delete(u.customers, id)
+	u.deletedCustomers = append(u.deletedCustomers, id)
+	return nil
+}
+
+// ListCustomerAccounts returns copies of the customer's accounts as staged in
+// this unit of work. They are for reading; use GetAccount to change one.
+func (u *memoryUnitOfWork) ListCustomerAccounts(customerID string) []*account.Account {
+	var accounts []*account.Account
+	for id, acc := range u.store.accounts {
+		if _, staged := u.accounts[id]; staged {
+			continue
+		}
+		if _, held := acc.Holder(customerID); held {
+			copied := *acc
+			accounts = append(accounts, &copied)
+		}
+	}
+	for _, acc := range u.accounts {
+		if _, held := acc.Holder(customerID); held {
+			copied := *acc
+			accounts = append(accounts, &copied)
+		}
+	}
+	sortAccounts(accounts)
+	return accounts
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/customer"
+	"banking-service/pkg/errors"
+)
+
+func TestDeleteCustomer(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		return uow.SaveCustomer(&customer.Customer{ID: "cust-1", Name: "Asha Rao", CreatedAt: now})
+	})
+	if err != nil {
+		t.Fatalf("SaveCustomer() error = %v", err)
+	}
+	if err := store.CreateAccount(ctx, &account.Account{ID: "acc-1", CustomerID: "cust-1", CreatedAt: now}); err != nil {
+		t.Fatalf("CreateAccount() error = %v", err)
+	}
+
+	err = store.Apply(ctx, func(uow UnitOfWork) error {
+		if got := uow.ListCustomerAccounts("cust-1"); len(got) != 1 {
+			t.Errorf("ListCustomerAccounts() = %d accounts, want 1", len(got))
+		}
+		return uow.DeleteCustomer("cust-1")
+	})
+	if err != nil {
+		t.Fatalf("DeleteCustomer() error = %v", err)
+	}
+
+	if _, err := store.GetCustomer(ctx, "cust-1"); err == nil {
+		t.Error("GetCustomer() found a deleted customer")
+	} else if _, ok := err.(*errors.ErrCustomerNotFound); !ok {
+		t.Errorf("GetCustomer() error = %v, want *errors.ErrCustomerNotFound", err)
+	}
+
+	acc, _ := store.GetAccount(ctx, "acc-1")
+	if acc.Version != 1 {
+		t.Errorf("listing accounts changed version to %d", acc.Version)
+	}
+}
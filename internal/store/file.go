@@ -0,0 +1,384 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/approval"
+	"banking-service/internal/batch"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/customer"
+	"banking-service/internal/fx"
+	"banking-service/internal/hold"
+	"banking-service/internal/idempotency"
+	"banking-service/internal/ledger"
+	"banking-service/internal/standingorder"
+	"banking-service/internal/transaction"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+)
+
+var _ Repository = (*FileStore)(nil)
+
+// walRecord is one durable change set. A record is applied as a whole, so
+// everything committed by a single Apply call is recovered together or not at
+// all. Snapshots reuse the same shape with Reset set.
+type walRecord struct {
+	Seq          uint64                     `json:"seq"`
+	Reset        bool                       `json:"reset,omitempty"`
+	Accounts     []*account.Account         `json:"accounts,omitempty"`
+	Transactions []*transaction.Transaction `json:"transactions,omitempty"`
+	Entries      []*ledger.JournalEntry     `json:"entries,omitempty"`
+
+	IdempotencyKeys        []*idempotency.Record `json:"idempotency_keys,omitempty"`
+	DeletedIdempotencyKeys []string              `json:"deleted_idempotency_keys,omitempty"`
+
+	Quotes        []*fx.Quote `json:"quotes,omitempty"`
+	DeletedQuotes []string    `json:"deleted_quotes,omitempty"`
+
+	Holds []*hold.Hold `json:"holds,omitempty"`
+
+	StandingOrders []*standingorder.StandingOrder `json:"standing_orders,omitempty"`
+	Batches        []*batch.Batch                 `json:"batches,omitempty"`
+
+	Customers        []*customer.Customer `json:"customers,omitempty"`
+	DeletedCustomers []string             `json:"deleted_customers,omitempty"`
+
+	Approvals []*approval.Approval `json:"approvals,omitempty"`
+
+	Beneficiaries        []*beneficiary.Beneficiary `json:"beneficiaries,omitempty"`
+	DeletedBeneficiaries []string                   `json:"deleted_beneficiaries,omitempty"`
+
+	AccountSequences map[string]int64 `json:"account_sequences,omitempty"`
+}
+
+type FileStoreOptions struct {
+	// SnapshotInterval is how often a snapshot is taken in the background.
+	// Zero disables the timer.
+	SnapshotInterval time.Duration
+	// SnapshotThreshold triggers a snapshot once this many records have been
+	// appended to the log since the last one. Zero disables it.
+	SnapshotThreshold int
+}
+
+// FileStore is a MemoryStore whose changes are fsynced to an append-only
+// write-ahead log before they are applied. On open the latest snapshot is
+// loaded and the log replayed on top of it; snapshots compact the log.
+type FileStore struct {
+	*MemoryStore
+
+	dir     string
+	opts    FileStoreOptions
+	wal     *os.File
+	seq     uint64
+	pending int
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func OpenFileStore(dir string, opts FileStoreOptions) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create store directory: %w", err)
+	}
+
+	f := &FileStore{
+		MemoryStore: NewMemoryStore(),
+		dir:         dir,
+		opts:        opts,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	if err := f.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := f.replay(); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open write-ahead log: %w", err)
+	}
+	f.wal = wal
+	f.MemoryStore.journal = f
+	if err := f.releaseAbandonedKeys(); err != nil {
+		f.wal.Close()
+		return nil, err
+	}
+
+	go f.snapshotLoop()
+	return f, nil
+}
+
+func (f *FileStore) append(rec *walRecord) error {
+	if f.wal == nil {
+		return fmt.Errorf("file store is closed")
+	}
+
+	rec.Seq = f.seq + 1
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode wal record: %w", err)
+	}
+	line = append(line, '\n')
+
+	offset, err := f.wal.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("seek write-ahead log: %w", err)
+	}
+	if _, err := f.wal.Write(line); err != nil {
+		return f.discardFrom(offset, fmt.Errorf("write wal record: %w", err))
+	}
+	if err := f.wal.Sync(); err != nil {
+		return f.discardFrom(offset, fmt.Errorf("sync wal: %w", err))
+	}
+
+	f.seq = rec.Seq
+	f.pending++
+	return nil
+}
+
+// applied compacts the log once SnapshotThreshold records have been appended
+// since the last snapshot. It runs after the record is applied, so the
+// snapshot includes it.
+func (f *FileStore) applied() {
+	if f.opts.SnapshotThreshold > 0 && f.pending >= f.opts.SnapshotThreshold {
+		// The record is already durable, so a failed compaction only means
+		// the log keeps growing until the next attempt.
+		_ = f.snapshotLocked()
+	}
+}
+
+// discardFrom truncates a record that failed to append back off the log, so
+// the next record does not follow a partial line that replay would reject.
+// If that fails too, the log is closed and nothing more is appended to it.
+func (f *FileStore) discardFrom(offset int64, cause error) error {
+	if err := f.wal.Truncate(offset); err != nil {
+		f.wal.Close()
+		f.wal = nil
+		return fmt.Errorf("%v; truncate write-ahead log: %w", cause, err)
+	}
+	return cause
+}
+
+func (f *FileStore) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(f.dir, snapshotFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var rec walRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return fmt.Errorf("decode snapshot: %w", err)
+	}
+
+	f.applyRecord(&rec)
+	f.seq = rec.Seq
+	return nil
+}
+
+// replay applies every log record newer than the loaded snapshot. A partially
+// written final record, left behind by a crash mid-append, is truncated away;
+// corruption anywhere else is reported.
+func (f *FileStore) replay() error {
+	path := filepath.Join(f.dir, walFileName)
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open write-ahead log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				return file.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read write-ahead log: %w", err)
+		}
+
+		var rec walRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			if _, peekErr := reader.Peek(1); peekErr == io.EOF {
+				return file.Truncate(offset)
+			}
+			return fmt.Errorf("corrupt write-ahead log record at offset %d: %w", offset, err)
+		}
+		offset += int64(len(line))
+
+		if rec.Seq <= f.seq {
+			continue
+		}
+		f.applyRecord(&rec)
+		f.seq = rec.Seq
+		f.pending++
+	}
+}
+
+// Snapshot writes the full state to disk and truncates the log.
+func (f *FileStore) Snapshot() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.snapshotLocked()
+}
+
+func (f *FileStore) snapshotLocked() error {
+	if f.wal == nil {
+		return fmt.Errorf("file store is closed")
+	}
+
+	rec := &walRecord{Seq: f.seq, Reset: true}
+	for _, acc := range f.accounts {
+		rec.Accounts = append(rec.Accounts, acc)
+	}
+	for _, tx := range f.transactions {
+		rec.Transactions = append(rec.Transactions, tx)
+	}
+	rec.Entries = f.entries
+	for _, key := range f.idempotencyKeys {
+		rec.IdempotencyKeys = append(rec.IdempotencyKeys, key)
+	}
+	for _, quote := range f.quotes {
+		rec.Quotes = append(rec.Quotes, quote)
+	}
+	for _, h := range f.holds {
+		rec.Holds = append(rec.Holds, h)
+	}
+	for _, o := range f.standingOrders {
+		rec.StandingOrders = append(rec.StandingOrders, o)
+	}
+	for _, b := range f.batches {
+		rec.Batches = append(rec.Batches, b)
+	}
+	for _, c := range f.customers {
+		rec.Customers = append(rec.Customers, c)
+	}
+	for _, a := range f.approvals {
+		rec.Approvals = append(rec.Approvals, a)
+	}
+	for _, b := range f.beneficiaries {
+		rec.Beneficiaries = append(rec.Beneficiaries, b)
+	}
+	rec.AccountSequences = f.accountSequences
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := writeFileSync(filepath.Join(f.dir, snapshotFileName), data); err != nil {
+		return err
+	}
+
+	// Every record up to f.seq is now in the snapshot. Should we crash before
+	// the truncate below, replay skips those records by sequence number.
+	if err := f.wal.Truncate(0); err != nil {
+		return fmt.Errorf("truncate write-ahead log: %w", err)
+	}
+	if err := f.wal.Sync(); err != nil {
+		return fmt.Errorf("sync wal: %w", err)
+	}
+
+	f.pending = 0
+	return nil
+}
+
+func (f *FileStore) snapshotLoop() {
+	defer close(f.done)
+
+	if f.opts.SnapshotInterval <= 0 {
+		<-f.stop
+		return
+	}
+
+	ticker := time.NewTicker(f.opts.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.mu.Lock()
+			if f.pending > 0 {
+				_ = f.snapshotLocked()
+			}
+			f.mu.Unlock()
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// Close stops background snapshots and closes the log. Every committed change
+// is already durable, so Close does not need to flush anything.
+func (f *FileStore) Close() error {
+	var err error
+	f.closeOnce.Do(func() {
+		close(f.stop)
+		<-f.done
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		err = f.wal.Close()
+		f.wal = nil
+	})
+	return err
+}
+
+// writeFileSync atomically replaces path with data: it writes a temporary
+// file, fsyncs it, renames it over path and fsyncs the directory.
+func writeFileSync(path string, data []byte) error {
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", tmp, err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("sync %s: %w", tmp, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename %s: %w", tmp, err)
+	}
+
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("open %s: %w", filepath.Dir(path), err)
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
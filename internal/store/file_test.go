@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/idempotency"
+	"banking-service/internal/ledger"
+	"banking-service/internal/transaction"
+)
+
+func openTestFileStore(t *testing.T, dir string) *FileStore {
+	t.Helper()
+
+	store, err := OpenFileStore(dir, FileStoreOptions{})
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	return store
+}
+
+func TestFileStoreRecovery(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	store.CreateAccount(ctx, &account.Account{ID: "from-id", CustomerName: "Nisha", Balance: 1000})
+	store.CreateAccount(ctx, &account.Account{ID: "to-id", CustomerName: "Rahul", Balance: 500})
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		from, _ := uow.GetAccount("from-id")
+		to, _ := uow.GetAccount("to-id")
+		from.Balance -= 300
+		to.Balance += 300
+		uow.PostEntry(&ledger.JournalEntry{
+			ID:       "test-entry-id",
+			Postings: []ledger.Posting{ledger.Debit("from-id", "INR", 300), ledger.Credit("to-id", "INR", 300)},
+		})
+		return uow.StoreTransaction(&transaction.Transaction{ID: "test-tx-id", Amount: 300})
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// Reopen without closing, as after a kill -9.
+	reopened := openTestFileStore(t, dir)
+	defer reopened.Close()
+
+	from, err := reopened.GetAccount(ctx, "from-id")
+	if err != nil {
+		t.Fatalf("GetAccount() error = %v", err)
+	}
+	to, err := reopened.GetAccount(ctx, "to-id")
+	if err != nil {
+		t.Fatalf("GetAccount() error = %v", err)
+	}
+	if from.Balance != 700 || to.Balance != 800 {
+		t.Errorf("recovered balances = %v/%v, want 700/800", from.Balance, to.Balance)
+	}
+
+	if _, err := reopened.GetTransaction(ctx, "test-tx-id"); err != nil {
+		t.Errorf("GetTransaction() error = %v", err)
+	}
+
+	if entries := reopened.GetJournalEntries(ctx); len(entries) != 1 {
+		t.Errorf("GetJournalEntries() returned %d entries, want 1", len(entries))
+	}
+}
+
+func TestFileStoreSnapshot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	store.CreateAccount(ctx, &account.Account{ID: "test-id", CustomerName: "Priya", Balance: 1000})
+
+	if err := store.Snapshot(); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("Snapshot() left %d bytes in the log, want 0", info.Size())
+	}
+
+	acc, _ := store.GetAccount(ctx, "test-id")
+	acc.Balance = 2500
+	store.UpdateAccount(ctx, acc)
+	store.Close()
+
+	reopened := openTestFileStore(t, dir)
+	defer reopened.Close()
+
+	acc, err = reopened.GetAccount(ctx, "test-id")
+	if err != nil {
+		t.Fatalf("GetAccount() error = %v", err)
+	}
+	if acc.Balance != 2500 {
+		t.Errorf("recovered balance = %v, want 2500", acc.Balance)
+	}
+}
+
+func TestFileStoreSnapshotThreshold(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := OpenFileStore(dir, FileStoreOptions{SnapshotThreshold: 2})
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	ids := []string{"a", "b", "c", "d", "e"}
+	for _, id := range ids {
+		if err := store.CreateAccount(ctx, &account.Account{ID: id, CustomerName: id}); err != nil {
+			t.Fatalf("CreateAccount(%s) error = %v", id, err)
+		}
+	}
+	store.Close()
+
+	// The records that triggered each snapshot must be in it.
+	reopened := openTestFileStore(t, dir)
+	defer reopened.Close()
+
+	for _, id := range ids {
+		if _, err := reopened.GetAccount(ctx, id); err != nil {
+			t.Errorf("GetAccount(%s) after reopening error = %v", id, err)
+		}
+	}
+}
+
+func TestFileStoreTornWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	store.CreateAccount(ctx, &account.Account{ID: "test-id", CustomerName: "Sunil", Balance: 1000})
+	store.Close()
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	wal.WriteString(`{"seq":2,"accounts":[{"id":"test-id","bal`)
+	wal.Close()
+
+	reopened := openTestFileStore(t, dir)
+	defer reopened.Close()
+
+	acc, err := reopened.GetAccount(ctx, "test-id")
+	if err != nil {
+		t.Fatalf("GetAccount() error = %v", err)
+	}
+	if acc.Balance != 1000 {
+		t.Errorf("recovered balance = %v, want 1000", acc.Balance)
+	}
+
+	if err := reopened.CreateAccount(ctx, &account.Account{ID: "second-id", CustomerName: "Meena"}); err != nil {
+		t.Fatalf("CreateAccount() after recovery error = %v", err)
+	}
+	reopened.Close()
+
+	again := openTestFileStore(t, dir)
+	defer again.Close()
+	if _, err := again.GetAccount(ctx, "second-id"); err != nil {
+		t.Errorf("GetAccount() after torn write recovery error = %v", err)
+	}
+}
+
+func TestFileStoreAccountNumbers(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	for _, id := range []string{"acc-1", "acc-2"} {
+		err := store.Apply(ctx, func(uow UnitOfWork) error {
+			acc := &account.Account{ID: id}
+			acc.Number = fmt.Sprintf("000120%07d", uow.NextAccountSequence("000120"))
+			acc.IBAN = "GB00NWBK" + acc.Number
+			return uow.CreateAccount(acc)
+		})
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+
+	reopened := openTestFileStore(t, dir)
+	defer reopened.Close()
+
+	for _, ref := range []string{"0001200000002", "gb00 nwbk 0001 2000 0000 2"} {
+		acc, err := reopened.GetAccount(ctx, ref)
+		if err != nil {
+			t.Fatalf("GetAccount(%q) error = %v", ref, err)
+		}
+		if acc.ID != "acc-2" {
+			t.Errorf("GetAccount(%q) = %s, want acc-2", ref, acc.ID)
+		}
+	}
+
+	reopened.Apply(ctx, func(uow UnitOfWork) error {
+		if seq := uow.NextAccountSequence("000120"); seq != 3 {
+			t.Errorf("NextAccountSequence() = %d after recovery, want 3", seq)
+		}
+		if seq := uow.NextAccountSequence("000110"); seq != 1 {
+			t.Errorf("NextAccountSequence() = %d for a new prefix, want 1", seq)
+		}
+		return nil
+	})
+}
+
+func TestFileStoreIdempotencyKeysAfterCrash(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Now()
+
+	store := openTestFileStore(t, dir)
+	store.CreateAccount(ctx, &account.Account{ID: "acc-id", CustomerName: "Nisha", Balance: 1000})
+	for _, key := range []string{"committed", "abandoned"} {
+		store.ReserveIdempotencyKey(ctx, &idempotency.Record{Key: key, Fingerprint: "fp", CreatedAt: now, ExpiresAt: now.Add(time.Minute)})
+	}
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		if err := uow.StoreTransaction(&transaction.Transaction{ID: "tx-id", Amount: 300}); err != nil {
+			return err
+		}
+		return uow.CommitIdempotencyKey("committed")
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// Reopen without completing either key, as after a kill -9 between the
+	// commit and storing the response.
+	reopened := openTestFileStore(t, dir)
+	defer reopened.Close()
+
+	retry := &idempotency.Record{Key: "committed", Fingerprint: "fp", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	existing, err := reopened.ReserveIdempotencyKey(ctx, retry)
+	if err != nil || existing == nil || !existing.Committed || existing.Completed() {
+		t.Fatalf("ReserveIdempotencyKey() = %+v, %v, want the committed key still held", existing, err)
+	}
+	if err := reopened.ReleaseIdempotencyKey(ctx, "committed"); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey() error = %v", err)
+	}
+	if purged, _ := reopened.PurgeExpiredIdempotencyKeys(ctx, now.Add(time.Hour)); purged != 0 {
+		t.Errorf("PurgeExpiredIdempotencyKeys() purged %d keys, want the committed key kept", purged)
+	}
+	if existing, _ := reopened.ReserveIdempotencyKey(ctx, retry); existing == nil {
+		t.Error("ReserveIdempotencyKey() reclaimed a committed key, so a retry would repeat its changes")
+	}
+
+	abandoned := &idempotency.Record{Key: "abandoned", Fingerprint: "fp", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	if existing, err := reopened.ReserveIdempotencyKey(ctx, abandoned); err != nil || existing != nil {
+		t.Errorf("ReserveIdempotencyKey() = %+v, %v, want the abandoned key released on reopen", existing, err)
+	}
+}
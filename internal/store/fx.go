@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"banking-service/internal/fx"
+	"banking-service/pkg/errors"
+)
+
+func (s *MemoryStore) SaveQuote(ctx context.Context, quote *fx.Quote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.quotes[quote.ID]; exists {
+		return fmt.Errorf("fx quote with ID %s already exists", quote.ID)
+	}
+
+	stored := *quote
+	return s.commit(&walRecord{Quotes: []*fx.Quote{&stored}})
+}
+
+func (s *MemoryStore) GetQuote(ctx context.Context, id string) (*fx.Quote, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	quote, exists := s.quotes[id]
+	if !exists {
+		return nil, &errors.ErrQuoteNotFound{QuoteID: id}
+	}
+
+	copied := *quote
+	return &copied, nil
+}
+
+func (s *MemoryStore) PurgeExpiredQuotes(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []string
+	for id, quote := range s.quotes {
+		if quote.Expired(now) {
+			expired = append(expired, id)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	if err := s.commit(&walRecord{DeletedQuotes: expired}); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+func (u *memoryUnitOfWork) GetQuote(id string) (*fx.Quote, error) {
+	quote, staged := u.quotes[id]
+	if !staged {
+		var exists bool
+		if quote, exists = u.store.quotes[id]; !exists {
+			return nil, &errors.ErrQuoteNotFound{QuoteID: id}
+		}
+	}
+
+	copied := *quote
+	return &copied, nil
+}
+
+// ConsumeQuote marks an unexpired, unused quote as used by transactionID.
+func (u *memoryUnitOfWork) ConsumeQuote(id, transactionID string, now time.Time) error {
+	quote, err := u.GetQuote(id)
+	if err != nil {
+		return err
+	}
+	if quote.ConsumedBy != "" {
+		return &errors.ErrQuoteAlreadyUsed{QuoteID: id, TransactionID: quote.ConsumedBy}
+	}
+	if quote.Expired(now) {
+		return &errors.ErrQuoteExpired{QuoteID: id}
+	}
+
+	quote.ConsumedBy = transactionID
+	u.quotes[id] = quote
+	return nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/internal/fx"
+	"banking-service/pkg/errors"
+)
+
+func TestConsumeQuote(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	quote := &fx.Quote{ID: "quote-1", FromCurrency: "USD", ToCurrency: "INR", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	if err := store.SaveQuote(ctx, quote); err != nil {
+		t.Fatalf("SaveQuote() error = %v", err)
+	}
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		return uow.ConsumeQuote("quote-1", "tx-1", now)
+	})
+	if err != nil {
+		t.Fatalf("ConsumeQuote() error = %v", err)
+	}
+
+	stored, _ := store.GetQuote(ctx, "quote-1")
+	if stored.ConsumedBy != "tx-1" {
+		t.Errorf("ConsumedBy = %q, want tx-1", stored.ConsumedBy)
+	}
+
+	err = store.Apply(ctx, func(uow UnitOfWork) error {
+		return uow.ConsumeQuote("quote-1", "tx-2", now)
+	})
+	if _, ok := err.(*errors.ErrQuoteAlreadyUsed); !ok {
+		t.Errorf("second ConsumeQuote() error = %v, want *errors.ErrQuoteAlreadyUsed", err)
+	}
+}
+
+func TestConsumeExpiredQuote(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	quote := &fx.Quote{ID: "quote-1", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	if err := store.SaveQuote(ctx, quote); err != nil {
+		t.Fatalf("SaveQuote() error = %v", err)
+	}
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		return uow.ConsumeQuote("quote-1", "tx-1", now.Add(time.Minute))
+	})
+	if _, ok := err.(*errors.ErrQuoteExpired); !ok {
+		t.Errorf("ConsumeQuote() error = %v, want *errors.ErrQuoteExpired", err)
+	}
+
+	purged, err := store.PurgeExpiredQuotes(ctx, now.Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Errorf("PurgeExpiredQuotes() = %d, %v, want 1, nil", purged, err)
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
+)
+
+type TransactionPage struct {
+	Transactions []*transaction.Transaction `json:"transactions"`
+	NextCursor   string                     `json:"next_cursor,omitempty"`
+}
+
+// indexTransaction adds a newly stored transaction to the per-account index.
+// The caller must hold mu.
+func (s *MemoryStore) indexTransaction(tx *transaction.Transaction) {
+	for _, accountID := range tx.AccountIDs() {
+		ids := s.accountTransactions[accountID]
+		i := sort.Search(len(ids), func(i int) bool {
+			return !s.transactionBefore(s.transactions[ids[i]], tx)
+		})
+
+		ids = append(ids, "")
+		copy(ids[i+1:], ids[i:])
+		ids[i] = tx.ID
+		s.accountTransactions[accountID] = ids
+	}
+}
+
+func (s *MemoryStore) transactionBefore(a, b *transaction.Transaction) bool {
+	if a.Timestamp.Equal(b.Timestamp) {
+		return a.ID < b.ID
+	}
+	return a.Timestamp.Before(b.Timestamp)
+}
+
+// ListAccountTransactions pages through an account's transactions, newest
+// first. cursor is the NextCursor of the previous page, or empty for the first.
+func (s *MemoryStore) ListAccountTransactions(ctx context.Context, accountID string, filter transaction.Filter, cursor string, limit int) (*TransactionPage, error) {
+	var after *transaction.Transaction
+	if cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = c
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	page := &TransactionPage{Transactions: []*transaction.Transaction{}}
+	ids := s.accountTransactions[accountID]
+
+	start := len(ids)
+	if after != nil {
+		start = sort.Search(len(ids), func(i int) bool {
+			return !s.transactionBefore(s.transactions[ids[i]], after)
+		})
+	}
+
+	for i := start - 1; i >= 0; i-- {
+		tx := s.transactions[ids[i]]
+		if !filter.Matches(tx) {
+			continue
+		}
+
+		if limit > 0 && len(page.Transactions) == limit {
+			page.NextCursor = encodeCursor(page.Transactions[limit-1])
+			break
+		}
+
+		copied := *tx
+		page.Transactions = append(page.Transactions, &copied)
+	}
+	return page, nil
+}
+
+func encodeCursor(tx *transaction.Transaction) string {
+	raw := strconv.FormatInt(tx.Timestamp.UnixNano(), 10) + ":" + tx.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor returns a stand-in transaction carrying only the sort key the
+// cursor points at.
+func decodeCursor(cursor string) (*transaction.Transaction, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, &errors.ErrInvalidCursor{Cursor: cursor}
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, &errors.ErrInvalidCursor{Cursor: cursor}
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, &errors.ErrInvalidCursor{Cursor: cursor}
+	}
+
+	return &transaction.Transaction{ID: id, Timestamp: time.Unix(0, n)}, nil
+}
+
+// ListAccountTransactions returns the account's transactions that match
+// filter, including any staged in this unit of work, oldest first.
+func (u *memoryUnitOfWork) ListAccountTransactions(accountID string, filter transaction.Filter) []*transaction.Transaction {
+	latest := make(map[string]*transaction.Transaction)
+	for _, id := range u.store.accountTransactions[accountID] {
+		latest[id] = u.store.transactions[id]
+	}
+	for _, tx := range u.transactions {
+		for _, id := range tx.AccountIDs() {
+			if id == accountID {
+				latest[tx.ID] = tx
+				break
+			}
+		}
+	}
+
+	var matched []*transaction.Transaction
+	for _, tx := range latest {
+		if filter.Matches(tx) {
+			copied := *tx
+			matched = append(matched, &copied)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return u.store.transactionBefore(matched[i], matched[j])
+	})
+	return matched
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/internal/transaction"
+)
+
+func TestListAccountTransactions(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, tx := range []*transaction.Transaction{
+		{ID: "tx-1", Type: transaction.TransactionTypeDeposit, AccountID: "acc-1", Amount: 100},
+		{ID: "tx-2", Type: transaction.TransactionTypeWithdrawal, AccountID: "acc-1", Amount: 50},
+		{ID: "tx-3", Type: transaction.TransactionTypeTransfer, FromAccountID: "acc-1", ToAccountID: "acc-2", Amount: 25},
+		{ID: "tx-4", Type: transaction.TransactionTypeDeposit, AccountID: "acc-2", Amount: 500},
+		{ID: "tx-5", Type: transaction.TransactionTypeDeposit, AccountID: "acc-1", Amount: 700},
+	} {
+		tx.Timestamp = base.Add(time.Duration(i) * time.Hour)
+		tx.Status = transaction.TransactionStatusCompleted
+		store.StoreTransaction(ctx, tx)
+	}
+
+	first, err := store.ListAccountTransactions(ctx, "acc-1", transaction.Filter{}, "", 2)
+	if err != nil {
+		t.Fatalf("ListAccountTransactions() error = %v", err)
+	}
+	if got := transactionIDs(first.Transactions); got != "tx-5,tx-3" {
+		t.Errorf("ListAccountTransactions() first page = %v, want tx-5,tx-3", got)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("ListAccountTransactions() expected a next cursor")
+	}
+
+	second, err := store.ListAccountTransactions(ctx, "acc-1", transaction.Filter{}, first.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("ListAccountTransactions() error = %v", err)
+	}
+	if got := transactionIDs(second.Transactions); got != "tx-2,tx-1" {
+		t.Errorf("ListAccountTransactions() second page = %v, want tx-2,tx-1", got)
+	}
+	if second.NextCursor != "" {
+		t.Errorf("ListAccountTransactions() next cursor = %q, want none", second.NextCursor)
+	}
+
+	min := int64(60)
+	deposits, _ := store.ListAccountTransactions(ctx, "acc-1", transaction.Filter{
+		Types:     []transaction.TransactionType{transaction.TransactionTypeDeposit},
+		MinAmount: &min,
+	}, "", 10)
+	if got := transactionIDs(deposits.Transactions); got != "tx-5,tx-1" {
+		t.Errorf("ListAccountTransactions() filtered = %v, want tx-5,tx-1", got)
+	}
+
+	window, _ := store.ListAccountTransactions(ctx, "acc-2", transaction.Filter{
+		From: base.Add(2 * time.Hour),
+		To:   base.Add(3 * time.Hour),
+	}, "", 10)
+	if got := transactionIDs(window.Transactions); got != "tx-3" {
+		t.Errorf("ListAccountTransactions() time range = %v, want tx-3", got)
+	}
+
+	if _, err := store.ListAccountTransactions(ctx, "acc-1", transaction.Filter{}, "not-a-cursor", 10); err == nil {
+		t.Error("ListAccountTransactions() expected error for invalid cursor")
+	}
+}
+
+func transactionIDs(txs []*transaction.Transaction) string {
+	var ids string
+	for i, tx := range txs {
+		if i > 0 {
+			ids += ","
+		}
+		ids += tx.ID
+	}
+	return ids
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"banking-service/internal/hold"
+	"banking-service/pkg/errors"
+)
+
+func (s *MemoryStore) GetHold(ctx context.Context, id string) (*hold.Hold, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	h, exists := s.holds[id]
+	if !exists {
+		return nil, &errors.ErrHoldNotFound{HoldID: id}
+	}
+
+	copied := *h
+	return &copied, nil
+}
+
+func (s *MemoryStore) ListExpiredHolds(ctx context.Context, now time.Time) []*hold.Hold {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var expired []*hold.Hold
+	for _, h := range s.holds {
+		if h.Expired(now) {
+			copied := *h
+			expired = append(expired, &copied)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		if !expired[i].ExpiresAt.Equal(expired[j].ExpiresAt) {
+			return expired[i].ExpiresAt.Before(expired[j].ExpiresAt)
+		}
+		return expired[i].ID < expired[j].ID
+	})
+	return expired
+}
+
+// GetHold returns a staged copy of the hold; changes to it are persisted with
+// SaveHold.
+func (u *memoryUnitOfWork) GetHold(id string) (*hold.Hold, error) {
+	if h, staged := u.holds[id]; staged {
+		copied := *h
+		return &copied, nil
+	}
+
+	h, exists := u.store.holds[id]
+	if !exists {
+		return nil, &errors.ErrHoldNotFound{HoldID: id}
+	}
+
+	copied := *h
+	return &copied, nil
+}
+
+func (u *memoryUnitOfWork) SaveHold(h *hold.Hold) error {
+	staged := *h
+	u.holds[h.ID] = &staged
+	return nil
+}
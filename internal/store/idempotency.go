@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"banking-service/internal/idempotency"
+)
+
+// ReserveIdempotencyKey claims rec.Key for a new request. If an unexpired
+// record already holds the key it is returned instead and nothing is stored.
+func (s *MemoryStore) ReserveIdempotencyKey(ctx context.Context, rec *idempotency.Record) (*idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.idempotencyKeys[rec.Key]; exists && !existing.Expired(rec.CreatedAt) {
+		copied := *existing
+		return &copied, nil
+	}
+
+	stored := *rec
+	return nil, s.commit(&walRecord{IdempotencyKeys: []*idempotency.Record{&stored}})
+}
+
+func (s *MemoryStore) CompleteIdempotencyKey(ctx context.Context, rec *idempotency.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *rec
+	return s.commit(&walRecord{IdempotencyKeys: []*idempotency.Record{&stored}})
+}
+
+// ReleaseIdempotencyKey forgets a reservation so the request can be retried,
+// e.g. after an internal error. Keys whose request already committed changes
+// are kept.
+func (s *MemoryStore) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, exists := s.idempotencyKeys[key]; !exists || rec.Committed {
+		return nil
+	}
+	return s.commit(&walRecord{DeletedIdempotencyKeys: []string{key}})
+}
+
+// releaseAbandonedKeys forgets the keys a previous run left in flight without
+// committing any changes, so their requests can be retried straight away.
+// It is called on startup, when nothing can still be in flight.
+func (s *MemoryStore) releaseAbandonedKeys() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var abandoned []string
+	for key, rec := range s.idempotencyKeys {
+		if !rec.Completed() && !rec.Committed {
+			abandoned = append(abandoned, key)
+		}
+	}
+	if len(abandoned) == 0 {
+		return nil
+	}
+	return s.commit(&walRecord{DeletedIdempotencyKeys: abandoned})
+}
+
+// CommitIdempotencyKey marks the request holding key as having made the
+// changes in this unit of work, in the same commit.
+func (u *memoryUnitOfWork) CommitIdempotencyKey(key string) error {
+	u.idempotencyKeys = append(u.idempotencyKeys, key)
+	return nil
+}
+
+func (s *MemoryStore) PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []string
+	for key, rec := range s.idempotencyKeys {
+		if rec.Expired(now) {
+			expired = append(expired, key)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	if err := s.commit(&walRecord{DeletedIdempotencyKeys: expired}); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
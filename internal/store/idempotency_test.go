@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"banking-service/internal/idempotency"
+)
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	rec := &idempotency.Record{
+		Key:         "key-1",
+		Fingerprint: "fp-1",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	existing, err := store.ReserveIdempotencyKey(ctx, rec)
+	if err != nil || existing != nil {
+		t.Fatalf("ReserveIdempotencyKey() = %v, %v, want nil, nil", existing, err)
+	}
+
+	rec.StatusCode = 200
+	rec.Response = []byte(`{"transaction_id":"tx-1"}`)
+	if err := store.CompleteIdempotencyKey(ctx, rec); err != nil {
+		t.Fatalf("CompleteIdempotencyKey() error = %v", err)
+	}
+
+	retry := &idempotency.Record{Key: "key-1", Fingerprint: "fp-1", CreatedAt: now.Add(time.Minute)}
+	existing, err = store.ReserveIdempotencyKey(ctx, retry)
+	if err != nil {
+		t.Fatalf("ReserveIdempotencyKey() error = %v", err)
+	}
+	if existing == nil || existing.StatusCode != 200 {
+		t.Fatalf("ReserveIdempotencyKey() existing = %v, want completed record", existing)
+	}
+
+	expired := &idempotency.Record{Key: "key-1", Fingerprint: "fp-2", CreatedAt: now.Add(2 * time.Hour)}
+	existing, err = store.ReserveIdempotencyKey(ctx, expired)
+	if err != nil || existing != nil {
+		t.Errorf("ReserveIdempotencyKey() after expiry = %v, %v, want nil, nil", existing, err)
+	}
+}
+
+func TestPurgeExpiredIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	store.ReserveIdempotencyKey(ctx, &idempotency.Record{Key: "old", CreatedAt: now, ExpiresAt: now.Add(time.Minute)})
+	store.ReserveIdempotencyKey(ctx, &idempotency.Record{Key: "new", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+
+	purged, err := store.PurgeExpiredIdempotencyKeys(ctx, now.Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("PurgeExpiredIdempotencyKeys() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeExpiredIdempotencyKeys() purged %d keys, want 1", purged)
+	}
+}
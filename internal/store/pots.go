@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+
+	"banking-service/internal/account"
+)
+
+func (s *MemoryStore) ListPots(ctx context.Context, parentID string) []*account.Account {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var pots []*account.Account
+	for _, acc := range s.accounts {
+		if acc.ParentID == parentID {
+			copied := *acc
+			pots = append(pots, &copied)
+		}
+	}
+	sortAccounts(pots)
+	return pots
+}
+
+// ListPots returns copies of the parent's pots as staged in this unit of
+// work. They are for reading; use GetAccount to change one.
+func (u *memoryUnitOfWork) ListPots(parentID string) []*account.Account {
+	var pots []*account.Account
+	for id, acc := range u.store.accounts {
+		if _, staged := u.accounts[id]; staged {
+			continue
+		}
+		if acc.ParentID == parentID {
+			copied := *acc
+			pots = append(pots, &copied)
+		}
+	}
+	for _, acc := range u.accounts {
+		if acc.ParentID == parentID {
+			copied := *acc
+			pots = append(pots, &copied)
+		}
+	}
+	sortAccounts(pots)
+	return pots
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"banking-service/internal/account"
+	"banking-service/internal/approval"
+	"banking-service/internal/batch"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/customer"
+	"banking-service/internal/fx"
+	"banking-service/internal/hold"
+	"banking-service/internal/idempotency"
+	"banking-service/internal/ledger"
+	"banking-service/internal/standingorder"
+	"banking-service/internal/transaction"
+)
+
+// Repository is the storage surface used by the API. MemoryStore is the
+// in-process implementation; other backends only need to satisfy this
+// interface to be selectable from cmd/server.
+type Repository interface {
+	CreateAccount(ctx context.Context, acc *account.Account) error
+	// GetAccount finds an account by its ID, account number or IBAN.
+	GetAccount(ctx context.Context, id string) (*account.Account, error)
+	UpdateAccount(ctx context.Context, acc *account.Account) error
+	StoreTransaction(ctx context.Context, tx *transaction.Transaction) error
+	GetTransaction(ctx context.Context, id string) (*transaction.Transaction, error)
+	ListAccountTransactions(ctx context.Context, accountID string, filter transaction.Filter, cursor string, limit int) (*TransactionPage, error)
+	GetAllAccounts(ctx context.Context) []*account.Account
+	GetAllTransactions(ctx context.Context) []*transaction.Transaction
+	GetJournalEntries(ctx context.Context) []*ledger.JournalEntry
+
+	// LedgerState returns every account and journal entry as of a single
+	// point in time, for verifying the ledger invariants.
+	LedgerState(ctx context.Context) ([]*account.Account, []*ledger.JournalEntry)
+
+	ReserveIdempotencyKey(ctx context.Context, rec *idempotency.Record) (*idempotency.Record, error)
+	CompleteIdempotencyKey(ctx context.Context, rec *idempotency.Record) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	PurgeExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int, error)
+
+	SaveQuote(ctx context.Context, quote *fx.Quote) error
+	GetQuote(ctx context.Context, id string) (*fx.Quote, error)
+	PurgeExpiredQuotes(ctx context.Context, now time.Time) (int, error)
+
+	GetHold(ctx context.Context, id string) (*hold.Hold, error)
+	// ListExpiredHolds returns the active holds whose expiry is at or before
+	// now, longest expired first.
+	ListExpiredHolds(ctx context.Context, now time.Time) []*hold.Hold
+
+	GetStandingOrder(ctx context.Context, id string) (*standingorder.StandingOrder, error)
+	// ListStandingOrders returns the standing orders paying from or into
+	// accountID, or every order if accountID is empty, oldest first.
+	ListStandingOrders(ctx context.Context, accountID string) []*standingorder.StandingOrder
+	// ListDueStandingOrders returns the active standing orders with an
+	// attempt due at or before now.
+	ListDueStandingOrders(ctx context.Context, now time.Time) []*standingorder.StandingOrder
+
+	GetBatch(ctx context.Context, id string) (*batch.Batch, error)
+
+	GetCustomer(ctx context.Context, id string) (*customer.Customer, error)
+	// ListCustomers returns every customer, oldest first.
+	ListCustomers(ctx context.Context) []*customer.Customer
+	// ListCustomerAccounts returns the accounts the customer holds in any
+	// role, oldest first.
+	ListCustomerAccounts(ctx context.Context, customerID string) []*account.Account
+	// ListPots returns the pots under parentID, including closed ones,
+	// oldest first.
+	ListPots(ctx context.Context, parentID string) []*account.Account
+
+	GetApproval(ctx context.Context, id string) (*approval.Approval, error)
+	// ListApprovals returns the approvals for payments from accountID,
+	// newest first.
+	ListApprovals(ctx context.Context, accountID string) []*approval.Approval
+	// ListExpiredApprovals returns the pending approvals whose expiry is at
+	// or before now.
+	ListExpiredApprovals(ctx context.Context, now time.Time) []*approval.Approval
+
+	GetBeneficiary(ctx context.Context, id string) (*beneficiary.Beneficiary, error)
+	// ListBeneficiaries returns the payees registered on accountID, oldest
+	// first.
+	ListBeneficiaries(ctx context.Context, accountID string) []*beneficiary.Beneficiary
+
+	// Apply runs fn as a single all-or-nothing unit: if fn returns an error
+	// nothing it staged is written, otherwise every change is committed
+	// together. A unit whose balance changes are not matched by its journal
+	// entries is rejected with *errors.ErrLedgerImbalance.
+	Apply(ctx context.Context, fn func(uow UnitOfWork) error) error
+}
+
+// UnitOfWork is the view of the repository handed to Apply. Accounts it
+// returns are staged copies that are only persisted when the unit commits.
+type UnitOfWork interface {
+	CreateAccount(acc *account.Account) error
+	GetAccount(id string) (*account.Account, error)
+	StoreTransaction(tx *transaction.Transaction) error
+	GetTransaction(id string) (*transaction.Transaction, error)
+	UpdateTransaction(tx *transaction.Transaction) error
+	ListAccountTransactions(accountID string, filter transaction.Filter) []*transaction.Transaction
+	PostEntry(entry *ledger.JournalEntry) error
+	GetQuote(id string) (*fx.Quote, error)
+	ConsumeQuote(id, transactionID string, now time.Time) error
+	GetHold(id string) (*hold.Hold, error)
+	SaveHold(h *hold.Hold) error
+	GetStandingOrder(id string) (*standingorder.StandingOrder, error)
+	SaveStandingOrder(o *standingorder.StandingOrder) error
+	SaveBatch(b *batch.Batch) error
+	GetCustomer(id string) (*customer.Customer, error)
+	SaveCustomer(c *customer.Customer) error
+	DeleteCustomer(id string) error
+	ListCustomerAccounts(customerID string) []*account.Account
+	ListPots(parentID string) []*account.Account
+	NextAccountSequence(prefix string) int64
+	GetApproval(id string) (*approval.Approval, error)
+	SaveApproval(a *approval.Approval) error
+	GetBeneficiary(id string) (*beneficiary.Beneficiary, error)
+	// FindBeneficiary returns the payee registered on accountID for
+	// payeeAccountID, or nil if there is none.
+	FindBeneficiary(accountID, payeeAccountID string) *beneficiary.Beneficiary
+	SaveBeneficiary(b *beneficiary.Beneficiary) error
+	DeleteBeneficiary(id string) error
+	// CommitIdempotencyKey records, with the unit of work's changes, that
+	// the request holding key made them.
+	CommitIdempotencyKey(key string) error
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"banking-service/internal/standingorder"
+	"banking-service/pkg/errors"
+)
+
+func (s *MemoryStore) GetStandingOrder(ctx context.Context, id string) (*standingorder.StandingOrder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, exists := s.standingOrders[id]
+	if !exists {
+		return nil, &errors.ErrStandingOrderNotFound{StandingOrderID: id}
+	}
+	return o.Clone(), nil
+}
+
+func (s *MemoryStore) ListStandingOrders(ctx context.Context, accountID string) []*standingorder.StandingOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var orders []*standingorder.StandingOrder
+	for _, o := range s.standingOrders {
+		if accountID == "" || o.FromAccountID == accountID || o.ToAccountID == accountID {
+			orders = append(orders, o.Clone())
+		}
+	}
+	sortStandingOrders(orders)
+	return orders
+}
+
+func (s *MemoryStore) ListDueStandingOrders(ctx context.Context, now time.Time) []*standingorder.StandingOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var due []*standingorder.StandingOrder
+	for _, o := range s.standingOrders {
+		if o.Due(now) {
+			due = append(due, o.Clone())
+		}
+	}
+	sortStandingOrders(due)
+	return due
+}
+
+func sortStandingOrders(orders []*standingorder.StandingOrder) {
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+		}
+		return orders[i].ID < orders[j].ID
+	})
+}
+
+// GetStandingOrder returns a staged copy of the order; changes to it are
+// persisted with SaveStandingOrder.
+func (u *memoryUnitOfWork) GetStandingOrder(id string) (*standingorder.StandingOrder, error) {
+	if o, staged := u.standingOrders[id]; staged {
+		return o.Clone(), nil
+	}
+
+	o, exists := u.store.standingOrders[id]
+	if !exists {
+		return nil, &errors.ErrStandingOrderNotFound{StandingOrderID: id}
+	}
+	return o.Clone(), nil
+}
+
+func (u *memoryUnitOfWork) SaveStandingOrder(o *standingorder.StandingOrder) error {
+	u.standingOrders[o.ID] = o.Clone()
+	return nil
+}
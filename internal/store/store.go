@@ -1,28 +1,90 @@
 package store
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	"banking-service/internal/account"
+	"banking-service/internal/accountnumber"
+	"banking-service/internal/approval"
+	"banking-service/internal/batch"
+	"banking-service/internal/beneficiary"
+	"banking-service/internal/customer"
+	"banking-service/internal/fx"
+	"banking-service/internal/hold"
+	"banking-service/internal/idempotency"
+	"banking-service/internal/ledger"
+	"banking-service/internal/standingorder"
 	"banking-service/internal/transaction"
 	"banking-service/pkg/errors"
 )
 
-type Store struct {
+var _ Repository = (*MemoryStore)(nil)
+
+type MemoryStore struct {
 	accounts     map[string]*account.Account
 	transactions map[string]*transaction.Transaction
+	entries      []*ledger.JournalEntry
 	mu           sync.RWMutex
+
+	idempotencyKeys map[string]*idempotency.Record
+
+	// accountTransactions indexes transaction IDs by every account they
+	// touch, kept sorted by (Timestamp, ID).
+	accountTransactions map[string][]string
+
+	// accountNumbers maps account numbers and IBANs to account IDs, and
+	// accountSequences holds the last sequence allocated for each account
+	// number prefix.
+	accountNumbers   map[string]string
+	accountSequences map[string]int64
+
+	quotes map[string]*fx.Quote
+	holds  map[string]*hold.Hold
+
+	standingOrders map[string]*standingorder.StandingOrder
+	batches        map[string]*batch.Batch
+	customers      map[string]*customer.Customer
+	approvals      map[string]*approval.Approval
+	beneficiaries  map[string]*beneficiary.Beneficiary
+
+	// journal, when set, must durably record every change before it is
+	// applied to the maps above. It is always called with mu held.
+	journal journal
 }
 
-func NewStore() *Store {
-	return &Store{
+type journal interface {
+	append(rec *walRecord) error
+	// applied is called once an appended record has been applied, with mu
+	// still held.
+	applied()
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
 		accounts:     make(map[string]*account.Account),
 		transactions: make(map[string]*transaction.Transaction),
+
+		idempotencyKeys: make(map[string]*idempotency.Record),
+
+		accountTransactions: make(map[string][]string),
+
+		accountNumbers:   make(map[string]string),
+		accountSequences: make(map[string]int64),
+
+		quotes: make(map[string]*fx.Quote),
+		holds:  make(map[string]*hold.Hold),
+
+		standingOrders: make(map[string]*standingorder.StandingOrder),
+		batches:        make(map[string]*batch.Batch),
+		customers:      make(map[string]*customer.Customer),
+		approvals:      make(map[string]*approval.Approval),
+		beneficiaries:  make(map[string]*beneficiary.Beneficiary),
 	}
 }
 
-func (s *Store) CreateAccount(acc *account.Account) error {
+func (s *MemoryStore) CreateAccount(ctx context.Context, acc *account.Account) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -30,35 +92,56 @@ func (s *Store) CreateAccount(acc *account.Account) error {
 		return fmt.Errorf("account with ID %s already exists", acc.ID)
 	}
 
-	s.accounts[acc.ID] = acc
+	stored := *acc
+	stored.Version = 1
+	if err := s.commit(&walRecord{Accounts: []*account.Account{&stored}}); err != nil {
+		return err
+	}
+
+	acc.Version = stored.Version
 	return nil
 }
 
-func (s *Store) GetAccount(id string) (*account.Account, error) {
+// GetAccount finds an account by its ID, account number or IBAN.
+func (s *MemoryStore) GetAccount(ctx context.Context, id string) (*account.Account, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	acc, exists := s.accounts[id]
+	if !exists {
+		acc, exists = s.accounts[s.accountNumbers[accountnumber.Normalize(id)]]
+	}
 	if !exists {
 		return nil, &errors.ErrAccountNotFound{AccountID: id}
 	}
 
-	return acc, nil
+	copied := *acc
+	return &copied, nil
 }
 
-func (s *Store) UpdateAccount(acc *account.Account) error {
+// UpdateAccount replaces the stored account and bumps its version. Changes
+// that depend on the account's current state belong in Apply, which reads
+// and writes under one lock.
+func (s *MemoryStore) UpdateAccount(ctx context.Context, acc *account.Account) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.accounts[acc.ID]; !exists {
+	current, exists := s.accounts[acc.ID]
+	if !exists {
 		return &errors.ErrAccountNotFound{AccountID: acc.ID}
 	}
 
-	s.accounts[acc.ID] = acc
+	stored := *acc
+	stored.Version = current.Version + 1
+	if err := s.commit(&walRecord{Accounts: []*account.Account{&stored}}); err != nil {
+		return err
+	}
+
+	acc.Version = stored.Version
 	return nil
 }
 
-func (s *Store) StoreTransaction(tx *transaction.Transaction) error {
+func (s *MemoryStore) StoreTransaction(ctx context.Context, tx *transaction.Transaction) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -66,48 +149,407 @@ func (s *Store) StoreTransaction(tx *transaction.Transaction) error {
 		return fmt.Errorf("transaction with ID %s already exists", tx.ID)
 	}
 
-	s.transactions[tx.ID] = tx
-	return nil
+	return s.commit(&walRecord{Transactions: []*transaction.Transaction{tx}})
 }
 
-func (s *Store) GetTransaction(id string) (*transaction.Transaction, error) {
+func (s *MemoryStore) GetTransaction(ctx context.Context, id string) (*transaction.Transaction, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	tx, exists := s.transactions[id]
 	if !exists {
-		return nil, fmt.Errorf("transaction not found: %s", id)
+		return nil, &errors.ErrTransactionNotFound{TransactionID: id}
+	}
+
+	copied := *tx
+	return &copied, nil
+}
+
+type memoryUnitOfWork struct {
+	store        *MemoryStore
+	accounts     map[string]*account.Account
+	transactions []*transaction.Transaction
+	entries      []*ledger.JournalEntry
+	created      []*account.Account
+	quotes       map[string]*fx.Quote
+	holds        map[string]*hold.Hold
+
+	standingOrders map[string]*standingorder.StandingOrder
+	batches        map[string]*batch.Batch
+
+	customers        map[string]*customer.Customer
+	deletedCustomers []string
+
+	approvals map[string]*approval.Approval
+
+	beneficiaries        map[string]*beneficiary.Beneficiary
+	deletedBeneficiaries []string
+
+	sequences map[string]int64
+
+	idempotencyKeys []string
+}
+
+func (u *memoryUnitOfWork) CreateAccount(acc *account.Account) error {
+	if _, exists := u.store.accounts[acc.ID]; exists {
+		return fmt.Errorf("account with ID %s already exists", acc.ID)
+	}
+	if _, staged := u.accounts[acc.ID]; staged {
+		return fmt.Errorf("account with ID %s already exists", acc.ID)
+	}
+
+	staged := *acc
+	staged.Version = 0
+	u.accounts[acc.ID] = &staged
+	u.created = append(u.created, acc)
+	return nil
+}
+
+// GetAccount returns a staged copy of the account. Mutations on the copy are
+// only visible to other callers once the unit of work commits.
+func (u *memoryUnitOfWork) GetAccount(id string) (*account.Account, error) {
+	if acc, ok := u.accounts[id]; ok {
+		return acc, nil
+	}
+
+	acc, exists := u.store.accounts[id]
+	if !exists {
+		return nil, &errors.ErrAccountNotFound{AccountID: id}
+	}
+
+	staged := *acc
+	u.accounts[id] = &staged
+	return &staged, nil
+}
+
+// NextAccountSequence allocates the next sequence number for account numbers
+// starting with prefix.
+func (u *memoryUnitOfWork) NextAccountSequence(prefix string) int64 {
+	seq, staged := u.sequences[prefix]
+	if !staged {
+		seq = u.store.accountSequences[prefix]
+	}
+	u.sequences[prefix] = seq + 1
+	return seq + 1
+}
+
+func (u *memoryUnitOfWork) StoreTransaction(tx *transaction.Transaction) error {
+	if _, exists := u.store.transactions[tx.ID]; exists {
+		return fmt.Errorf("transaction with ID %s already exists", tx.ID)
+	}
+	for _, staged := range u.transactions {
+		if staged.ID == tx.ID {
+			return fmt.Errorf("transaction with ID %s already exists", tx.ID)
+		}
 	}
 
-	return tx, nil
+	u.transactions = append(u.transactions, tx)
+	return nil
 }
 
-func (s *Store) GetAllAccounts() []*account.Account {
+// GetTransaction returns a copy of the transaction, including any staged
+// in this unit of work. Changes are persisted with UpdateTransaction.
+func (u *memoryUnitOfWork) GetTransaction(id string) (*transaction.Transaction, error) {
+	for i := len(u.transactions) - 1; i >= 0; i-- {
+		if u.transactions[i].ID == id {
+			copied := *u.transactions[i]
+			return &copied, nil
+		}
+	}
+
+	tx, exists := u.store.transactions[id]
+	if !exists {
+		return nil, &errors.ErrTransactionNotFound{TransactionID: id}
+	}
+
+	copied := *tx
+	return &copied, nil
+}
+
+// UpdateTransaction replaces a stored transaction. Its ID, type and accounts
+// must not change, since the account history index is keyed on them.
+func (u *memoryUnitOfWork) UpdateTransaction(tx *transaction.Transaction) error {
+	current, err := u.GetTransaction(tx.ID)
+	if err != nil {
+		return err
+	}
+	if current.Type != tx.Type || !current.Timestamp.Equal(tx.Timestamp) ||
+		current.AccountID != tx.AccountID || current.FromAccountID != tx.FromAccountID || current.ToAccountID != tx.ToAccountID {
+		return fmt.Errorf("transaction %s cannot change type, timestamp or accounts", tx.ID)
+	}
+
+	u.transactions = append(u.transactions, tx)
+	return nil
+}
+
+func (u *memoryUnitOfWork) PostEntry(entry *ledger.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	u.entries = append(u.entries, entry)
+	return nil
+}
+
+// verifyPostings checks that every staged balance change is fully explained
+// by the journal entries posted in this unit of work.
+func (u *memoryUnitOfWork) verifyPostings() error {
+	deltas := ledger.BalanceDeltas(u.entries)
+
+	for id, acc := range u.accounts {
+		var before int64
+		if orig, exists := u.store.accounts[id]; exists {
+			before = orig.Balance
+		}
+
+		if acc.Balance-before != deltas[id] {
+			return &errors.ErrLedgerImbalance{
+				AccountID: id,
+				Expected:  acc.Balance - before,
+				Actual:    deltas[id],
+			}
+		}
+	}
+
+	for id, delta := range deltas {
+		if _, staged := u.accounts[id]; !staged && delta != 0 {
+			return &errors.ErrLedgerImbalance{AccountID: id, Actual: delta}
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Apply(ctx context.Context, fn func(uow UnitOfWork) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	uow := &memoryUnitOfWork{
+		store:    s,
+		accounts: make(map[string]*account.Account),
+		quotes:   make(map[string]*fx.Quote),
+		holds:    make(map[string]*hold.Hold),
+
+		standingOrders: make(map[string]*standingorder.StandingOrder),
+		batches:        make(map[string]*batch.Batch),
+		customers:      make(map[string]*customer.Customer),
+		approvals:      make(map[string]*approval.Approval),
+		beneficiaries:  make(map[string]*beneficiary.Beneficiary),
+		sequences:      make(map[string]int64),
+	}
+
+	if err := fn(uow); err != nil {
+		return err
+	}
+	if err := uow.verifyPostings(); err != nil {
+		return err
+	}
+
+	rec := &walRecord{Transactions: uow.transactions, Entries: uow.entries}
+	for _, quote := range uow.quotes {
+		rec.Quotes = append(rec.Quotes, quote)
+	}
+	for _, h := range uow.holds {
+		rec.Holds = append(rec.Holds, h)
+	}
+	for _, o := range uow.standingOrders {
+		rec.StandingOrders = append(rec.StandingOrders, o)
+	}
+	for _, b := range uow.batches {
+		rec.Batches = append(rec.Batches, b)
+	}
+	for _, c := range uow.customers {
+		rec.Customers = append(rec.Customers, c)
+	}
+	rec.DeletedCustomers = uow.deletedCustomers
+	for _, a := range uow.approvals {
+		rec.Approvals = append(rec.Approvals, a)
+	}
+	for _, b := range uow.beneficiaries {
+		rec.Beneficiaries = append(rec.Beneficiaries, b)
+	}
+	rec.DeletedBeneficiaries = uow.deletedBeneficiaries
+	if len(uow.sequences) > 0 {
+		rec.AccountSequences = uow.sequences
+	}
+	for _, key := range uow.idempotencyKeys {
+		if claimed, exists := s.idempotencyKeys[key]; exists && !claimed.Completed() {
+			committed := *claimed
+			committed.Committed = true
+			rec.IdempotencyKeys = append(rec.IdempotencyKeys, &committed)
+		}
+	}
+	// Units of work run under mu from staging to commit, so staged accounts
+	// cannot have changed since they were read.
+	for _, acc := range uow.accounts {
+		acc.Version++
+		rec.Accounts = append(rec.Accounts, acc)
+	}
+	if err := s.commit(rec); err != nil {
+		return err
+	}
+
+	for _, acc := range uow.created {
+		acc.Version = uow.accounts[acc.ID].Version
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetAllAccounts(ctx context.Context) []*account.Account {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	accounts := make([]*account.Account, 0, len(s.accounts))
 	for _, acc := range s.accounts {
-		accounts = append(accounts, acc)
+		copied := *acc
+		accounts = append(accounts, &copied)
 	}
 	return accounts
 }
 
-func (s *Store) GetAllTransactions() []*transaction.Transaction {
+func (s *MemoryStore) GetAllTransactions(ctx context.Context) []*transaction.Transaction {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	transactions := make([]*transaction.Transaction, 0, len(s.transactions))
 	for _, tx := range s.transactions {
-		transactions = append(transactions, tx)
+		copied := *tx
+		transactions = append(transactions, &copied)
 	}
 	return transactions
 }
 
-func (s *Store) Clear() {
+func (s *MemoryStore) GetJournalEntries(ctx context.Context) []*ledger.JournalEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*ledger.JournalEntry, len(s.entries))
+	copy(entries, s.entries)
+	return entries
+}
+
+func (s *MemoryStore) LedgerState(ctx context.Context) ([]*account.Account, []*ledger.JournalEntry) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accounts := make([]*account.Account, 0, len(s.accounts))
+	for _, acc := range s.accounts {
+		copied := *acc
+		accounts = append(accounts, &copied)
+	}
+
+	entries := make([]*ledger.JournalEntry, len(s.entries))
+	copy(entries, s.entries)
+	return accounts, entries
+}
+
+func (s *MemoryStore) Clear() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.accounts = make(map[string]*account.Account)
-	s.transactions = make(map[string]*transaction.Transaction)
+	return s.commit(&walRecord{Reset: true})
+}
+
+// commit journals rec, if a journal is configured, and then applies it. The
+// caller must hold mu.
+func (s *MemoryStore) commit(rec *walRecord) error {
+	if s.journal != nil {
+		if err := s.journal.append(rec); err != nil {
+			return err
+		}
+	}
+
+	s.applyRecord(rec)
+	if s.journal != nil {
+		s.journal.applied()
+	}
+	return nil
+}
+
+func (s *MemoryStore) applyRecord(rec *walRecord) {
+	if rec.Reset {
+		s.accounts = make(map[string]*account.Account)
+		s.transactions = make(map[string]*transaction.Transaction)
+		s.entries = nil
+		s.idempotencyKeys = make(map[string]*idempotency.Record)
+		s.accountTransactions = make(map[string][]string)
+		s.accountNumbers = make(map[string]string)
+		s.accountSequences = make(map[string]int64)
+		s.quotes = make(map[string]*fx.Quote)
+		s.holds = make(map[string]*hold.Hold)
+		s.standingOrders = make(map[string]*standingorder.StandingOrder)
+		s.batches = make(map[string]*batch.Batch)
+		s.customers = make(map[string]*customer.Customer)
+		s.approvals = make(map[string]*approval.Approval)
+		s.beneficiaries = make(map[string]*beneficiary.Beneficiary)
+	}
+
+	for _, acc := range rec.Accounts {
+		s.accounts[acc.ID] = acc
+		if acc.Number != "" {
+			s.accountNumbers[acc.Number] = acc.ID
+		}
+		if acc.IBAN != "" {
+			s.accountNumbers[acc.IBAN] = acc.ID
+		}
+	}
+	for prefix, seq := range rec.AccountSequences {
+		if seq > s.accountSequences[prefix] {
+			s.accountSequences[prefix] = seq
+		}
+	}
+	for _, tx := range rec.Transactions {
+		if _, exists := s.transactions[tx.ID]; !exists {
+			s.transactions[tx.ID] = tx
+			s.indexTransaction(tx)
+			continue
+		}
+		s.transactions[tx.ID] = tx
+	}
+	s.entries = append(s.entries, rec.Entries...)
+
+	for _, key := range rec.IdempotencyKeys {
+		s.idempotencyKeys[key.Key] = key
+	}
+	for _, key := range rec.DeletedIdempotencyKeys {
+		delete(s.idempotencyKeys, key)
+	}
+
+	for _, quote := range rec.Quotes {
+		s.quotes[quote.ID] = quote
+	}
+	for _, id := range rec.DeletedQuotes {
+		delete(s.quotes, id)
+	}
+
+	for _, h := range rec.Holds {
+		s.holds[h.ID] = h
+	}
+	for _, o := range rec.StandingOrders {
+		s.standingOrders[o.ID] = o
+	}
+	for _, b := range rec.Batches {
+		s.batches[b.ID] = b
+	}
+
+	for _, c := range rec.Customers {
+		s.customers[c.ID] = c
+	}
+	for _, id := range rec.DeletedCustomers {
+		delete(s.customers, id)
+	}
+
+	for _, a := range rec.Approvals {
+		s.approvals[a.ID] = a
+	}
+
+	for _, b := range rec.Beneficiaries {
+		s.beneficiaries[b.ID] = b
+	}
+	for _, id := range rec.DeletedBeneficiaries {
+		delete(s.beneficiaries, id)
+	}
 } 
\ No newline at end of file
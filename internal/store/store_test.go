@@ -1,21 +1,25 @@
 package store
 
 import (
+	"context"
 	"testing"
 
 	"banking-service/internal/account"
+	"banking-service/internal/ledger"
 	"banking-service/internal/transaction"
+	"banking-service/pkg/errors"
 )
 
-func TestNewStore(t *testing.T) {
-	store := NewStore()
+func TestNewMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
 	if store == nil {
-		t.Error("NewStore() returned nil")
+		t.Error("NewMemoryStore() returned nil")
 	}
 }
 
 func TestCreateAccount(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	account := &account.Account{
 		ID:           "test-id",
@@ -23,12 +27,12 @@ func TestCreateAccount(t *testing.T) {
 		Balance:      1000,
 	}
 
-	err := store.CreateAccount(account)
+	err := store.CreateAccount(ctx, account)
 	if err != nil {
 		t.Errorf("CreateAccount() error = %v", err)
 	}
 
-	retrievedAccount, err := store.GetAccount("test-id")
+	retrievedAccount, err := store.GetAccount(ctx, "test-id")
 	if err != nil {
 		t.Errorf("GetAccount() error = %v", err)
 	}
@@ -39,7 +43,8 @@ func TestCreateAccount(t *testing.T) {
 }
 
 func TestGetAccount(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	account := &account.Account{
 		ID:           "test-id",
@@ -47,9 +52,9 @@ func TestGetAccount(t *testing.T) {
 		Balance:      1000,
 	}
 
-	store.CreateAccount(account)
+	store.CreateAccount(ctx, account)
 
-	retrievedAccount, err := store.GetAccount("test-id")
+	retrievedAccount, err := store.GetAccount(ctx, "test-id")
 	if err != nil {
 		t.Errorf("GetAccount() error = %v", err)
 	}
@@ -58,14 +63,15 @@ func TestGetAccount(t *testing.T) {
 		t.Errorf("GetAccount() ID = %v, want %v", retrievedAccount.ID, account.ID)
 	}
 
-	_, err = store.GetAccount("non-existent")
+	_, err = store.GetAccount(ctx, "non-existent")
 	if err == nil {
 		t.Error("GetAccount() expected error for non-existent account")
 	}
 }
 
 func TestUpdateAccount(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	account := &account.Account{
 		ID:           "test-id",
@@ -73,15 +79,15 @@ func TestUpdateAccount(t *testing.T) {
 		Balance:      1000,
 	}
 
-	store.CreateAccount(account)
+	store.CreateAccount(ctx, account)
 
 	account.Balance = 2000
-	err := store.UpdateAccount(account)
+	err := store.UpdateAccount(ctx, account)
 	if err != nil {
 		t.Errorf("UpdateAccount() error = %v", err)
 	}
 
-	retrievedAccount, err := store.GetAccount("test-id")
+	retrievedAccount, err := store.GetAccount(ctx, "test-id")
 	if err != nil {
 		t.Errorf("GetAccount() error = %v", err)
 	}
@@ -92,7 +98,8 @@ func TestUpdateAccount(t *testing.T) {
 }
 
 func TestStoreTransaction(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	transaction := &transaction.Transaction{
 		ID:        "test-tx-id",
@@ -101,12 +108,12 @@ func TestStoreTransaction(t *testing.T) {
 		Amount:    1000,
 	}
 
-	err := store.StoreTransaction(transaction)
+	err := store.StoreTransaction(ctx, transaction)
 	if err != nil {
 		t.Errorf("StoreTransaction() error = %v", err)
 	}
 
-	retrievedTransaction, err := store.GetTransaction("test-tx-id")
+	retrievedTransaction, err := store.GetTransaction(ctx, "test-tx-id")
 	if err != nil {
 		t.Errorf("GetTransaction() error = %v", err)
 	}
@@ -117,7 +124,8 @@ func TestStoreTransaction(t *testing.T) {
 }
 
 func TestGetTransaction(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	transaction := &transaction.Transaction{
 		ID:        "test-tx-id",
@@ -126,9 +134,9 @@ func TestGetTransaction(t *testing.T) {
 		Amount:    1000,
 	}
 
-	store.StoreTransaction(transaction)
+	store.StoreTransaction(ctx, transaction)
 
-	retrievedTransaction, err := store.GetTransaction("test-tx-id")
+	retrievedTransaction, err := store.GetTransaction(ctx, "test-tx-id")
 	if err != nil {
 		t.Errorf("GetTransaction() error = %v", err)
 	}
@@ -137,14 +145,15 @@ func TestGetTransaction(t *testing.T) {
 		t.Errorf("GetTransaction() ID = %v, want %v", retrievedTransaction.ID, transaction.ID)
 	}
 
-	_, err = store.GetTransaction("non-existent")
+	_, err = store.GetTransaction(ctx, "non-existent")
 	if err == nil {
 		t.Error("GetTransaction() expected error for non-existent transaction")
 	}
 }
 
 func TestGetAllAccounts(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	account1 := &account.Account{
 		ID:           "test-id-1",
@@ -158,17 +167,18 @@ func TestGetAllAccounts(t *testing.T) {
 		Balance:      2000,
 	}
 
-	store.CreateAccount(account1)
-	store.CreateAccount(account2)
+	store.CreateAccount(ctx, account1)
+	store.CreateAccount(ctx, account2)
 
-	accounts := store.GetAllAccounts()
+	accounts := store.GetAllAccounts(ctx)
 	if len(accounts) != 2 {
 		t.Errorf("GetAllAccounts() returned %d accounts, want 2", len(accounts))
 	}
 }
 
 func TestGetAllTransactions(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	transaction1 := &transaction.Transaction{
 		ID:        "test-tx-id-1",
@@ -184,17 +194,18 @@ func TestGetAllTransactions(t *testing.T) {
 		Amount:    500,
 	}
 
-	store.StoreTransaction(transaction1)
-	store.StoreTransaction(transaction2)
+	store.StoreTransaction(ctx, transaction1)
+	store.StoreTransaction(ctx, transaction2)
 
-	transactions := store.GetAllTransactions()
+	transactions := store.GetAllTransactions(ctx)
 	if len(transactions) != 2 {
 		t.Errorf("GetAllTransactions() returned %d transactions, want 2", len(transactions))
 	}
 }
 
 func TestClear(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	account := &account.Account{
 		ID:           "test-id",
@@ -209,24 +220,25 @@ func TestClear(t *testing.T) {
 		Amount:    1000,
 	}
 
-	store.CreateAccount(account)
-	store.StoreTransaction(transaction)
+	store.CreateAccount(ctx, account)
+	store.StoreTransaction(ctx, transaction)
 
 	store.Clear()
 
-	accounts := store.GetAllAccounts()
+	accounts := store.GetAllAccounts(ctx)
 	if len(accounts) != 0 {
 		t.Errorf("Clear() accounts not cleared, got %d accounts", len(accounts))
 	}
 
-	transactions := store.GetAllTransactions()
+	transactions := store.GetAllTransactions(ctx)
 	if len(transactions) != 0 {
 		t.Errorf("Clear() transactions not cleared, got %d transactions", len(transactions))
 	}
 }
 
 func TestConcurrency(t *testing.T) {
-	store := NewStore()
+	ctx := context.Background()
+	store := NewMemoryStore()
 
 	done := make(chan bool, 10)
 
@@ -238,18 +250,18 @@ func TestConcurrency(t *testing.T) {
 				Balance:      1000,
 			}
 
-			err := store.CreateAccount(account)
+			err := store.CreateAccount(ctx, account)
 			if err != nil {
 				t.Errorf("CreateAccount() error in goroutine %d: %v", id, err)
 			}
 
 			account.Balance = 2000
-			err = store.UpdateAccount(account)
+			err = store.UpdateAccount(ctx, account)
 			if err != nil {
 				t.Errorf("UpdateAccount() error in goroutine %d: %v", id, err)
 			}
 
-			_, err = store.GetAccount(account.ID)
+			_, err = store.GetAccount(ctx, account.ID)
 			if err != nil {
 				t.Errorf("GetAccount() error in goroutine %d: %v", id, err)
 			}
@@ -262,8 +274,151 @@ func TestConcurrency(t *testing.T) {
 		<-done
 	}
 
-	accounts := store.GetAllAccounts()
+	accounts := store.GetAllAccounts(ctx)
 	if len(accounts) != 10 {
 		t.Errorf("Concurrency test failed, got %d accounts, want 10", len(accounts))
 	}
-} 
\ No newline at end of file
+} 
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	store.CreateAccount(ctx, &account.Account{ID: "from-id", CustomerName: "Nisha", Balance: 1000})
+	store.CreateAccount(ctx, &account.Account{ID: "to-id", CustomerName: "Rahul", Balance: 500})
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		from, err := uow.GetAccount("from-id")
+		if err != nil {
+			return err
+		}
+		to, err := uow.GetAccount("to-id")
+		if err != nil {
+			return err
+		}
+
+		from.Balance -= 300
+		to.Balance += 300
+		if err := uow.PostEntry(&ledger.JournalEntry{
+			ID:       "test-entry-id",
+			Postings: []ledger.Posting{ledger.Debit("from-id", "INR", 300), ledger.Credit("to-id", "INR", 300)},
+		}); err != nil {
+			return err
+		}
+		return uow.StoreTransaction(&transaction.Transaction{
+			ID:            "test-tx-id",
+			Type:          transaction.TransactionTypeTransfer,
+			FromAccountID: "from-id",
+			ToAccountID:   "to-id",
+			Amount:        300,
+		})
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	from, _ := store.GetAccount(ctx, "from-id")
+	to, _ := store.GetAccount(ctx, "to-id")
+	if from.Balance != 700 || to.Balance != 800 {
+		t.Errorf("Apply() balances = %v/%v, want 700/800", from.Balance, to.Balance)
+	}
+
+	if _, err := store.GetTransaction(ctx, "test-tx-id"); err != nil {
+		t.Errorf("Apply() transaction not stored: %v", err)
+	}
+}
+
+func TestApplyRollback(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	store.CreateAccount(ctx, &account.Account{ID: "from-id", CustomerName: "Nisha", Balance: 1000})
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		from, err := uow.GetAccount("from-id")
+		if err != nil {
+			return err
+		}
+
+		from.Balance -= 300
+		if err := uow.StoreTransaction(&transaction.Transaction{ID: "test-tx-id"}); err != nil {
+			return err
+		}
+
+		_, err = uow.GetAccount("non-existent")
+		return err
+	})
+	if err == nil {
+		t.Fatal("Apply() expected error for non-existent account")
+	}
+
+	from, _ := store.GetAccount(ctx, "from-id")
+	if from.Balance != 1000 {
+		t.Errorf("Apply() balance after rollback = %v, want 1000", from.Balance)
+	}
+
+	if _, err := store.GetTransaction(ctx, "test-tx-id"); err == nil {
+		t.Error("Apply() stored transaction from failed unit of work")
+	}
+}
+
+func TestApplyRejectsUnpostedBalanceChange(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	store.CreateAccount(ctx, &account.Account{ID: "test-id", CustomerName: "Priya", Balance: 1000})
+
+	err := store.Apply(ctx, func(uow UnitOfWork) error {
+		acc, err := uow.GetAccount("test-id")
+		if err != nil {
+			return err
+		}
+
+		acc.Balance += 500
+		return nil
+	})
+	if _, ok := err.(*errors.ErrLedgerImbalance); !ok {
+		t.Fatalf("Apply() error = %v, want *errors.ErrLedgerImbalance", err)
+	}
+
+	acc, _ := store.GetAccount(ctx, "test-id")
+	if acc.Balance != 1000 {
+		t.Errorf("Apply() balance = %v, want 1000", acc.Balance)
+	}
+}
+
+func TestGetAccountReturnsCopy(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	store.CreateAccount(ctx, &account.Account{ID: "test-id", CustomerName: "Anjali", Balance: 1000})
+
+	acc, _ := store.GetAccount(ctx, "test-id")
+	acc.Balance = 5000
+
+	retrievedAccount, _ := store.GetAccount(ctx, "test-id")
+	if retrievedAccount.Balance != 1000 {
+		t.Errorf("GetAccount() balance = %v, want 1000", retrievedAccount.Balance)
+	}
+}
+
+func TestUpdateAccountBumpsVersion(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	store.CreateAccount(ctx, &account.Account{ID: "test-id", CustomerName: "Deepak", Balance: 1000})
+
+	acc, _ := store.GetAccount(ctx, "test-id")
+	acc.Balance += 100
+	if err := store.UpdateAccount(ctx, acc); err != nil {
+		t.Fatalf("UpdateAccount() error = %v", err)
+	}
+	if acc.Version != 2 {
+		t.Errorf("UpdateAccount() version = %v, want 2", acc.Version)
+	}
+
+	retrievedAccount, _ := store.GetAccount(ctx, "test-id")
+	if retrievedAccount.Balance != 1100 || retrievedAccount.Version != 2 {
+		t.Errorf("UpdateAccount() stored balance %v at version %v, want 1100 at 2", retrievedAccount.Balance, retrievedAccount.Version)
+	}
+}
@@ -3,6 +3,8 @@ package transaction
 import (
 	"time"
 
+	"banking-service/pkg/money"
+
 	"github.com/google/uuid"
 )
 
@@ -12,6 +14,22 @@ const (
 	TransactionTypeDeposit   TransactionType = "deposit"
 	TransactionTypeWithdrawal TransactionType = "withdrawal"
 	TransactionTypeTransfer   TransactionType = "transfer"
+	
+	// A reversal undoes a whole transaction and a refund part of it. Both
+	// move money back between the original's accounts.
+	TransactionTypeReversal TransactionType = "reversal"
+	TransactionTypeRefund   TransactionType = "refund"
+	
+	// A fee is a charge the bank takes from the account; Reason says what
+	// for.
+	TransactionTypeFee TransactionType = "fee"
+	
+	// Interest is paid by the bank into the account.
+	TransactionTypeInterest TransactionType = "interest"
+	
+	// A move shifts money between an account and one of its pots. Moves are
+	// free and do not count towards transfer limits.
+	TransactionTypeMove TransactionType = "move"
 )
 
 type TransactionStatus string
@@ -20,6 +38,7 @@ const (
 	TransactionStatusPending   TransactionStatus = "pending"
 	TransactionStatusCompleted TransactionStatus = "completed"
 	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusReversed  TransactionStatus = "reversed"
 )
 
 type Transaction struct {
@@ -29,24 +48,163 @@ type Transaction struct {
 	FromAccountID string            `json:"from_account_id,omitempty"`
 	ToAccountID   string            `json:"to_account_id,omitempty"`
 	Amount        int64             `json:"amount"`
+	Currency      string            `json:"currency,omitempty"`
 	Timestamp     time.Time         `json:"timestamp"`
 	Status        TransactionStatus `json:"status"`
+	FailureCode   string            `json:"failure_code,omitempty"`
+	FailureReason string            `json:"failure_reason,omitempty"`
+
+	// Set on transfers between accounts in different currencies: Amount
+	// left the source account in Currency and TargetAmount arrived in
+	// TargetCurrency at Rate, locked by the fx quote QuoteID if one was used.
+	TargetAmount   int64  `json:"target_amount,omitempty"`
+	TargetCurrency string `json:"target_currency,omitempty"`
+	Rate           string `json:"rate,omitempty"`
+	QuoteID        string `json:"quote_id,omitempty"`
+
+	// HoldID is set on withdrawals that settle a captured hold.
+	HoldID string `json:"hold_id,omitempty"`
+
+	// Reversals and refunds point at the transaction they undo and keep the
+	// original's accounts; the original tracks how much has been returned.
+	OriginalTransactionID string   `json:"original_transaction_id,omitempty"`
+	Reason                string   `json:"reason,omitempty"`
+	RefundedAmount        int64    `json:"refunded_amount,omitempty"`
+	ReversalIDs           []string `json:"reversal_ids,omitempty"`
+
+	// A withdrawal or transfer that was charged a fee points at the fee
+	// transaction, and the fee back at what it was charged for.
+	FeeTransactionID     string `json:"fee_transaction_id,omitempty"`
+	RelatedTransactionID string `json:"related_transaction_id,omitempty"`
+
+	// BatchID is set on transfers made as a leg of a batch.
+	BatchID string `json:"batch_id,omitempty"`
+}
+
+// IsReversal reports whether the transaction undoes (part of) another.
+func (t *Transaction) IsReversal() bool {
+	return t.Type == TransactionTypeReversal || t.Type == TransactionTypeRefund
+}
+
+// IsConverted reports whether the transaction exchanged one currency for
+// another.
+func (t *Transaction) IsConverted() bool {
+	return t.TargetCurrency != "" && t.TargetCurrency != t.Currency
+}
+
+// Filter narrows a transaction listing. Zero values mean "no constraint";
+// From is inclusive and To exclusive.
+type Filter struct {
+	Types     []TransactionType
+	Statuses  []TransactionStatus
+	MinAmount *int64
+	MaxAmount *int64
+	From      time.Time
+	To        time.Time
+}
+
+func (f Filter) Matches(tx *Transaction) bool {
+	if len(f.Types) > 0 && !containsType(f.Types, tx.Type) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, tx.Status) {
+		return false
+	}
+	if f.MinAmount != nil && tx.Amount < *f.MinAmount {
+		return false
+	}
+	if f.MaxAmount != nil && tx.Amount > *f.MaxAmount {
+		return false
+	}
+	if !f.From.IsZero() && tx.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !tx.Timestamp.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+func containsType(types []TransactionType, t TransactionType) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(statuses []TransactionStatus, status TransactionStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountIDs returns every account the transaction touches.
+func (t *Transaction) AccountIDs() []string {
+	var ids []string
+	for _, id := range []string{t.AccountID, t.FromAccountID, t.ToAccountID} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 type DepositRequest struct {
 	AccountID string `json:"account_id"`
 	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency,omitempty"`
 }
 
 type WithdrawRequest struct {
 	AccountID string `json:"account_id"`
 	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency,omitempty"`
 }
 
+// TransferRequest moves money between two accounts. Transfers between
+// currencies must name an FX quote, which fixes the rate.
 type TransferRequest struct {
 	FromAccountID string `json:"from_account_id"`
 	ToAccountID   string `json:"to_account_id"`
 	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency,omitempty"`
+	QuoteID       string `json:"quote_id,omitempty"`
+}
+
+// MoveRequest moves money between an account and one of its pots.
+type MoveRequest struct {
+	FromAccountID string `json:"from_account_id"`
+	ToAccountID   string `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+}
+
+// ReverseRequest undoes a transaction. Amount defaults to everything not yet
+// refunded; anything less is a partial refund.
+type ReverseRequest struct {
+	Amount int64  `json:"amount,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// QuoteRequest asks what a withdrawal or transfer of Amount out of AccountID
+// would cost before it is made.
+type QuoteRequest struct {
+	Type      TransactionType `json:"type"`
+	AccountID string          `json:"account_id"`
+	Amount    int64           `json:"amount"`
+}
+
+type QuoteResponse struct {
+	Type      TransactionType `json:"type"`
+	AccountID string          `json:"account_id"`
+	Amount    int64           `json:"amount"`
+	Fee       int64           `json:"fee"`
+	Total     int64           `json:"total"`
+	Currency  string          `json:"currency"`
 }
 
 type TransactionResponse struct {
@@ -60,47 +218,145 @@ func NewService() *Service {
 	return &Service{}
 }
 
-func (s *Service) CreateDepositTransaction(accountID string, amount int64) *Transaction {
+func (s *Service) CreateDepositTransaction(accountID string, amount int64, currency string) *Transaction {
 	return &Transaction{
 		ID:        uuid.New().String(),
 		Type:      TransactionTypeDeposit,
 		AccountID: accountID,
 		Amount:    amount,
+		Currency:  currency,
 		Timestamp: time.Now(),
 		Status:    TransactionStatusCompleted,
 	}
 }
 
-func (s *Service) CreateWithdrawalTransaction(accountID string, amount int64) *Transaction {
+func (s *Service) CreateWithdrawalTransaction(accountID string, amount int64, currency string) *Transaction {
 	return &Transaction{
 		ID:        uuid.New().String(),
 		Type:      TransactionTypeWithdrawal,
 		AccountID: accountID,
 		Amount:    amount,
+		Currency:  currency,
 		Timestamp: time.Now(),
 		Status:    TransactionStatusCompleted,
 	}
 }
 
-func (s *Service) CreateTransferTransaction(fromAccountID, toAccountID string, amount int64) *Transaction {
+func (s *Service) CreateTransferTransaction(fromAccountID, toAccountID string, amount int64, currency string) *Transaction {
 	return &Transaction{
 		ID:            uuid.New().String(),
 		Type:          TransactionTypeTransfer,
 		FromAccountID: fromAccountID,
 		ToAccountID:   toAccountID,
 		Amount:        amount,
+		Currency:      currency,
 		Timestamp:     time.Now(),
 		Status:        TransactionStatusCompleted,
 	}
 }
 
-func (s *Service) CreateFailedTransaction(txType TransactionType, accountID string, amount int64, reason string) *Transaction {
+func (s *Service) CreateMoveTransaction(fromAccountID, toAccountID string, amount int64, currency string) *Transaction {
+	return &Transaction{
+		ID:            uuid.New().String(),
+		Type:          TransactionTypeMove,
+		FromAccountID: fromAccountID,
+		ToAccountID:   toAccountID,
+		Amount:        amount,
+		Currency:      currency,
+		Timestamp:     time.Now(),
+		Status:        TransactionStatusCompleted,
+	}
+}
+
+func (s *Service) CreateConvertedTransferTransaction(fromAccountID, toAccountID string, conv money.Conversion) *Transaction {
+	tx := s.CreateTransferTransaction(fromAccountID, toAccountID, conv.Source.Amount, conv.Source.Currency)
+	tx.TargetAmount = conv.Target.Amount
+	tx.TargetCurrency = conv.Target.Currency
+	tx.Rate = conv.Rate
+	return tx
+}
+
+// CreateReversalTransaction builds the transaction returning amount of
+// original. A reversal returns the whole original; anything less is a refund.
+func (s *Service) CreateReversalTransaction(original *Transaction, amount int64, reason string) *Transaction {
+	txType := TransactionTypeRefund
+	if original.RefundedAmount == 0 && amount == original.Amount {
+		txType = TransactionTypeReversal
+	}
+
+	tx := &Transaction{
+		ID:                    uuid.New().String(),
+		Type:                  txType,
+		AccountID:             original.AccountID,
+		FromAccountID:         original.FromAccountID,
+		ToAccountID:           original.ToAccountID,
+		Amount:                amount,
+		Currency:              original.Currency,
+		Timestamp:             time.Now(),
+		Status:                TransactionStatusCompleted,
+		OriginalTransactionID: original.ID,
+		Reason:                reason,
+	}
+	if original.IsConverted() {
+		tx.TargetAmount = original.TargetAmount
+		tx.TargetCurrency = original.TargetCurrency
+		tx.Rate = original.Rate
+	}
+	return tx
+}
+
+// MarkRefunded records that reversal returned part or all of original.
+func (s *Service) MarkRefunded(original, reversal *Transaction) {
+	original.RefundedAmount += reversal.Amount
+	original.ReversalIDs = append(original.ReversalIDs, reversal.ID)
+	if original.RefundedAmount >= original.Amount {
+		original.Status = TransactionStatusReversed
+	}
+}
+
+func (s *Service) CreateFeeTransaction(accountID string, amount int64, currency, reason string) *Transaction {
 	return &Transaction{
 		ID:        uuid.New().String(),
-		Type:      txType,
+		Type:      TransactionTypeFee,
 		AccountID: accountID,
 		Amount:    amount,
+		Currency:  currency,
 		Timestamp: time.Now(),
-		Status:    TransactionStatusFailed,
+		Status:    TransactionStatusCompleted,
+		Reason:    reason,
+	}
+}
+
+// CreateInterestTransaction records interest paid for the period ending at
+// valueDate, which becomes the transaction's timestamp.
+func (s *Service) CreateInterestTransaction(accountID string, amount int64, currency string, valueDate time.Time) *Transaction {
+	return &Transaction{
+		ID:        uuid.New().String(),
+		Type:      TransactionTypeInterest,
+		AccountID: accountID,
+		Amount:    amount,
+		Currency:  currency,
+		Timestamp: valueDate,
+		Status:    TransactionStatusCompleted,
+	}
+}
+
+func (s *Service) CreateFailedTransaction(txType TransactionType, accountID string, amount int64, reason string) *Transaction {
+	return &Transaction{
+		ID:            uuid.New().String(),
+		Type:          txType,
+		AccountID:     accountID,
+		Amount:        amount,
+		Timestamp:     time.Now(),
+		Status:        TransactionStatusFailed,
+		FailureReason: reason,
 	}
-} 
\ No newline at end of file
+}
+
+// MarkFailed records that tx was declined, with a machine-readable code and
+// a human-readable reason.
+func (s *Service) MarkFailed(tx *Transaction, code, reason string) {
+	tx.Status = TransactionStatusFailed
+	tx.FailureCode = code
+	tx.FailureReason = reason
+}
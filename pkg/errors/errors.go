@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type ErrAccountNotFound struct {
 	AccountID string
@@ -10,14 +13,21 @@ func (e ErrAccountNotFound) Error() string {
 	return fmt.Sprintf("account not found: %s", e.AccountID)
 }
 
+// ErrInsufficientFunds reports the account balance and the funds actually
+// available to spend, which also count any overdraft and exclude holds.
 type ErrInsufficientFunds struct {
 	AccountID string
 	Balance   int64
+	Available int64
 	Amount    int64
+	Currency  string
 }
 
 func (e ErrInsufficientFunds) Error() string {
-	return fmt.Sprintf("insufficient funds in account %s: balance %d, requested %d", e.AccountID, e.Balance, e.Amount)
+	if e.Currency != "" {
+		return fmt.Sprintf("insufficient funds in account %s: balance %d %s, available %d %s, requested %d %s", e.AccountID, e.Balance, e.Currency, e.Available, e.Currency, e.Amount, e.Currency)
+	}
+	return fmt.Sprintf("insufficient funds in account %s: balance %d, available %d, requested %d", e.AccountID, e.Balance, e.Available, e.Amount)
 }
 
 type ErrInvalidAmount struct {
@@ -60,4 +70,527 @@ type ErrSameAccountTransfer struct {
 
 func (e ErrSameAccountTransfer) Error() string {
 	return fmt.Sprintf("cannot transfer to same account: from %s to %s", e.FromAccountID, e.ToAccountID)
-} 
\ No newline at end of file
+} 
+
+type ErrLedgerImbalance struct {
+	EntryID   string
+	AccountID string
+	Expected  int64
+	Actual    int64
+}
+
+func (e ErrLedgerImbalance) Error() string {
+	switch {
+	case e.AccountID != "":
+		return fmt.Sprintf("ledger out of balance for account %s: balance %d, postings %d", e.AccountID, e.Expected, e.Actual)
+	case e.EntryID != "":
+		return fmt.Sprintf("journal entry %s does not balance: postings sum to %d", e.EntryID, e.Actual)
+	default:
+		return fmt.Sprintf("ledger out of balance: postings sum to %d", e.Actual)
+	}
+}
+
+type ErrTransactionNotFound struct {
+	TransactionID string
+}
+
+func (e ErrTransactionNotFound) Error() string {
+	return fmt.Sprintf("transaction not found: %s", e.TransactionID)
+}
+
+type ErrInvalidCursor struct {
+	Cursor string
+}
+
+func (e ErrInvalidCursor) Error() string {
+	return fmt.Sprintf("invalid pagination cursor: %s", e.Cursor)
+}
+
+type ErrInvalidParameter struct {
+	Name  string
+	Value string
+}
+
+func (e ErrInvalidParameter) Error() string {
+	return fmt.Sprintf("invalid value for %s: %q", e.Name, e.Value)
+}
+
+type ErrAccountFrozen struct {
+	AccountID string
+}
+
+func (e ErrAccountFrozen) Error() string {
+	return fmt.Sprintf("account %s is frozen", e.AccountID)
+}
+
+type ErrAccountDormant struct {
+	AccountID string
+}
+
+func (e ErrAccountDormant) Error() string {
+	return fmt.Sprintf("account %s is dormant", e.AccountID)
+}
+
+type ErrAccountClosed struct {
+	AccountID string
+}
+
+func (e ErrAccountClosed) Error() string {
+	return fmt.Sprintf("account %s is closed", e.AccountID)
+}
+
+type ErrInvalidStatusTransition struct {
+	AccountID string
+	From      string
+	To        string
+}
+
+func (e ErrInvalidStatusTransition) Error() string {
+	return fmt.Sprintf("account %s cannot move from %s to %s", e.AccountID, e.From, e.To)
+}
+
+type ErrAccountBalanceNotZero struct {
+	AccountID string
+	Balance   int64
+}
+
+func (e ErrAccountBalanceNotZero) Error() string {
+	return fmt.Sprintf("account %s cannot be closed with non-zero balance %d", e.AccountID, e.Balance)
+}
+
+type ErrUnsupportedCurrency struct {
+	Currency string
+}
+
+func (e ErrUnsupportedCurrency) Error() string {
+	return fmt.Sprintf("unsupported currency: %q", e.Currency)
+}
+
+type ErrCurrencyMismatch struct {
+	AccountID string
+	Expected  string
+	Actual    string
+}
+
+func (e ErrCurrencyMismatch) Error() string {
+	if e.AccountID != "" {
+		return fmt.Sprintf("currency mismatch for account %s: account is %s, got %s", e.AccountID, e.Expected, e.Actual)
+	}
+	return fmt.Sprintf("currency mismatch: expected %s, got %s", e.Expected, e.Actual)
+}
+
+type ErrInvalidConversion struct {
+	Reason string
+}
+
+func (e ErrInvalidConversion) Error() string {
+	return fmt.Sprintf("invalid currency conversion: %s", e.Reason)
+}
+
+type ErrRateUnavailable struct {
+	From string
+	To   string
+}
+
+func (e ErrRateUnavailable) Error() string {
+	return fmt.Sprintf("no exchange rate available from %s to %s", e.From, e.To)
+}
+
+type ErrQuoteNotFound struct {
+	QuoteID string
+}
+
+func (e ErrQuoteNotFound) Error() string {
+	return fmt.Sprintf("fx quote not found: %s", e.QuoteID)
+}
+
+type ErrQuoteExpired struct {
+	QuoteID string
+}
+
+func (e ErrQuoteExpired) Error() string {
+	return fmt.Sprintf("fx quote %s has expired", e.QuoteID)
+}
+
+type ErrQuoteAlreadyUsed struct {
+	QuoteID       string
+	TransactionID string
+}
+
+func (e ErrQuoteAlreadyUsed) Error() string {
+	return fmt.Sprintf("fx quote %s was already used by transaction %s", e.QuoteID, e.TransactionID)
+}
+
+type ErrHoldNotFound struct {
+	HoldID string
+}
+
+func (e ErrHoldNotFound) Error() string {
+	return fmt.Sprintf("hold not found: %s", e.HoldID)
+}
+
+type ErrHoldNotActive struct {
+	HoldID string
+	Status string
+}
+
+func (e ErrHoldNotActive) Error() string {
+	return fmt.Sprintf("hold %s is %s", e.HoldID, e.Status)
+}
+
+type ErrHoldExpired struct {
+	HoldID string
+}
+
+func (e ErrHoldExpired) Error() string {
+	return fmt.Sprintf("hold %s has expired", e.HoldID)
+}
+
+type ErrCaptureExceedsHold struct {
+	HoldID string
+	Held   int64
+	Amount int64
+}
+
+func (e ErrCaptureExceedsHold) Error() string {
+	return fmt.Sprintf("cannot capture %d from hold %s of %d", e.Amount, e.HoldID, e.Held)
+}
+
+type ErrAccountHasHolds struct {
+	AccountID string
+	Held      int64
+}
+
+func (e ErrAccountHasHolds) Error() string {
+	return fmt.Sprintf("account %s has %d held by active holds", e.AccountID, e.Held)
+}
+
+type ErrTransactionNotReversible struct {
+	TransactionID string
+	Reason        string
+}
+
+func (e ErrTransactionNotReversible) Error() string {
+	return fmt.Sprintf("transaction %s cannot be reversed: %s", e.TransactionID, e.Reason)
+}
+
+type ErrAlreadyReversed struct {
+	TransactionID string
+}
+
+func (e ErrAlreadyReversed) Error() string {
+	return fmt.Sprintf("transaction %s has already been reversed", e.TransactionID)
+}
+
+type ErrRefundExceedsOriginal struct {
+	TransactionID string
+	Remaining     int64
+	Amount        int64
+}
+
+func (e ErrRefundExceedsOriginal) Error() string {
+	return fmt.Sprintf("cannot refund %d of transaction %s: only %d remains", e.Amount, e.TransactionID, e.Remaining)
+}
+
+type ErrOverdraftLimitTooLow struct {
+	AccountID string
+	Limit     int64
+	Balance   int64
+}
+
+func (e ErrOverdraftLimitTooLow) Error() string {
+	return fmt.Sprintf("overdraft limit %d for account %s does not cover its balance of %d", e.Limit, e.AccountID, e.Balance)
+}
+
+// ErrLimitExceeded reports a transaction that would break one of the
+// account's limits. Measure is "amount" or "count", and Max and Remaining are
+// in that unit; Remaining is what is left in the current period.
+type ErrLimitExceeded struct {
+	AccountID       string
+	TransactionType string
+	Period          string
+	Measure         string
+	Max             int64
+	Remaining       int64
+}
+
+func (e ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("%s %s %s limit of %d exceeded for account %s: %d remaining", e.Period, e.TransactionType, e.Measure, e.Max, e.AccountID, e.Remaining)
+}
+
+type ErrStandingOrderNotFound struct {
+	StandingOrderID string
+}
+
+func (e ErrStandingOrderNotFound) Error() string {
+	return fmt.Sprintf("standing order not found: %s", e.StandingOrderID)
+}
+
+type ErrStandingOrderNotActive struct {
+	StandingOrderID string
+	Status          string
+}
+
+func (e ErrStandingOrderNotActive) Error() string {
+	return fmt.Sprintf("standing order %s is %s", e.StandingOrderID, e.Status)
+}
+
+type ErrBatchNotFound struct {
+	BatchID string
+}
+
+func (e ErrBatchNotFound) Error() string {
+	return fmt.Sprintf("batch not found: %s", e.BatchID)
+}
+
+type ErrCustomerNotFound struct {
+	CustomerID string
+}
+
+func (e ErrCustomerNotFound) Error() string {
+	return fmt.Sprintf("customer not found: %s", e.CustomerID)
+}
+
+type ErrCustomerHasAccounts struct {
+	CustomerID string
+	Accounts   int
+}
+
+func (e ErrCustomerHasAccounts) Error() string {
+	return fmt.Sprintf("customer %s still has %d open accounts", e.CustomerID, e.Accounts)
+}
+
+// ErrCallerNotAuthorized means the caller is not a holder allowed to act on
+// the account. An empty CustomerID means the caller did not identify
+// themselves.
+type ErrCallerNotAuthorized struct {
+	AccountID  string
+	CustomerID string
+	Action     string
+}
+
+func (e ErrCallerNotAuthorized) Error() string {
+	if e.CustomerID == "" {
+		return fmt.Sprintf("a holder of account %s must identify themselves to %s", e.AccountID, e.Action)
+	}
+	return fmt.Sprintf("customer %s is not authorized to %s on account %s", e.CustomerID, e.Action, e.AccountID)
+}
+
+type ErrHolderNotFound struct {
+	AccountID  string
+	CustomerID string
+}
+
+func (e ErrHolderNotFound) Error() string {
+	return fmt.Sprintf("customer %s is not a holder of account %s", e.CustomerID, e.AccountID)
+}
+
+type ErrInvalidHolderChange struct {
+	AccountID  string
+	CustomerID string
+	Reason     string
+}
+
+func (e ErrInvalidHolderChange) Error() string {
+	return fmt.Sprintf("cannot change holder %s of account %s: %s", e.CustomerID, e.AccountID, e.Reason)
+}
+
+type ErrInvalidMandate struct {
+	AccountID string
+	Mandate   string
+	Reason    string
+}
+
+func (e ErrInvalidMandate) Error() string {
+	return fmt.Sprintf("invalid mandate %q for account %s: %s", e.Mandate, e.AccountID, e.Reason)
+}
+
+type ErrApprovalNotFound struct {
+	ApprovalID string
+}
+
+func (e ErrApprovalNotFound) Error() string {
+	return fmt.Sprintf("approval not found: %s", e.ApprovalID)
+}
+
+type ErrApprovalNotPending struct {
+	ApprovalID string
+	Status     string
+}
+
+func (e ErrApprovalNotPending) Error() string {
+	return fmt.Sprintf("approval %s is %s", e.ApprovalID, e.Status)
+}
+
+type ErrAlreadyApproved struct {
+	ApprovalID string
+	CustomerID string
+}
+
+func (e ErrAlreadyApproved) Error() string {
+	return fmt.Sprintf("customer %s has already approved %s", e.CustomerID, e.ApprovalID)
+}
+
+// ErrApprovalRequired means the account's mandate needs other holders to
+// approve the caller's payments, and the payment asked for cannot wait for
+// them.
+type ErrApprovalRequired struct {
+	AccountID  string
+	CustomerID string
+	Action     string
+}
+
+func (e ErrApprovalRequired) Error() string {
+	return fmt.Sprintf("customer %s cannot %s on account %s without other holders' approval", e.CustomerID, e.Action, e.AccountID)
+}
+
+// ErrInvalidAccountNumber means an account number or IBAN has wrong check
+// digits, usually because it was mistyped.
+type ErrInvalidAccountNumber struct {
+	Number string
+}
+
+func (e ErrInvalidAccountNumber) Error() string {
+	return fmt.Sprintf("invalid account number: %s", e.Number)
+}
+
+type ErrBeneficiaryNotFound struct {
+	BeneficiaryID string
+}
+
+func (e ErrBeneficiaryNotFound) Error() string {
+	return fmt.Sprintf("beneficiary not found: %s", e.BeneficiaryID)
+}
+
+type ErrBeneficiaryExists struct {
+	AccountID      string
+	PayeeAccountID string
+	BeneficiaryID  string
+}
+
+func (e ErrBeneficiaryExists) Error() string {
+	return fmt.Sprintf("account %s is already a beneficiary of %s: %s", e.PayeeAccountID, e.AccountID, e.BeneficiaryID)
+}
+
+// ErrBeneficiaryRequired means a transfer was made to an account that is not
+// a registered payee while registration is required.
+type ErrBeneficiaryRequired struct {
+	AccountID      string
+	PayeeAccountID string
+}
+
+func (e ErrBeneficiaryRequired) Error() string {
+	return fmt.Sprintf("account %s must be added as a beneficiary of %s before transferring to it", e.PayeeAccountID, e.AccountID)
+}
+
+type ErrBeneficiaryNotVerified struct {
+	BeneficiaryID string
+	NameMatch     string
+}
+
+func (e ErrBeneficiaryNotVerified) Error() string {
+	if e.NameMatch == "" {
+		return fmt.Sprintf("beneficiary %s has not been verified", e.BeneficiaryID)
+	}
+	return fmt.Sprintf("beneficiary %s has not been verified: %s", e.BeneficiaryID, e.NameMatch)
+}
+
+// ErrBeneficiaryCoolingOff reports a transfer that would take what has been
+// sent to a newly added payee past the cooling-off limit. Remaining is what
+// may still be sent before Until. For a payee that is not registered,
+// BeneficiaryID is empty and the cap does not end.
+type ErrBeneficiaryCoolingOff struct {
+	BeneficiaryID  string
+	PayeeAccountID string
+	Until          time.Time
+	Max            int64
+	Remaining      int64
+}
+
+func (e ErrBeneficiaryCoolingOff) Error() string {
+	if e.BeneficiaryID == "" {
+		return fmt.Sprintf("account %s is not a registered payee, so transfers to it are capped: %d of %d remaining", e.PayeeAccountID, e.Remaining, e.Max)
+	}
+	return fmt.Sprintf("beneficiary %s is cooling off until %s: %d of %d remaining", e.BeneficiaryID, e.Until.Format(time.RFC3339), e.Remaining, e.Max)
+}
+
+// ErrPotRestricted means money was paid into or out of a pot other than by
+// a move to or from its parent account.
+type ErrPotRestricted struct {
+	AccountID string
+	ParentID  string
+}
+
+func (e ErrPotRestricted) Error() string {
+	return fmt.Sprintf("account %s is a pot of %s: money can only be moved between them", e.AccountID, e.ParentID)
+}
+
+type ErrInvalidPotMove struct {
+	FromAccountID string
+	ToAccountID   string
+}
+
+func (e ErrInvalidPotMove) Error() string {
+	return fmt.Sprintf("money can only be moved between an account and its own pots: %s to %s", e.FromAccountID, e.ToAccountID)
+}
+
+type ErrInvalidPot struct {
+	AccountID string
+	Reason    string
+}
+
+func (e ErrInvalidPot) Error() string {
+	return fmt.Sprintf("cannot open a pot in account %s: %s", e.AccountID, e.Reason)
+}
+
+// Code returns a stable, machine-readable code for errors that decline a
+// money movement, or "" for errors that do not, such as storage failures.
+func Code(err error) string {
+	switch err.(type) {
+	case *ErrAccountNotFound:
+		return "account_not_found"
+	case *ErrInsufficientFunds:
+		return "insufficient_funds"
+	case *ErrInvalidAmount:
+		return "invalid_amount"
+	case *ErrSameAccountTransfer:
+		return "same_account_transfer"
+	case *ErrAccountFrozen:
+		return "account_frozen"
+	case *ErrAccountDormant:
+		return "account_dormant"
+	case *ErrAccountClosed:
+		return "account_closed"
+	case *ErrUnsupportedCurrency:
+		return "unsupported_currency"
+	case *ErrCurrencyMismatch:
+		return "currency_mismatch"
+	case *ErrInvalidConversion:
+		return "invalid_conversion"
+	case *ErrQuoteNotFound:
+		return "quote_not_found"
+	case *ErrQuoteExpired:
+		return "quote_expired"
+	case *ErrQuoteAlreadyUsed:
+		return "quote_already_used"
+	case *ErrLimitExceeded:
+		return "limit_exceeded"
+	case *ErrCallerNotAuthorized:
+		return "caller_not_authorized"
+	case *ErrApprovalRequired:
+		return "approval_required"
+	case *ErrInvalidAccountNumber:
+		return "invalid_account_number"
+	case *ErrBeneficiaryRequired:
+		return "beneficiary_required"
+	case *ErrBeneficiaryNotVerified:
+		return "beneficiary_not_verified"
+	case *ErrBeneficiaryCoolingOff:
+		return "beneficiary_cooling_off"
+	case *ErrPotRestricted:
+		return "pot_restricted"
+	case *ErrInvalidPotMove:
+		return "invalid_pot_move"
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"banking-service/pkg/errors"
+)
+
+// DefaultCurrency is assumed for accounts and requests that predate
+// multi-currency support.
+const DefaultCurrency = "INR"
+
+// exponents holds the number of minor-unit digits for each supported ISO 4217
+// currency.
+var exponents = map[string]int{
+	"AED": 2,
+	"AUD": 2,
+	"BHD": 3,
+	"CAD": 2,
+	"CHF": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"SGD": 2,
+	"USD": 2,
+}
+
+// Money is an amount in the minor units of its currency, e.g. paise for INR.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func Exponent(currency string) (int, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return 0, &errors.ErrUnsupportedCurrency{Currency: currency}
+	}
+	return exp, nil
+}
+
+func ValidateCurrency(currency string) error {
+	_, err := Exponent(currency)
+	return err
+}
+
+func New(amount int64, currency string) (Money, error) {
+	if err := ValidateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, &errors.ErrCurrencyMismatch{Expected: m.Currency, Actual: other.Currency}
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, &errors.ErrCurrencyMismatch{Expected: m.Currency, Actual: other.Currency}
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul multiplies m by factor, rounding half-to-even to m's minor unit.
+func (m Money) Mul(factor *big.Rat) Money {
+	value := new(big.Rat).SetInt64(m.Amount)
+	value.Mul(value, factor)
+	return Money{Amount: roundHalfEven(value).Int64(), Currency: m.Currency}
+}
+
+// String formats the amount in major units, e.g. "1234.50 INR".
+func (m Money) String() string {
+	exp, err := Exponent(m.Currency)
+	if err != nil || exp == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%0*d", exp+1, amount)
+	split := len(digits) - exp
+	return fmt.Sprintf("%s%s.%s %s", sign, digits[:split], digits[split:], m.Currency)
+}
+
+// Conversion records an exchange of Source into Target at Rate, where Rate is
+// a decimal number of Target major units per Source major unit.
+type Conversion struct {
+	Source Money  `json:"source"`
+	Target Money  `json:"target"`
+	Rate   string `json:"rate"`
+}
+
+// Validate checks that Target is exactly what Convert produces for Source at
+// Rate.
+func (c Conversion) Validate() error {
+	want, err := Convert(c.Source, c.Target.Currency, c.Rate)
+	if err != nil {
+		return err
+	}
+	if want != c.Target {
+		return &errors.ErrInvalidConversion{
+			Reason: fmt.Sprintf("%s at rate %s is %s, not %s", c.Source, c.Rate, want, c.Target),
+		}
+	}
+	return nil
+}
+
+// Convert exchanges src into the target currency at rate. The result is
+// rounded to the target's minor unit with round-half-to-even (banker's
+// rounding), so the same inputs always give the same amount.
+func Convert(src Money, targetCurrency, rate string) (Money, error) {
+	srcExp, err := Exponent(src.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	targetExp, err := Exponent(targetCurrency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	r, err := ParseRate(rate)
+	if err != nil {
+		return Money{}, err
+	}
+
+	value := new(big.Rat).SetInt64(src.Amount)
+	value.Mul(value, r)
+	value.Mul(value, pow10Rat(targetExp-srcExp))
+
+	amount := roundHalfEven(value)
+	if !amount.IsInt64() {
+		return Money{}, &errors.ErrInvalidConversion{Reason: "converted amount overflows"}
+	}
+	return Money{Amount: amount.Int64(), Currency: targetCurrency}, nil
+}
+
+// ParseRate parses a positive decimal exchange rate such as "83.125".
+func ParseRate(rate string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || r.Sign() <= 0 || strings.ContainsAny(rate, "/eE") {
+		return nil, &errors.ErrInvalidConversion{Reason: fmt.Sprintf("invalid exchange rate %q", rate)}
+	}
+	return r, nil
+}
+
+func pow10Rat(exp int) *big.Rat {
+	if exp >= 0 {
+		return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	}
+	return new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+}
+
+func roundHalfEven(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	twice := new(big.Int).Mul(rem, big.NewInt(2))
+
+	switch twice.Cmp(den) {
+	case 1:
+		q.Add(q, big.NewInt(1))
+	case 0:
+		if q.Bit(0) == 1 {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q
+}
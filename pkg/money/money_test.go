@@ -0,0 +1,123 @@
+package money
+
+import (
+	"testing"
+
+	"banking-service/pkg/errors"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 123450, Currency: "INR"}, "1234.50 INR"},
+		{Money{Amount: 5, Currency: "USD"}, "0.05 USD"},
+		{Money{Amount: -250, Currency: "EUR"}, "-2.50 EUR"},
+		{Money{Amount: 1500, Currency: "JPY"}, "1500 JPY"},
+		{Money{Amount: 1234, Currency: "KWD"}, "1.234 KWD"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestAddCurrencyMismatch(t *testing.T) {
+	_, err := Money{Amount: 100, Currency: "INR"}.Add(Money{Amount: 100, Currency: "USD"})
+	if _, ok := err.(*errors.ErrCurrencyMismatch); !ok {
+		t.Errorf("Add() error = %v, want *errors.ErrCurrencyMismatch", err)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   Money
+		currency string
+		rate     string
+		want     Money
+		wantErr  bool
+	}{
+		{
+			name:     "usd_to_inr",
+			source:   Money{Amount: 1000, Currency: "USD"},
+			currency: "INR",
+			rate:     "83.125",
+			want:     Money{Amount: 83125, Currency: "INR"},
+		},
+		{
+			name:     "half_rounds_down_to_even",
+			source:   Money{Amount: 1, Currency: "USD"},
+			currency: "EUR",
+			rate:     "0.5",
+			want:     Money{Amount: 0, Currency: "EUR"},
+		},
+		{
+			name:     "half_rounds_up_to_even",
+			source:   Money{Amount: 3, Currency: "USD"},
+			currency: "EUR",
+			rate:     "0.5",
+			want:     Money{Amount: 2, Currency: "EUR"},
+		},
+		{
+			name:     "different_exponents",
+			source:   Money{Amount: 10000, Currency: "INR"},
+			currency: "JPY",
+			rate:     "1.805",
+			want:     Money{Amount: 180, Currency: "JPY"},
+		},
+		{
+			name:     "invalid_rate",
+			source:   Money{Amount: 100, Currency: "USD"},
+			currency: "INR",
+			rate:     "-1",
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported_currency",
+			source:   Money{Amount: 100, Currency: "USD"},
+			currency: "XXX",
+			rate:     "1",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.source, tt.currency, tt.rate)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Convert() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Convert() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConversionValidate(t *testing.T) {
+	conv := Conversion{
+		Source: Money{Amount: 1000, Currency: "USD"},
+		Target: Money{Amount: 83125, Currency: "INR"},
+		Rate:   "83.125",
+	}
+	if err := conv.Validate(); err != nil {
+		t.Errorf("Validate() unexpected error = %v", err)
+	}
+
+	conv.Target.Amount = 90000
+	if _, ok := conv.Validate().(*errors.ErrInvalidConversion); !ok {
+		t.Error("Validate() expected *errors.ErrInvalidConversion for mismatched target")
+	}
+}